@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,13 +11,17 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"stocky/internal/broker"
 	"stocky/internal/config"
 	"stocky/internal/database"
+	"stocky/internal/depository"
 	"stocky/internal/handler"
 	"stocky/internal/middleware"
+	"stocky/internal/outbox"
 	"stocky/internal/repository"
 	"stocky/internal/scheduler"
 	"stocky/internal/service"
+	"stocky/internal/webhook"
 )
 
 func main() {
@@ -40,13 +45,123 @@ func main() {
 	rewardRepo := repository.NewRewardRepository(db)
 	ledgerRepo := repository.NewLedgerRepository(db)
 	priceRepo := repository.NewStockPriceRepository(db)
+	vestingRepo := repository.NewVestingRepository(db)
+	approvalRepo := repository.NewApprovalRepository(db)
+	orderRepo := repository.NewOrderRepository(db)
+	inventoryRepo := repository.NewInventoryRepository(db)
+	rewardJobRepo := repository.NewRewardJobRepository(db)
+	deadLetterRepo := repository.NewDeadLetterRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	scheduleRepo := repository.NewScheduledRewardRepository(db)
+	offerRepo := repository.NewRewardOfferRepository(db)
+	transferRepo := repository.NewShareTransferRepository(db)
+	saleRepo := repository.NewShareSaleRepository(db)
+	withdrawalRepo := repository.NewDematWithdrawalRepository(db)
+	reconciliationRepo := repository.NewReconciliationRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	exportRepo := repository.NewLedgerExportRepository(db)
+	periodRepo := repository.NewAccountingPeriodRepository(db)
 
-	priceService := service.NewPriceService(priceRepo)
-	rewardService := service.NewRewardService(rewardRepo, ledgerRepo, userRepo, priceRepo, db)
-	portfolioService := service.NewPortfolioService(rewardRepo, priceRepo)
+	priceService := service.NewPriceService(priceRepo, outboxRepo, db)
+	approvalPolicy := service.ApprovalPolicy{
+		ThresholdINR: cfg.Approval.ThresholdINR,
+		Expiry:       cfg.Approval.Expiry,
+	}
+	inventoryPolicy := service.InventoryPolicy{
+		ReplenishThreshold: cfg.Inventory.ReplenishThreshold,
+		ReplenishQuantity:  cfg.Inventory.ReplenishQuantity,
+	}
+	timestampPolicy := service.TimestampPolicy{
+		MaxFutureSkew: cfg.Timestamp.MaxFutureSkew,
+		MaxBackdate:   cfg.Timestamp.MaxBackdate,
+		ClosedBefore:  cfg.Timestamp.ClosedBefore,
+	}
+	inventoryService := service.NewInventoryService(inventoryRepo, orderRepo, ledgerRepo, inventoryPolicy, db)
+	rewardService := service.NewRewardService(rewardRepo, ledgerRepo, userRepo, priceRepo, vestingRepo, approvalRepo, periodRepo, approvalPolicy, timestampPolicy, inventoryService, outboxRepo, db)
+	portfolioService := service.NewPortfolioService(rewardRepo, priceRepo, vestingRepo, offerRepo, ledgerRepo)
+	vestingService := service.NewVestingService(vestingRepo)
+	approvalService := service.NewApprovalService(approvalRepo, rewardRepo, rewardService, db)
+	settlementService := service.NewSettlementService(rewardRepo, ledgerRepo, inventoryService, outboxRepo, db)
+	queuePolicy := service.QueuePolicy{
+		MaxAttempts:       cfg.RewardQueue.MaxAttempts,
+		BaseBackoff:       cfg.RewardQueue.BaseBackoff,
+		MaxBackoff:        cfg.RewardQueue.MaxBackoff,
+		VisibilityTimeout: cfg.RewardQueue.VisibilityTimeout,
+	}
+	deadLetterService := service.NewDeadLetterService(deadLetterRepo, rewardService)
+	rewardQueueService := service.NewRewardQueueService(rewardJobRepo, rewardRepo, scheduleRepo, rewardService, deadLetterService, queuePolicy)
+	schedulePolicy := service.SchedulePolicy{
+		MaxHorizon:        cfg.Schedule.MaxHorizon,
+		BatchSize:         cfg.Schedule.BatchSize,
+		VisibilityTimeout: cfg.Schedule.VisibilityTimeout,
+	}
+	scheduledRewardService := service.NewScheduledRewardService(scheduleRepo, rewardRepo, rewardService, rewardQueueService, deadLetterService, schedulePolicy)
+	offerPolicy := service.OfferPolicy{
+		MaxClaimWindow: cfg.Offer.MaxClaimWindow,
+	}
+	rewardOfferService := service.NewRewardOfferService(offerRepo, rewardRepo, rewardService, offerPolicy, db)
+	transferService := service.NewShareTransferService(transferRepo, rewardRepo, userRepo, ledgerRepo, rewardService, outboxRepo, db)
+	saleService := service.NewShareSaleService(saleRepo, rewardRepo, ledgerRepo, rewardService, inventoryService, outboxRepo, db)
+
+	if cfg.Broker.Provider != "fake" {
+		logrus.WithField("provider", cfg.Broker.Provider).Fatal("Unsupported broker provider")
+	}
+	brokerClient := broker.NewFakeBroker(priceService.GetLatestPrice)
+	if cfg.Depository.Provider != "fake" {
+		logrus.WithField("provider", cfg.Depository.Provider).Fatal("Unsupported depository provider")
+	}
+	depositoryClient := depository.NewFakeDepository(cfg.Depository.FakeSettleAfter)
+	withdrawalService := service.NewDematWithdrawalService(withdrawalRepo, rewardRepo, ledgerRepo, rewardService, depositoryClient, outboxRepo, db)
 
-	rewardHandler := handler.NewRewardHandler(rewardService)
+	ledgerService := service.NewLedgerService(ledgerRepo)
+	reconciliationService := service.NewReconciliationService(reconciliationRepo, ledgerRepo, db)
+	reportService := service.NewReportService(reportRepo)
+	exportPolicy := service.ExportPolicy{
+		TallyCompany: cfg.Export.TallyCompany,
+		AccountMap:   cfg.Export.AccountMap,
+		LookbackDays: cfg.Export.LookbackDays,
+	}
+	exportService := service.NewLedgerExportService(exportRepo, reportRepo, exportPolicy)
+	periodService := service.NewAccountingPeriodService(periodRepo, ledgerRepo, db)
+	snapshotService := service.NewHoldingSnapshotService(ledgerRepo, cfg.Snapshot.LookbackDays, db)
+
+	orderService := service.NewOrderService(orderRepo, rewardRepo, ledgerRepo, settlementService, inventoryService, brokerClient, db)
+
+	outboxSinks, err := buildOutboxSinks(cfg.Outbox)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to configure outbox sinks")
+	}
+	webhookPolicy := service.WebhookPolicy{
+		MaxAttempts:  cfg.Webhook.MaxAttempts,
+		BaseBackoff:  cfg.Webhook.BaseBackoff,
+		MaxBackoff:   cfg.Webhook.MaxBackoff,
+		DisableAfter: cfg.Webhook.DisableAfter,
+		BatchSize:    cfg.Webhook.BatchSize,
+		LeaseTimeout: cfg.Webhook.LeaseTimeout,
+	}
+	webhookService := service.NewWebhookService(webhookRepo, webhook.NewClient(cfg.Webhook.Timeout), webhookPolicy, db)
+	outboxSinks = append(outboxSinks, webhookService)
+	outboxRelay := service.NewOutboxRelay(outboxRepo, outboxSinks, cfg.Outbox.BatchSize, cfg.Outbox.LeaseTimeout)
+
+	rewardHandler := handler.NewRewardHandler(rewardService, rewardQueueService, deadLetterService, cfg.RewardQueue.Async)
 	portfolioHandler := handler.NewPortfolioHandler(portfolioService)
+	approvalHandler := handler.NewApprovalHandler(approvalService)
+	settlementHandler := handler.NewSettlementHandler(settlementService)
+	orderHandler := handler.NewOrderHandler(orderService)
+	inventoryHandler := handler.NewInventoryHandler(inventoryService)
+	deadLetterHandler := handler.NewDeadLetterHandler(deadLetterService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	scheduledRewardHandler := handler.NewScheduledRewardHandler(scheduledRewardService)
+	rewardOfferHandler := handler.NewRewardOfferHandler(rewardOfferService)
+	transferHandler := handler.NewShareTransferHandler(transferService)
+	saleHandler := handler.NewShareSaleHandler(saleService)
+	withdrawalHandler := handler.NewDematWithdrawalHandler(withdrawalService)
+	ledgerHandler := handler.NewLedgerHandler(ledgerService)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationService)
+	reportHandler := handler.NewReportHandler(reportService)
+	exportHandler := handler.NewLedgerExportHandler(exportService)
+	periodHandler := handler.NewAccountingPeriodHandler(periodService)
 
 	router := gin.New()
 	router.Use(gin.Recovery())
@@ -55,10 +170,71 @@ func main() {
 	api := router.Group("/api/v1")
 	{
 		api.POST("/reward", rewardHandler.CreateReward)
+		api.POST("/rewards/scheduled", scheduledRewardHandler.ScheduleReward)
+		api.POST("/rewards/offers", rewardOfferHandler.OfferReward)
+		api.GET("/rewards/:eventId/status", rewardHandler.GetRewardStatus)
+		api.POST("/rewards/:eventId/cancel", scheduledRewardHandler.CancelScheduledReward)
+		api.POST("/rewards/:eventId/claim", rewardOfferHandler.ClaimReward)
+		api.POST("/rewards/:eventId/reverse", rewardHandler.ReverseReward)
+		api.POST("/rewards/:eventId/order", settlementHandler.MarkOrdered)
+		api.POST("/rewards/:eventId/settle", settlementHandler.MarkSettled)
+		api.POST("/rewards/:eventId/fail", settlementHandler.MarkFailed)
 		api.GET("/today-stocks/:userId", portfolioHandler.GetTodayStocks)
 		api.GET("/historical-inr/:userId", portfolioHandler.GetHistoricalINR)
 		api.GET("/stats/:userId", portfolioHandler.GetStats)
 		api.GET("/portfolio/:userId", portfolioHandler.GetPortfolio)
+		api.POST("/transfers", transferHandler.CreateTransfer)
+		api.GET("/transfers/:userId", transferHandler.ListTransfers)
+		api.POST("/sales", saleHandler.Sell)
+		api.GET("/sales/:userId", saleHandler.ListSales)
+		api.GET("/wallet/:userId", saleHandler.GetWallet)
+		api.POST("/withdrawals", withdrawalHandler.RequestWithdrawal)
+		api.GET("/withdrawals", withdrawalHandler.ListWithdrawals)
+		api.GET("/withdrawals/:id", withdrawalHandler.GetWithdrawal)
+		api.GET("/users/:userId/statement", ledgerHandler.GetStatement)
+		api.GET("/broker-orders", orderHandler.ListOrders)
+		api.POST("/broker-orders/run", orderHandler.RunOrderCycle)
+		api.GET("/inventory", inventoryHandler.ListPools)
+		api.PUT("/inventory/:symbol", inventoryHandler.UpdatePool)
+		api.GET("/approvals", approvalHandler.ListApprovals)
+		api.POST("/approvals/:eventId/approve", approvalHandler.Approve)
+		api.POST("/approvals/:eventId/reject", approvalHandler.Reject)
+
+		admin := api.Group("/admin")
+		{
+			admin.GET("/dead-letters", deadLetterHandler.ListDeadLetters)
+			admin.POST("/dead-letters/replay", deadLetterHandler.ReplayByErrorClass)
+			admin.GET("/dead-letters/:id", deadLetterHandler.GetDeadLetter)
+			admin.PUT("/dead-letters/:id", deadLetterHandler.UpdateDeadLetter)
+			admin.POST("/dead-letters/:id/replay", deadLetterHandler.ReplayDeadLetter)
+			admin.POST("/webhooks", webhookHandler.CreateWebhook)
+			admin.GET("/webhooks", webhookHandler.ListWebhooks)
+			admin.GET("/webhooks/:id", webhookHandler.GetWebhook)
+			admin.PUT("/webhooks/:id", webhookHandler.UpdateWebhook)
+			admin.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+			admin.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+			admin.GET("/webhook-deliveries/:id", webhookHandler.GetDelivery)
+			admin.POST("/webhook-deliveries/:id/redeliver", webhookHandler.Redeliver)
+			admin.POST("/campaigns", rewardOfferHandler.CreateCampaign)
+			admin.GET("/campaigns", rewardOfferHandler.ListCampaigns)
+			admin.GET("/campaigns/:id", rewardOfferHandler.GetCampaign)
+			admin.GET("/accounts", ledgerHandler.ListAccounts)
+			admin.GET("/ledger/verify", ledgerHandler.VerifyChain)
+			admin.GET("/reconciliation/latest", reconciliationHandler.GetLatest)
+			admin.POST("/reconciliation/run", reconciliationHandler.Run)
+			admin.GET("/reports/trial-balance", reportHandler.TrialBalance)
+			admin.GET("/reports/general-ledger", reportHandler.GeneralLedger)
+			admin.GET("/reports/account-activity", reportHandler.AccountActivity)
+			admin.POST("/exports", exportHandler.CreateExport)
+			admin.GET("/exports", exportHandler.ListExports)
+			admin.GET("/exports/:id/download", exportHandler.DownloadExport)
+			admin.POST("/exports/:id/imported", exportHandler.MarkImported)
+			admin.GET("/periods", periodHandler.ListPeriods)
+			admin.GET("/periods/:period", periodHandler.GetPeriod)
+			admin.POST("/periods/:period/close", periodHandler.ClosePeriod)
+			admin.POST("/periods/:period/lock", periodHandler.LockPeriod)
+			admin.POST("/periods/:period/reopen", periodHandler.ReopenPeriod)
+		}
 	}
 
 	router.GET("/health", func(c *gin.Context) {
@@ -71,6 +247,45 @@ func main() {
 	priceFetcher := scheduler.NewPriceFetcher(priceService, cfg.PriceService.FetchInterval)
 	go priceFetcher.Start(ctx)
 
+	vestingJob := scheduler.NewPeriodicJob("vesting", cfg.Vesting.Interval, vestingService.VestDueTranches)
+	go vestingJob.Start(ctx)
+
+	approvalExpiryJob := scheduler.NewPeriodicJob("approval-expiry", cfg.Approval.ExpiryJobInterval, approvalService.ExpirePending)
+	go approvalExpiryJob.Start(ctx)
+
+	settlementJob := scheduler.NewPeriodicJob("settlement", cfg.Settlement.Interval, settlementService.SettleDue)
+	go settlementJob.Start(ctx)
+
+	rewardWorkers := scheduler.NewRewardWorkerPool(rewardQueueService, cfg.RewardQueue.Workers, cfg.RewardQueue.PollInterval)
+	go rewardWorkers.Start(ctx)
+
+	orderJob := scheduler.NewPeriodicJob("broker-orders", cfg.Broker.OrderInterval, orderService.RunOrderCycle)
+	go orderJob.Start(ctx)
+
+	outboxJob := scheduler.NewPeriodicJob("outbox-relay", cfg.Outbox.RelayInterval, outboxRelay.Relay)
+	go outboxJob.Start(ctx)
+
+	scheduledRewardJob := scheduler.NewPeriodicJob("scheduled-rewards", cfg.Schedule.Interval, scheduledRewardService.ExecuteDue)
+	go scheduledRewardJob.Start(ctx)
+
+	offerExpiryJob := scheduler.NewPeriodicJob("offer-expiry", cfg.Offer.ExpiryInterval, rewardOfferService.ExpireOffers)
+	go offerExpiryJob.Start(ctx)
+
+	withdrawalJob := scheduler.NewPeriodicJob("demat-withdrawals", cfg.Depository.WithdrawalInterval, withdrawalService.ProcessWithdrawals)
+	go withdrawalJob.Start(ctx)
+
+	reconciliationJob := scheduler.NewPeriodicJob("reconciliation", cfg.Reconciliation.Interval, reconciliationService.Reconcile)
+	go reconciliationJob.Start(ctx)
+
+	exportJob := scheduler.NewPeriodicJob("ledger-export", cfg.Export.Interval, exportService.ExportDue)
+	go exportJob.Start(ctx)
+
+	snapshotJob := scheduler.NewPeriodicJob("holding-snapshot", cfg.Snapshot.Interval, snapshotService.SnapshotDue)
+	go snapshotJob.Start(ctx)
+
+	webhookJob := scheduler.NewPeriodicJob("webhook-delivery", cfg.Webhook.DeliveryInterval, webhookService.DeliverDue)
+	go webhookJob.Start(ctx)
+
 	srv := &http.Server{
 		Addr:    ":" + cfg.Server.Port,
 		Handler: router,
@@ -100,3 +315,26 @@ func main() {
 	logrus.Info("Server exited")
 }
 
+func buildOutboxSinks(cfg config.OutboxConfig) ([]outbox.Sink, error) {
+	var sinks []outbox.Sink
+	for _, name := range cfg.Sinks {
+		switch name {
+		case "stdout":
+			sinks = append(sinks, outbox.NewStdoutSink())
+		case "file":
+			sink, err := outbox.NewFileSink(cfg.FilePath)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case "http":
+			if cfg.HTTPURL == "" {
+				return nil, fmt.Errorf("OUTBOX_HTTP_URL is required for the http sink")
+			}
+			sinks = append(sinks, outbox.NewHTTPSink(cfg.HTTPURL, cfg.HTTPTimeout))
+		default:
+			return nil, fmt.Errorf("unsupported outbox sink %q", name)
+		}
+	}
+	return sinks, nil
+}
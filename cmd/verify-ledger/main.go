@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"stocky/internal/config"
+	"stocky/internal/database"
+	"stocky/internal/repository"
+	"stocky/internal/service"
+)
+
+func main() {
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+
+	cfg, err := config.Load()
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to load configuration")
+	}
+
+	db, err := database.NewPostgres(cfg.Database.DSN())
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to connect to database")
+	}
+	defer db.Close()
+
+	ledgerService := service.NewLedgerService(repository.NewLedgerRepository(db))
+	result, err := ledgerService.VerifyChain(context.Background())
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to verify ledger chain")
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		logrus.WithError(err).Fatal("Failed to write result")
+	}
+	if !result.Valid {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"stocky/internal/webhook"
+)
+
+func main() {
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+
+	addr := getEnv("RECEIVER_ADDR", ":9090")
+	secret := os.Getenv("WEBHOOK_SECRET")
+	if secret == "" {
+		logrus.Fatal("WEBHOOK_SECRET is required")
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+
+		log := logrus.WithFields(logrus.Fields{
+			"event":    r.Header.Get(webhook.EventHeader),
+			"delivery": r.Header.Get(webhook.DeliveryHeader),
+		})
+
+		if err := webhook.Verify(secret, r.Header.Get(webhook.SignatureHeader), body, 5*time.Minute, time.Now()); err != nil {
+			log.WithError(err).Warn("Rejected webhook")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		log.WithField("body", string(body)).Info("Received webhook")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	logrus.WithField("addr", addr).Info("Webhook receiver listening")
+	if err := http.ListenAndServe(addr, nil); err != nil {
+		logrus.WithError(err).Fatal("Webhook receiver stopped")
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
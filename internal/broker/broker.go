@@ -0,0 +1,37 @@
+package broker
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"stocky/pkg/fees"
+)
+
+type Side string
+
+const (
+	SideBuy  Side = "BUY"
+	SideSell Side = "SELL"
+)
+
+type Order struct {
+	ClientOrderID uuid.UUID
+	Symbol        string
+	Side          Side
+	Quantity      int64
+}
+
+type Fill struct {
+	FillID     string
+	Quantity   decimal.Decimal
+	Price      decimal.Decimal
+	Fees       fees.Breakdown
+	ExecutedAt time.Time
+}
+
+type Broker interface {
+	PlaceOrder(ctx context.Context, order Order) (string, error)
+	GetFills(ctx context.Context, brokerOrderID string) ([]Fill, error)
+}
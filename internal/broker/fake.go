@@ -0,0 +1,68 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"stocky/pkg/fees"
+)
+
+type PriceSource func(ctx context.Context, symbol string) (decimal.Decimal, error)
+
+type FakeBroker struct {
+	prices PriceSource
+	mu     sync.Mutex
+	fills  map[string][]Fill
+}
+
+func NewFakeBroker(prices PriceSource) *FakeBroker {
+	return &FakeBroker{
+		prices: prices,
+		fills:  make(map[string][]Fill),
+	}
+}
+
+func (b *FakeBroker) PlaceOrder(ctx context.Context, order Order) (string, error) {
+	if order.Quantity <= 0 {
+		return "", fmt.Errorf("invalid order quantity %d", order.Quantity)
+	}
+
+	price, err := b.prices(ctx, order.Symbol)
+	if err != nil {
+		return "", fmt.Errorf("no market price for %s: %w", order.Symbol, err)
+	}
+
+	slippage := decimal.NewFromFloat(rand.Float64()*0.01 - 0.005)
+	execPrice := price.Mul(decimal.NewFromInt(1).Add(slippage)).Round(2)
+	quantity := decimal.NewFromInt(order.Quantity)
+
+	brokerOrderID := "FAKE-" + uuid.NewString()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fills[brokerOrderID] = []Fill{
+		{
+			FillID:     brokerOrderID + "-1",
+			Quantity:   quantity,
+			Price:      execPrice,
+			Fees:       fees.CalculateFees(execPrice, quantity),
+			ExecutedAt: time.Now(),
+		},
+	}
+	return brokerOrderID, nil
+}
+
+func (b *FakeBroker) GetFills(ctx context.Context, brokerOrderID string) ([]Fill, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	fills, ok := b.fills[brokerOrderID]
+	if !ok {
+		return nil, fmt.Errorf("unknown broker order %s", brokerOrderID)
+	}
+	return append([]Fill(nil), fills...), nil
+}
@@ -3,16 +3,34 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 )
 
 type Config struct {
-	Server       ServerConfig
-	Database     DatabaseConfig
-	PriceService PriceServiceConfig
+	Server         ServerConfig
+	Database       DatabaseConfig
+	PriceService   PriceServiceConfig
+	Vesting        VestingConfig
+	Approval       ApprovalConfig
+	Settlement     SettlementConfig
+	Broker         BrokerConfig
+	Inventory      InventoryConfig
+	RewardQueue    RewardQueueConfig
+	Outbox         OutboxConfig
+	Webhook        WebhookConfig
+	Timestamp      TimestampConfig
+	Schedule       ScheduleConfig
+	Offer          OfferConfig
+	Depository     DepositoryConfig
+	Reconciliation ReconciliationConfig
+	Export         ExportConfig
+	Snapshot       SnapshotConfig
 }
 
 type ServerConfig struct {
@@ -34,6 +52,101 @@ type PriceServiceConfig struct {
 	FetchInterval time.Duration
 }
 
+type VestingConfig struct {
+	Interval time.Duration
+}
+
+type SettlementConfig struct {
+	Interval time.Duration
+}
+
+type BrokerConfig struct {
+	Provider      string
+	OrderInterval time.Duration
+}
+
+type InventoryConfig struct {
+	ReplenishThreshold decimal.Decimal
+	ReplenishQuantity  int64
+}
+
+type RewardQueueConfig struct {
+	Async             bool
+	Workers           int
+	MaxAttempts       int
+	BaseBackoff       time.Duration
+	MaxBackoff        time.Duration
+	PollInterval      time.Duration
+	VisibilityTimeout time.Duration
+}
+
+type OutboxConfig struct {
+	Sinks         []string
+	FilePath      string
+	HTTPURL       string
+	HTTPTimeout   time.Duration
+	BatchSize     int
+	RelayInterval time.Duration
+	LeaseTimeout  time.Duration
+}
+
+type WebhookConfig struct {
+	DeliveryInterval time.Duration
+	BatchSize        int
+	Timeout          time.Duration
+	MaxAttempts      int
+	BaseBackoff      time.Duration
+	MaxBackoff       time.Duration
+	DisableAfter     int
+	LeaseTimeout     time.Duration
+}
+
+type ScheduleConfig struct {
+	Interval          time.Duration
+	MaxHorizon        time.Duration
+	BatchSize         int
+	VisibilityTimeout time.Duration
+}
+
+type OfferConfig struct {
+	MaxClaimWindow time.Duration
+	ExpiryInterval time.Duration
+}
+
+type DepositoryConfig struct {
+	Provider           string
+	WithdrawalInterval time.Duration
+	FakeSettleAfter    time.Duration
+}
+
+type ReconciliationConfig struct {
+	Interval time.Duration
+}
+
+type ExportConfig struct {
+	Interval     time.Duration
+	LookbackDays int
+	TallyCompany string
+	AccountMap   map[string]string
+}
+
+type SnapshotConfig struct {
+	Interval     time.Duration
+	LookbackDays int
+}
+
+type TimestampConfig struct {
+	MaxFutureSkew time.Duration
+	MaxBackdate   time.Duration
+	ClosedBefore  *time.Time
+}
+
+type ApprovalConfig struct {
+	ThresholdINR      decimal.Decimal
+	Expiry            time.Duration
+	ExpiryJobInterval time.Duration
+}
+
 func Load() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		logrus.Warn("No .env file found, using environment variables")
@@ -45,6 +158,96 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid PRICE_FETCH_INTERVAL: %w", err)
 	}
 
+	vestingInterval, err := time.ParseDuration(getEnv("VESTING_INTERVAL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid VESTING_INTERVAL: %w", err)
+	}
+
+	approvalThreshold, err := decimal.NewFromString(getEnv("APPROVAL_THRESHOLD_INR", "100000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid APPROVAL_THRESHOLD_INR: %w", err)
+	}
+
+	approvalExpiry, err := time.ParseDuration(getEnv("APPROVAL_EXPIRY", "72h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid APPROVAL_EXPIRY: %w", err)
+	}
+
+	approvalExpiryInterval, err := time.ParseDuration(getEnv("APPROVAL_EXPIRY_INTERVAL", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid APPROVAL_EXPIRY_INTERVAL: %w", err)
+	}
+
+	settlementInterval, err := time.ParseDuration(getEnv("SETTLEMENT_INTERVAL", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SETTLEMENT_INTERVAL: %w", err)
+	}
+
+	orderInterval, err := time.ParseDuration(getEnv("BROKER_ORDER_INTERVAL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BROKER_ORDER_INTERVAL: %w", err)
+	}
+
+	replenishThreshold, err := decimal.NewFromString(getEnv("INVENTORY_REPLENISH_THRESHOLD", "1"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INVENTORY_REPLENISH_THRESHOLD: %w", err)
+	}
+
+	replenishQuantity, err := strconv.ParseInt(getEnv("INVENTORY_REPLENISH_QUANTITY", "0"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid INVENTORY_REPLENISH_QUANTITY: %w", err)
+	}
+
+	rewardQueue, err := loadRewardQueueConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	outbox, err := loadOutboxConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := loadWebhookConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp, err := loadTimestampConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := loadScheduleConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	offer, err := loadOfferConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	depository, err := loadDepositoryConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliation, err := loadReconciliationConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := loadExportConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := loadSnapshotConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		Server: ServerConfig{
 			Port:    getEnv("PORT", "8080"),
@@ -62,18 +265,253 @@ func Load() (*Config, error) {
 			APIURL:        getEnv("PRICE_API_URL", ""),
 			FetchInterval: priceInterval,
 		},
+		Vesting: VestingConfig{
+			Interval: vestingInterval,
+		},
+		Approval: ApprovalConfig{
+			ThresholdINR:      approvalThreshold,
+			Expiry:            approvalExpiry,
+			ExpiryJobInterval: approvalExpiryInterval,
+		},
+		Settlement: SettlementConfig{
+			Interval: settlementInterval,
+		},
+		Broker: BrokerConfig{
+			Provider:      getEnv("BROKER_PROVIDER", "fake"),
+			OrderInterval: orderInterval,
+		},
+		Inventory: InventoryConfig{
+			ReplenishThreshold: replenishThreshold,
+			ReplenishQuantity:  replenishQuantity,
+		},
+		RewardQueue:    rewardQueue,
+		Outbox:         outbox,
+		Webhook:        webhook,
+		Timestamp:      timestamp,
+		Schedule:       schedule,
+		Offer:          offer,
+		Depository:     depository,
+		Reconciliation: reconciliation,
+		Export:         export,
+		Snapshot:       snapshot,
 	}, nil
 }
 
+func loadRewardQueueConfig() (RewardQueueConfig, error) {
+	cfg := RewardQueueConfig{}
+	var err error
+
+	if cfg.Async, err = strconv.ParseBool(getEnv("REWARD_ASYNC", "false")); err != nil {
+		return cfg, fmt.Errorf("invalid REWARD_ASYNC: %w", err)
+	}
+	if cfg.Workers, err = strconv.Atoi(getEnv("REWARD_WORKERS", "4")); err != nil {
+		return cfg, fmt.Errorf("invalid REWARD_WORKERS: %w", err)
+	}
+	if cfg.MaxAttempts, err = strconv.Atoi(getEnv("REWARD_MAX_ATTEMPTS", "5")); err != nil {
+		return cfg, fmt.Errorf("invalid REWARD_MAX_ATTEMPTS: %w", err)
+	}
+	if cfg.BaseBackoff, err = time.ParseDuration(getEnv("REWARD_RETRY_BASE_BACKOFF", "2s")); err != nil {
+		return cfg, fmt.Errorf("invalid REWARD_RETRY_BASE_BACKOFF: %w", err)
+	}
+	if cfg.MaxBackoff, err = time.ParseDuration(getEnv("REWARD_RETRY_MAX_BACKOFF", "5m")); err != nil {
+		return cfg, fmt.Errorf("invalid REWARD_RETRY_MAX_BACKOFF: %w", err)
+	}
+	if cfg.PollInterval, err = time.ParseDuration(getEnv("REWARD_QUEUE_POLL_INTERVAL", "1s")); err != nil {
+		return cfg, fmt.Errorf("invalid REWARD_QUEUE_POLL_INTERVAL: %w", err)
+	}
+	if cfg.VisibilityTimeout, err = time.ParseDuration(getEnv("REWARD_QUEUE_VISIBILITY_TIMEOUT", "5m")); err != nil {
+		return cfg, fmt.Errorf("invalid REWARD_QUEUE_VISIBILITY_TIMEOUT: %w", err)
+	}
+
+	return cfg, nil
+}
+
 func (c *DatabaseConfig) DSN() string {
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode)
 }
 
+func loadOutboxConfig() (OutboxConfig, error) {
+	cfg := OutboxConfig{
+		FilePath: getEnv("OUTBOX_FILE_PATH", "outbox.log"),
+		HTTPURL:  getEnv("OUTBOX_HTTP_URL", ""),
+	}
+	var err error
+
+	for _, sink := range strings.Split(getEnv("OUTBOX_SINKS", "stdout"), ",") {
+		if sink = strings.TrimSpace(sink); sink != "" {
+			cfg.Sinks = append(cfg.Sinks, sink)
+		}
+	}
+	if cfg.HTTPTimeout, err = time.ParseDuration(getEnv("OUTBOX_HTTP_TIMEOUT", "5s")); err != nil {
+		return cfg, fmt.Errorf("invalid OUTBOX_HTTP_TIMEOUT: %w", err)
+	}
+	if cfg.BatchSize, err = strconv.Atoi(getEnv("OUTBOX_BATCH_SIZE", "100")); err != nil {
+		return cfg, fmt.Errorf("invalid OUTBOX_BATCH_SIZE: %w", err)
+	}
+	if cfg.RelayInterval, err = time.ParseDuration(getEnv("OUTBOX_RELAY_INTERVAL", "5s")); err != nil {
+		return cfg, fmt.Errorf("invalid OUTBOX_RELAY_INTERVAL: %w", err)
+	}
+	if cfg.LeaseTimeout, err = time.ParseDuration(getEnv("OUTBOX_LEASE_TIMEOUT", "1m")); err != nil {
+		return cfg, fmt.Errorf("invalid OUTBOX_LEASE_TIMEOUT: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func loadWebhookConfig() (WebhookConfig, error) {
+	cfg := WebhookConfig{}
+	var err error
+
+	if cfg.DeliveryInterval, err = time.ParseDuration(getEnv("WEBHOOK_DELIVERY_INTERVAL", "5s")); err != nil {
+		return cfg, fmt.Errorf("invalid WEBHOOK_DELIVERY_INTERVAL: %w", err)
+	}
+	if cfg.BatchSize, err = strconv.Atoi(getEnv("WEBHOOK_BATCH_SIZE", "50")); err != nil {
+		return cfg, fmt.Errorf("invalid WEBHOOK_BATCH_SIZE: %w", err)
+	}
+	if cfg.Timeout, err = time.ParseDuration(getEnv("WEBHOOK_TIMEOUT", "10s")); err != nil {
+		return cfg, fmt.Errorf("invalid WEBHOOK_TIMEOUT: %w", err)
+	}
+	if cfg.MaxAttempts, err = strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", "8")); err != nil {
+		return cfg, fmt.Errorf("invalid WEBHOOK_MAX_ATTEMPTS: %w", err)
+	}
+	if cfg.BaseBackoff, err = time.ParseDuration(getEnv("WEBHOOK_RETRY_BASE_BACKOFF", "30s")); err != nil {
+		return cfg, fmt.Errorf("invalid WEBHOOK_RETRY_BASE_BACKOFF: %w", err)
+	}
+	if cfg.MaxBackoff, err = time.ParseDuration(getEnv("WEBHOOK_RETRY_MAX_BACKOFF", "1h")); err != nil {
+		return cfg, fmt.Errorf("invalid WEBHOOK_RETRY_MAX_BACKOFF: %w", err)
+	}
+	if cfg.DisableAfter, err = strconv.Atoi(getEnv("WEBHOOK_DISABLE_AFTER", "20")); err != nil {
+		return cfg, fmt.Errorf("invalid WEBHOOK_DISABLE_AFTER: %w", err)
+	}
+	if cfg.LeaseTimeout, err = time.ParseDuration(getEnv("WEBHOOK_LEASE_TIMEOUT", "10m")); err != nil {
+		return cfg, fmt.Errorf("invalid WEBHOOK_LEASE_TIMEOUT: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func loadTimestampConfig() (TimestampConfig, error) {
+	cfg := TimestampConfig{}
+	var err error
+
+	if cfg.MaxFutureSkew, err = time.ParseDuration(getEnv("REWARD_MAX_FUTURE_SKEW", "5m")); err != nil {
+		return cfg, fmt.Errorf("invalid REWARD_MAX_FUTURE_SKEW: %w", err)
+	}
+	if cfg.MaxBackdate, err = time.ParseDuration(getEnv("REWARD_MAX_BACKDATE", "720h")); err != nil {
+		return cfg, fmt.Errorf("invalid REWARD_MAX_BACKDATE: %w", err)
+	}
+	if closedBefore := getEnv("REWARD_CLOSED_BEFORE", ""); closedBefore != "" {
+		date, err := time.Parse("2006-01-02", closedBefore)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid REWARD_CLOSED_BEFORE: %w", err)
+		}
+		cfg.ClosedBefore = &date
+	}
+
+	return cfg, nil
+}
+
+func loadScheduleConfig() (ScheduleConfig, error) {
+	cfg := ScheduleConfig{}
+	var err error
+
+	if cfg.Interval, err = time.ParseDuration(getEnv("SCHEDULED_REWARD_INTERVAL", "1m")); err != nil {
+		return cfg, fmt.Errorf("invalid SCHEDULED_REWARD_INTERVAL: %w", err)
+	}
+	if cfg.MaxHorizon, err = time.ParseDuration(getEnv("REWARD_SCHEDULE_MAX_HORIZON", "8760h")); err != nil {
+		return cfg, fmt.Errorf("invalid REWARD_SCHEDULE_MAX_HORIZON: %w", err)
+	}
+	if cfg.BatchSize, err = strconv.Atoi(getEnv("SCHEDULED_REWARD_BATCH_SIZE", "100")); err != nil {
+		return cfg, fmt.Errorf("invalid SCHEDULED_REWARD_BATCH_SIZE: %w", err)
+	}
+	if cfg.VisibilityTimeout, err = time.ParseDuration(getEnv("SCHEDULED_REWARD_VISIBILITY_TIMEOUT", "5m")); err != nil {
+		return cfg, fmt.Errorf("invalid SCHEDULED_REWARD_VISIBILITY_TIMEOUT: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func loadOfferConfig() (OfferConfig, error) {
+	cfg := OfferConfig{}
+	var err error
+
+	if cfg.MaxClaimWindow, err = time.ParseDuration(getEnv("REWARD_OFFER_MAX_CLAIM_WINDOW", "720h")); err != nil {
+		return cfg, fmt.Errorf("invalid REWARD_OFFER_MAX_CLAIM_WINDOW: %w", err)
+	}
+	if cfg.ExpiryInterval, err = time.ParseDuration(getEnv("REWARD_OFFER_EXPIRY_INTERVAL", "1m")); err != nil {
+		return cfg, fmt.Errorf("invalid REWARD_OFFER_EXPIRY_INTERVAL: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func loadDepositoryConfig() (DepositoryConfig, error) {
+	cfg := DepositoryConfig{Provider: getEnv("DEPOSITORY_PROVIDER", "fake")}
+	var err error
+
+	if cfg.WithdrawalInterval, err = time.ParseDuration(getEnv("WITHDRAWAL_INTERVAL", "1m")); err != nil {
+		return cfg, fmt.Errorf("invalid WITHDRAWAL_INTERVAL: %w", err)
+	}
+	if cfg.FakeSettleAfter, err = time.ParseDuration(getEnv("FAKE_DEPOSITORY_SETTLE_AFTER", "2m")); err != nil {
+		return cfg, fmt.Errorf("invalid FAKE_DEPOSITORY_SETTLE_AFTER: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func loadReconciliationConfig() (ReconciliationConfig, error) {
+	interval, err := time.ParseDuration(getEnv("RECONCILIATION_INTERVAL", "1h"))
+	if err != nil {
+		return ReconciliationConfig{}, fmt.Errorf("invalid RECONCILIATION_INTERVAL: %w", err)
+	}
+	return ReconciliationConfig{Interval: interval}, nil
+}
+
+func loadExportConfig() (ExportConfig, error) {
+	cfg := ExportConfig{
+		TallyCompany: getEnv("EXPORT_TALLY_COMPANY", "Stocky"),
+		AccountMap:   make(map[string]string),
+	}
+	var err error
+
+	if cfg.Interval, err = time.ParseDuration(getEnv("EXPORT_INTERVAL", "1h")); err != nil {
+		return cfg, fmt.Errorf("invalid EXPORT_INTERVAL: %w", err)
+	}
+	if cfg.LookbackDays, err = strconv.Atoi(getEnv("EXPORT_LOOKBACK_DAYS", "7")); err != nil {
+		return cfg, fmt.Errorf("invalid EXPORT_LOOKBACK_DAYS: %w", err)
+	}
+	for _, pair := range strings.Split(getEnv("EXPORT_ACCOUNT_MAP", ""), ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		prefix, ledger, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(prefix) == "" || strings.TrimSpace(ledger) == "" {
+			return cfg, fmt.Errorf("invalid EXPORT_ACCOUNT_MAP entry %q: expected ACCOUNT_PREFIX=Ledger Name", pair)
+		}
+		cfg.AccountMap[strings.TrimSpace(prefix)] = strings.TrimSpace(ledger)
+	}
+
+	return cfg, nil
+}
+
+func loadSnapshotConfig() (SnapshotConfig, error) {
+	var cfg SnapshotConfig
+	var err error
+
+	if cfg.Interval, err = time.ParseDuration(getEnv("HOLDING_SNAPSHOT_INTERVAL", "1h")); err != nil {
+		return cfg, fmt.Errorf("invalid HOLDING_SNAPSHOT_INTERVAL: %w", err)
+	}
+	if cfg.LookbackDays, err = strconv.Atoi(getEnv("HOLDING_SNAPSHOT_LOOKBACK_DAYS", "30")); err != nil {
+		return cfg, fmt.Errorf("invalid HOLDING_SNAPSHOT_LOOKBACK_DAYS: %w", err)
+	}
+
+	return cfg, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
-
@@ -0,0 +1,33 @@
+package depository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type TransferStatus string
+
+const (
+	TransferStatusPending   TransferStatus = "PENDING"
+	TransferStatusCompleted TransferStatus = "COMPLETED"
+	TransferStatusFailed    TransferStatus = "FAILED"
+)
+
+type TransferInstruction struct {
+	RequestID uuid.UUID
+	Symbol    string
+	Quantity  int64
+	DPID      string
+	ClientID  string
+}
+
+type TransferResult struct {
+	Status TransferStatus
+	Reason string
+}
+
+type Depository interface {
+	InitiateTransfer(ctx context.Context, instruction TransferInstruction) (string, error)
+	GetTransferStatus(ctx context.Context, reference string) (TransferResult, error)
+}
@@ -0,0 +1,72 @@
+package depository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const FakeRejectedClientID = "00000000"
+
+type fakeTransfer struct {
+	instruction TransferInstruction
+	reference   string
+	initiatedAt time.Time
+}
+
+type FakeDepository struct {
+	settleAfter time.Duration
+	mu          sync.Mutex
+	byRequest   map[uuid.UUID]*fakeTransfer
+	byReference map[string]*fakeTransfer
+}
+
+func NewFakeDepository(settleAfter time.Duration) *FakeDepository {
+	return &FakeDepository{
+		settleAfter: settleAfter,
+		byRequest:   make(map[uuid.UUID]*fakeTransfer),
+		byReference: make(map[string]*fakeTransfer),
+	}
+}
+
+func (d *FakeDepository) InitiateTransfer(ctx context.Context, instruction TransferInstruction) (string, error) {
+	if instruction.Quantity <= 0 {
+		return "", fmt.Errorf("invalid transfer quantity %d", instruction.Quantity)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if existing, ok := d.byRequest[instruction.RequestID]; ok {
+		return existing.reference, nil
+	}
+
+	transfer := &fakeTransfer{
+		instruction: instruction,
+		reference:   "FAKE-DIS-" + uuid.NewString(),
+		initiatedAt: time.Now(),
+	}
+	d.byRequest[instruction.RequestID] = transfer
+	d.byReference[transfer.reference] = transfer
+	return transfer.reference, nil
+}
+
+func (d *FakeDepository) GetTransferStatus(ctx context.Context, reference string) (TransferResult, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	transfer, ok := d.byReference[reference]
+	if !ok {
+		return TransferResult{}, fmt.Errorf("unknown depository transfer %s", reference)
+	}
+	if transfer.instruction.ClientID == FakeRejectedClientID {
+		return TransferResult{Status: TransferStatusFailed, Reason: "beneficiary account not found"}, nil
+	}
+	if time.Since(transfer.initiatedAt) < d.settleAfter {
+		return TransferResult{Status: TransferStatusPending}, nil
+	}
+	return TransferResult{Status: TransferStatusCompleted}, nil
+}
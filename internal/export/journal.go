@@ -0,0 +1,114 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Line struct {
+	Ledger string
+	Debit  decimal.Decimal
+	Credit decimal.Decimal
+}
+
+type Voucher struct {
+	Number    string
+	Date      time.Time
+	Narration string
+	Lines     []Line
+}
+
+type tallyEnvelope struct {
+	XMLName      xml.Name        `xml:"ENVELOPE"`
+	TallyRequest string          `xml:"HEADER>TALLYREQUEST"`
+	ImportData   tallyImportData `xml:"BODY>IMPORTDATA"`
+}
+
+type tallyImportData struct {
+	ReportName string         `xml:"REQUESTDESC>REPORTNAME"`
+	Company    string         `xml:"REQUESTDESC>STATICVARIABLES>SVCURRENTCOMPANY"`
+	Messages   []tallyMessage `xml:"REQUESTDATA>TALLYMESSAGE"`
+}
+
+type tallyMessage struct {
+	Voucher tallyVoucher `xml:"VOUCHER"`
+}
+
+type tallyVoucher struct {
+	VoucherType     string             `xml:"VCHTYPE,attr"`
+	Action          string             `xml:"ACTION,attr"`
+	Date            string             `xml:"DATE"`
+	VoucherTypeName string             `xml:"VOUCHERTYPENAME"`
+	VoucherNumber   string             `xml:"VOUCHERNUMBER"`
+	Narration       string             `xml:"NARRATION"`
+	Entries         []tallyLedgerEntry `xml:"ALLLEDGERENTRIES.LIST"`
+}
+
+type tallyLedgerEntry struct {
+	LedgerName       string `xml:"LEDGERNAME"`
+	IsDeemedPositive string `xml:"ISDEEMEDPOSITIVE"`
+	Amount           string `xml:"AMOUNT"`
+}
+
+func TallyXML(company string, vouchers []Voucher) ([]byte, error) {
+	envelope := tallyEnvelope{
+		TallyRequest: "Import Data",
+		ImportData: tallyImportData{
+			ReportName: "Vouchers",
+			Company:    company,
+			Messages:   make([]tallyMessage, len(vouchers)),
+		},
+	}
+
+	for i, v := range vouchers {
+		voucher := tallyVoucher{
+			VoucherType:     "Journal",
+			Action:          "Create",
+			Date:            v.Date.Format("20060102"),
+			VoucherTypeName: "Journal",
+			VoucherNumber:   v.Number,
+			Narration:       v.Narration,
+		}
+		for _, l := range v.Lines {
+			entry := tallyLedgerEntry{LedgerName: l.Ledger, IsDeemedPositive: "No", Amount: l.Credit.String()}
+			if l.Debit.IsPositive() {
+				entry.IsDeemedPositive = "Yes"
+				entry.Amount = l.Debit.Neg().String()
+			}
+			voucher.Entries = append(voucher.Entries, entry)
+		}
+		envelope.ImportData.Messages[i] = tallyMessage{Voucher: voucher}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(envelope); err != nil {
+		return nil, fmt.Errorf("failed to encode tally xml: %w", err)
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+func JournalCSV(vouchers []Voucher) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"date", "voucher", "line", "ledger", "debit", "credit", "narration"})
+	for _, v := range vouchers {
+		for i, l := range v.Lines {
+			w.Write([]string{v.Date.Format("2006-01-02"), v.Number, fmt.Sprint(i + 1), l.Ledger,
+				l.Debit.String(), l.Credit.String(), v.Narration})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write journal csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/service"
+)
+
+type AccountingPeriodHandler struct {
+	periodService *service.AccountingPeriodService
+}
+
+func NewAccountingPeriodHandler(periodService *service.AccountingPeriodService) *AccountingPeriodHandler {
+	return &AccountingPeriodHandler{periodService: periodService}
+}
+
+func (h *AccountingPeriodHandler) ListPeriods(c *gin.Context) {
+	periods, err := h.periodService.List(c.Request.Context())
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list accounting periods")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, periods)
+}
+
+func (h *AccountingPeriodHandler) GetPeriod(c *gin.Context) {
+	period, err := h.periodService.Get(c.Request.Context(), c.Param("period"))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get accounting period")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, period)
+}
+
+func (h *AccountingPeriodHandler) ClosePeriod(c *gin.Context) {
+	period, err := h.periodService.Close(c.Request.Context(), c.Param("period"), c.GetHeader(operatorHeader))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to close accounting period")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, period)
+}
+
+func (h *AccountingPeriodHandler) LockPeriod(c *gin.Context) {
+	period, err := h.periodService.Lock(c.Request.Context(), c.Param("period"), c.GetHeader(operatorHeader))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to lock accounting period")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, period)
+}
+
+func (h *AccountingPeriodHandler) ReopenPeriod(c *gin.Context) {
+	period, err := h.periodService.Reopen(c.Request.Context(), c.Param("period"), c.GetHeader(operatorHeader))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to reopen accounting period")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, period)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/service"
+)
+
+type ApprovalHandler struct {
+	approvalService *service.ApprovalService
+}
+
+func NewApprovalHandler(approvalService *service.ApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{approvalService: approvalService}
+}
+
+func (h *ApprovalHandler) ListApprovals(c *gin.Context) {
+	status := models.ApprovalStatus(strings.ToUpper(c.DefaultQuery("status", string(models.ApprovalStatusPending))))
+
+	approvals, err := h.approvalService.ListApprovals(c.Request.Context(), status)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list approvals")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, approvals)
+}
+
+func (h *ApprovalHandler) Approve(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("eventId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event_id"})
+		return
+	}
+
+	approval, err := h.approvalService.Approve(c.Request.Context(), eventID, c.GetHeader(operatorHeader))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to approve reward")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, approval)
+}
+
+func (h *ApprovalHandler) Reject(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("eventId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event_id"})
+		return
+	}
+
+	var req service.RejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logrus.WithError(err).Error("Invalid request")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	approval, err := h.approvalService.Reject(c.Request.Context(), eventID, c.GetHeader(operatorHeader), req)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to reject reward")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, approval)
+}
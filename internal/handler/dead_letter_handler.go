@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/service"
+)
+
+type DeadLetterHandler struct {
+	deadLetterService *service.DeadLetterService
+}
+
+func NewDeadLetterHandler(deadLetterService *service.DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{deadLetterService: deadLetterService}
+}
+
+func (h *DeadLetterHandler) ListDeadLetters(c *gin.Context) {
+	status := models.DeadLetterStatus(strings.ToUpper(c.DefaultQuery("status", string(models.DeadLetterStatusOpen))))
+	errorClass := models.DeadLetterErrorClass(strings.ToUpper(c.Query("error_class")))
+
+	letters, err := h.deadLetterService.List(c.Request.Context(), status, errorClass)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list dead letters")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, letters)
+}
+
+func (h *DeadLetterHandler) GetDeadLetter(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	letter, err := h.deadLetterService.Get(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get dead letter")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, letter)
+}
+
+func (h *DeadLetterHandler) UpdateDeadLetter(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req service.RewardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logrus.WithError(err).Error("Invalid request")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	letter, err := h.deadLetterService.UpdatePayload(c.Request.Context(), id, req)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to update dead letter")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, letter)
+}
+
+func (h *DeadLetterHandler) ReplayDeadLetter(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	letter, err := h.deadLetterService.Replay(c.Request.Context(), id, c.GetHeader(operatorHeader))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to replay dead letter")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, letter)
+}
+
+func (h *DeadLetterHandler) ReplayByErrorClass(c *gin.Context) {
+	var req service.BulkReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logrus.WithError(err).Error("Invalid request")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.ErrorClass = models.DeadLetterErrorClass(strings.ToUpper(string(req.ErrorClass)))
+
+	result, err := h.deadLetterService.ReplayByErrorClass(c.Request.Context(), req, c.GetHeader(operatorHeader))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to replay dead letters")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
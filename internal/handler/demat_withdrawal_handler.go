@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/service"
+)
+
+type DematWithdrawalHandler struct {
+	withdrawalService *service.DematWithdrawalService
+}
+
+func NewDematWithdrawalHandler(withdrawalService *service.DematWithdrawalService) *DematWithdrawalHandler {
+	return &DematWithdrawalHandler{withdrawalService: withdrawalService}
+}
+
+func (h *DematWithdrawalHandler) RequestWithdrawal(c *gin.Context) {
+	var req service.WithdrawalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logrus.WithError(err).Error("Invalid request")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	withdrawal, err := h.withdrawalService.RequestWithdrawal(c.Request.Context(), req)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to request withdrawal")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, withdrawal)
+}
+
+func (h *DematWithdrawalHandler) ListWithdrawals(c *gin.Context) {
+	userID, err := uuid.Parse(c.Query("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+		return
+	}
+
+	withdrawals, err := h.withdrawalService.ListWithdrawals(c.Request.Context(), userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list withdrawals")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, withdrawals)
+}
+
+func (h *DematWithdrawalHandler) GetWithdrawal(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	withdrawal, err := h.withdrawalService.GetWithdrawal(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get withdrawal")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, withdrawal)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"stocky/internal/service"
+)
+
+const operatorHeader = "X-Operator-ID"
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrRewardNotFound),
+		errors.Is(err, service.ErrApprovalNotFound),
+		errors.Is(err, service.ErrDeadLetterNotFound),
+		errors.Is(err, service.ErrWebhookNotFound),
+		errors.Is(err, service.ErrWebhookDeliveryNotFound),
+		errors.Is(err, service.ErrOfferNotFound),
+		errors.Is(err, service.ErrCampaignNotFound),
+		errors.Is(err, service.ErrWithdrawalNotFound),
+		errors.Is(err, service.ErrReconciliationNotFound),
+		errors.Is(err, service.ErrExportNotFound),
+		errors.Is(err, service.ErrPeriodNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidVestingSchedule),
+		errors.Is(err, service.ErrOperatorRequired),
+		errors.Is(err, service.ErrInvalidInventorySettings),
+		errors.Is(err, service.ErrInvalidPayload),
+		errors.Is(err, service.ErrInvalidWebhook),
+		errors.Is(err, service.ErrInvalidTimestamp),
+		errors.Is(err, service.ErrInvalidSchedule),
+		errors.Is(err, service.ErrInvalidOffer),
+		errors.Is(err, service.ErrInvalidCampaign),
+		errors.Is(err, service.ErrInvalidTransfer),
+		errors.Is(err, service.ErrInvalidSale),
+		errors.Is(err, service.ErrInvalidWithdrawal),
+		errors.Is(err, service.ErrInvalidStatement),
+		errors.Is(err, service.ErrInvalidReport),
+		errors.Is(err, service.ErrInvalidExport),
+		errors.Is(err, service.ErrInvalidPeriod):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrSelfApproval):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrApprovalNotPending),
+		errors.Is(err, service.ErrApprovalExpired),
+		errors.Is(err, service.ErrRewardNotBooked),
+		errors.Is(err, service.ErrInvalidTransition),
+		errors.Is(err, service.ErrDeadLetterNotOpen),
+		errors.Is(err, service.ErrPartnerMismatch),
+		errors.Is(err, service.ErrPeriodClosed),
+		errors.Is(err, service.ErrRewardNotScheduled),
+		errors.Is(err, service.ErrRewardAlreadyExists),
+		errors.Is(err, service.ErrOfferNotClaimable),
+		errors.Is(err, service.ErrOfferExpired),
+		errors.Is(err, service.ErrCampaignBudgetExceeded),
+		errors.Is(err, service.ErrTransferConflict),
+		errors.Is(err, service.ErrSaleConflict),
+		errors.Is(err, service.ErrWithdrawalConflict),
+		errors.Is(err, service.ErrExportAlreadyImported),
+		errors.Is(err, service.ErrInvalidPeriodTransition):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrInsufficientUnvested),
+		errors.Is(err, service.ErrInsufficientHoldings):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrPriceUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/service"
+)
+
+type InventoryHandler struct {
+	inventoryService *service.InventoryService
+}
+
+func NewInventoryHandler(inventoryService *service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{inventoryService: inventoryService}
+}
+
+func (h *InventoryHandler) ListPools(c *gin.Context) {
+	pools, err := h.inventoryService.ListPools(c.Request.Context())
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list inventory pools")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, pools)
+}
+
+func (h *InventoryHandler) UpdatePool(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	var req service.UpdatePoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logrus.WithError(err).Error("Invalid request")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.inventoryService.UpdatePool(c.Request.Context(), symbol, req); err != nil {
+		logrus.WithError(err).Error("Failed to update inventory pool")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Inventory pool updated",
+		"symbol":  symbol,
+	})
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/service"
+)
+
+type LedgerExportHandler struct {
+	exportService *service.LedgerExportService
+}
+
+func NewLedgerExportHandler(exportService *service.LedgerExportService) *LedgerExportHandler {
+	return &LedgerExportHandler{exportService: exportService}
+}
+
+func (h *LedgerExportHandler) CreateExport(c *gin.Context) {
+	var req service.ExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Format = models.ExportFormat(strings.ToUpper(string(req.Format)))
+
+	export, created, err := h.exportService.Export(c.Request.Context(), req)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to export ledger")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, export)
+}
+
+func (h *LedgerExportHandler) ListExports(c *gin.Context) {
+	format := models.ExportFormat(strings.ToUpper(c.Query("format")))
+
+	exports, err := h.exportService.List(c.Request.Context(), format)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list ledger exports")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, exports)
+}
+
+func (h *LedgerExportHandler) DownloadExport(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	export, err := h.exportService.Download(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to download ledger export")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType, extension := "text/csv", "csv"
+	if export.Format == models.ExportFormatTallyXML {
+		contentType, extension = "application/xml", "xml"
+	}
+	filename := fmt.Sprintf("ledger-%s-%s.%s", export.ExportDate.Format("2006-01-02"), strings.ToLower(string(export.Format)), extension)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("X-Checksum-SHA256", export.Checksum)
+	c.Data(http.StatusOK, contentType, export.Content)
+}
+
+func (h *LedgerExportHandler) MarkImported(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	export, err := h.exportService.MarkImported(c.Request.Context(), id, c.GetHeader(operatorHeader))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to mark ledger export imported")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
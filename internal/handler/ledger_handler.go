@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/service"
+)
+
+type LedgerHandler struct {
+	ledgerService *service.LedgerService
+}
+
+func NewLedgerHandler(ledgerService *service.LedgerService) *LedgerHandler {
+	return &LedgerHandler{ledgerService: ledgerService}
+}
+
+func (h *LedgerHandler) ListAccounts(c *gin.Context) {
+	var userID *uuid.UUID
+	if raw := c.Query("user_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+			return
+		}
+		userID = &id
+	}
+
+	accounts, err := h.ledgerService.ListAccounts(c.Request.Context(), userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list ledger accounts")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+func (h *LedgerHandler) GetStatement(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+		return
+	}
+
+	var query service.StatementQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	statement, err := h.ledgerService.GetStatement(c.Request.Context(), userID, query)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get statement")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, statement)
+}
+
+func (h *LedgerHandler) VerifyChain(c *gin.Context) {
+	result, err := h.ledgerService.VerifyChain(c.Request.Context())
+	if err != nil {
+		logrus.WithError(err).Error("Failed to verify ledger chain")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if !result.Valid {
+		logrus.WithFields(logrus.Fields{
+			"sequence": result.BrokenAt.Sequence,
+			"entry_id": result.BrokenAt.EntryID,
+			"reason":   result.BrokenAt.Reason,
+		}).Error("Ledger chain is broken")
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/service"
+)
+
+type OrderHandler struct {
+	orderService *service.OrderService
+}
+
+func NewOrderHandler(orderService *service.OrderService) *OrderHandler {
+	return &OrderHandler{orderService: orderService}
+}
+
+func (h *OrderHandler) ListOrders(c *gin.Context) {
+	status := models.BrokerOrderStatus(strings.ToUpper(c.DefaultQuery("status", string(models.BrokerOrderStatusSubmitted))))
+
+	orders, err := h.orderService.ListOrders(c.Request.Context(), status)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list broker orders")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+func (h *OrderHandler) RunOrderCycle(c *gin.Context) {
+	if err := h.orderService.RunOrderCycle(c.Request.Context()); err != nil {
+		logrus.WithError(err).Error("Failed to run order cycle")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Order cycle completed"})
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/service"
+)
+
+type ReconciliationHandler struct {
+	reconciliationService *service.ReconciliationService
+}
+
+func NewReconciliationHandler(reconciliationService *service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{reconciliationService: reconciliationService}
+}
+
+func (h *ReconciliationHandler) GetLatest(c *gin.Context) {
+	result, err := h.reconciliationService.GetLatest(c.Request.Context())
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get latest reconciliation")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *ReconciliationHandler) Run(c *gin.Context) {
+	result, err := h.reconciliationService.Run(c.Request.Context())
+	if err != nil {
+		logrus.WithError(err).Error("Failed to run reconciliation")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
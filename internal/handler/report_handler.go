@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/service"
+)
+
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+func (h *ReportHandler) TrialBalance(c *gin.Context) {
+	query, ok := bindReportQuery(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.TrialBalance(c.Request.Context(), query)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to build trial balance")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if !wantsCSV(c) {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	w := startCSV(c, "trial-balance.csv")
+	w.Write([]string{"account_code", "account_name", "account_type", "entry_type", "opening_balance",
+		"debit", "credit", "closing_balance", "closing_debit", "closing_credit"})
+	for _, l := range report.Lines {
+		w.Write([]string{l.AccountCode, l.AccountName, string(l.AccountType), string(l.EntryType), l.OpeningBalance.String(),
+			l.Debit.String(), l.Credit.String(), l.ClosingBalance.String(), l.ClosingDebit.String(), l.ClosingCredit.String()})
+	}
+	w.Write([]string{"TOTAL", "", "", "", "", report.TotalDebit.String(), report.TotalCredit.String(), "",
+		report.ClosingDebit.String(), report.ClosingCredit.String()})
+	w.Flush()
+}
+
+func (h *ReportHandler) AccountActivity(c *gin.Context) {
+	query, ok := bindReportQuery(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.AccountActivity(c.Request.Context(), query)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to build account activity")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if !wantsCSV(c) {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	w := startCSV(c, "account-activity.csv")
+	w.Write([]string{"date", "account_code", "account_name", "account_type", "entry_type", "postings", "debit", "credit"})
+	for _, l := range report.Lines {
+		w.Write([]string{l.Date, l.AccountCode, l.AccountName, string(l.AccountType), string(l.EntryType),
+			fmt.Sprint(l.Postings), l.Debit.String(), l.Credit.String()})
+	}
+	w.Flush()
+}
+
+func (h *ReportHandler) GeneralLedger(c *gin.Context) {
+	query, ok := bindReportQuery(c)
+	if !ok {
+		return
+	}
+	if err := h.reportService.ValidateQuery(query); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	var err error
+	if wantsCSV(c) {
+		w := startCSV(c, "general-ledger.csv")
+		w.Write([]string{"sequence", "entry_id", "created_at", "account_code", "entry_type", "event_id", "user_id",
+			"symbol", "debit", "credit", "balance"})
+		err = h.reportService.GeneralLedger(c.Request.Context(), query, func(l *service.GeneralLedgerLine) error {
+			userID, symbol := "", ""
+			if l.UserID != nil {
+				userID = l.UserID.String()
+			}
+			if l.Symbol != nil {
+				symbol = *l.Symbol
+			}
+			return w.Write([]string{fmt.Sprint(l.Sequence), l.EntryID.String(), l.CreatedAt.Format("2006-01-02T15:04:05.000000"),
+				l.AccountCode, string(l.EntryType), l.EventID.String(), userID, symbol,
+				l.Debit.String(), l.Credit.String(), l.Balance.String()})
+		})
+		w.Flush()
+	} else {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		c.Status(http.StatusOK)
+		enc := json.NewEncoder(c.Writer)
+		first := true
+		c.Writer.WriteString("[")
+		err = h.reportService.GeneralLedger(c.Request.Context(), query, func(l *service.GeneralLedgerLine) error {
+			if !first {
+				c.Writer.WriteString(",")
+			}
+			first = false
+			return enc.Encode(l)
+		})
+		c.Writer.WriteString("]")
+	}
+
+	if err != nil {
+		logrus.WithError(err).Error("Failed to stream general ledger")
+	}
+}
+
+func bindReportQuery(c *gin.Context) (service.ReportQuery, bool) {
+	var query service.ReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return query, false
+	}
+	return query, true
+}
+
+func wantsCSV(c *gin.Context) bool {
+	return c.Query("format") == "csv"
+}
+
+func startCSV(c *gin.Context, filename string) *csv.Writer {
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+	return csv.NewWriter(c.Writer)
+}
@@ -1,19 +1,36 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
 	"stocky/internal/service"
 )
 
 type RewardHandler struct {
-	rewardService *service.RewardService
+	rewardService     *service.RewardService
+	queueService      *service.RewardQueueService
+	deadLetterService *service.DeadLetterService
+	async             bool
 }
 
-func NewRewardHandler(rewardService *service.RewardService) *RewardHandler {
-	return &RewardHandler{rewardService: rewardService}
+func NewRewardHandler(
+	rewardService *service.RewardService,
+	queueService *service.RewardQueueService,
+	deadLetterService *service.DeadLetterService,
+	async bool,
+) *RewardHandler {
+	return &RewardHandler{
+		rewardService:     rewardService,
+		queueService:      queueService,
+		deadLetterService: deadLetterService,
+		async:             async,
+	}
 }
 
 func (h *RewardHandler) CreateReward(c *gin.Context) {
@@ -23,16 +40,103 @@ func (h *RewardHandler) CreateReward(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.RequestedBy = c.GetHeader(operatorHeader)
+	req.ReceivedAt = time.Now()
+
+	if h.async {
+		status, err := h.queueService.Enqueue(c.Request.Context(), req)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to enqueue reward")
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		statusURL := fmt.Sprintf("/api/v1/rewards/%s/status", req.EventID)
+		c.Header("Location", statusURL)
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":    "Reward accepted for processing",
+			"event_id":   req.EventID,
+			"status":     status.Status,
+			"status_url": statusURL,
+		})
+		return
+	}
 
-	if err := h.rewardService.ProcessReward(c.Request.Context(), req); err != nil {
+	reward, err := h.rewardService.ProcessReward(c.Request.Context(), req)
+	if err != nil {
 		logrus.WithError(err).Error("Failed to process reward")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		status := errorStatus(err)
+		if status >= http.StatusInternalServerError {
+			if dlErr := h.deadLetterService.CaptureRequest(c.Request.Context(), models.DeadLetterSourceAPI, req, 1, err); dlErr != nil {
+				logrus.WithError(dlErr).Error("Failed to dead-letter reward")
+			}
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if reward.Status == models.RewardStatusPendingApproval {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":  "Reward is pending approval",
+			"event_id": req.EventID,
+			"status":   reward.Status,
+		})
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message":  "Reward processed successfully",
-		"event_id": req.EventID,
+		"message":       "Reward processed successfully",
+		"event_id":      req.EventID,
+		"status":        reward.Status,
+		"event_time":    reward.Timestamp,
+		"booked_at":     reward.BookedAt,
+		"booking_price": reward.BookingPrice,
+		"priced_at":     reward.PricedAt,
 	})
 }
 
+func (h *RewardHandler) GetRewardStatus(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("eventId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event_id"})
+		return
+	}
+
+	status, err := h.queueService.GetStatus(c.Request.Context(), eventID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get reward status")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (h *RewardHandler) ReverseReward(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("eventId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event_id"})
+		return
+	}
+
+	var req service.ReverseRewardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logrus.WithError(err).Error("Invalid request")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reversal, err := h.rewardService.ReverseReward(c.Request.Context(), eventID, req)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to reverse reward")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Reward reversed successfully",
+		"reversal_id": reversal.ID,
+		"event_id":    reversal.EventID,
+		"quantity":    reversal.Quantity,
+	})
+}
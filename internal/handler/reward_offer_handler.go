@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/service"
+)
+
+type RewardOfferHandler struct {
+	offerService *service.RewardOfferService
+}
+
+func NewRewardOfferHandler(offerService *service.RewardOfferService) *RewardOfferHandler {
+	return &RewardOfferHandler{offerService: offerService}
+}
+
+func (h *RewardOfferHandler) OfferReward(c *gin.Context) {
+	var req service.OfferRewardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logrus.WithError(err).Error("Invalid request")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.RequestedBy = c.GetHeader(operatorHeader)
+
+	offer, err := h.offerService.Offer(c.Request.Context(), req)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to offer reward")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, offer)
+}
+
+func (h *RewardOfferHandler) ClaimReward(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("eventId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event_id"})
+		return
+	}
+
+	reward, err := h.offerService.Claim(c.Request.Context(), eventID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to claim reward")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":       "Reward claimed successfully",
+		"event_id":      reward.EventID,
+		"status":        reward.Status,
+		"event_time":    reward.Timestamp,
+		"booked_at":     reward.BookedAt,
+		"booking_price": reward.BookingPrice,
+		"priced_at":     reward.PricedAt,
+	})
+}
+
+func (h *RewardOfferHandler) CreateCampaign(c *gin.Context) {
+	var req service.CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logrus.WithError(err).Error("Invalid request")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	campaign, err := h.offerService.CreateCampaign(c.Request.Context(), req)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to create campaign")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, campaign)
+}
+
+func (h *RewardOfferHandler) ListCampaigns(c *gin.Context) {
+	campaigns, err := h.offerService.ListCampaigns(c.Request.Context())
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list campaigns")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, campaigns)
+}
+
+func (h *RewardOfferHandler) GetCampaign(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	campaign, err := h.offerService.GetCampaign(c.Request.Context(), id)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get campaign")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, campaign)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/service"
+)
+
+type ScheduledRewardHandler struct {
+	scheduledRewardService *service.ScheduledRewardService
+}
+
+func NewScheduledRewardHandler(scheduledRewardService *service.ScheduledRewardService) *ScheduledRewardHandler {
+	return &ScheduledRewardHandler{scheduledRewardService: scheduledRewardService}
+}
+
+func (h *ScheduledRewardHandler) ScheduleReward(c *gin.Context) {
+	var req service.ScheduleRewardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logrus.WithError(err).Error("Invalid request")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.RequestedBy = c.GetHeader(operatorHeader)
+
+	scheduled, err := h.scheduledRewardService.Schedule(c.Request.Context(), req)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to schedule reward")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	statusURL := fmt.Sprintf("/api/v1/rewards/%s/status", scheduled.EventID)
+	c.Header("Location", statusURL)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":       "Reward scheduled",
+		"event_id":      scheduled.EventID,
+		"status":        scheduled.Status,
+		"scheduled_for": scheduled.ScheduledFor,
+		"status_url":    statusURL,
+	})
+}
+
+func (h *ScheduledRewardHandler) CancelScheduledReward(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("eventId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event_id"})
+		return
+	}
+
+	scheduled, err := h.scheduledRewardService.Cancel(c.Request.Context(), eventID, c.GetHeader(operatorHeader))
+	if err != nil {
+		logrus.WithError(err).Error("Failed to cancel scheduled reward")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, scheduled)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/service"
+)
+
+type ShareSaleHandler struct {
+	saleService *service.ShareSaleService
+}
+
+func NewShareSaleHandler(saleService *service.ShareSaleService) *ShareSaleHandler {
+	return &ShareSaleHandler{saleService: saleService}
+}
+
+func (h *ShareSaleHandler) Sell(c *gin.Context) {
+	var req service.SellRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logrus.WithError(err).Error("Invalid request")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sale, err := h.saleService.Sell(c.Request.Context(), req)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to sell shares")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sale)
+}
+
+func (h *ShareSaleHandler) ListSales(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+		return
+	}
+
+	sales, err := h.saleService.ListSales(c.Request.Context(), userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list share sales")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sales)
+}
+
+func (h *ShareSaleHandler) GetWallet(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+		return
+	}
+
+	wallet, err := h.saleService.GetWallet(c.Request.Context(), userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get wallet")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, wallet)
+}
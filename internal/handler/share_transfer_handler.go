@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/service"
+)
+
+type ShareTransferHandler struct {
+	transferService *service.ShareTransferService
+}
+
+func NewShareTransferHandler(transferService *service.ShareTransferService) *ShareTransferHandler {
+	return &ShareTransferHandler{transferService: transferService}
+}
+
+func (h *ShareTransferHandler) CreateTransfer(c *gin.Context) {
+	var req service.TransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logrus.WithError(err).Error("Invalid request")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transfer, err := h.transferService.Transfer(c.Request.Context(), req)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to transfer shares")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+func (h *ShareTransferHandler) ListTransfers(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+		return
+	}
+
+	transfers, err := h.transferService.ListTransfers(c.Request.Context(), userID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list share transfers")
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
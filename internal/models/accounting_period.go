@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type PeriodStatus string
+
+const (
+	PeriodStatusOpen   PeriodStatus = "OPEN"
+	PeriodStatusClosed PeriodStatus = "CLOSED"
+	PeriodStatusLocked PeriodStatus = "LOCKED"
+)
+
+type AccountingPeriod struct {
+	ID         uuid.UUID    `db:"id"`
+	Period     string       `db:"period"`
+	StartsAt   time.Time    `db:"starts_at"`
+	EndsAt     time.Time    `db:"ends_at"`
+	Status     PeriodStatus `db:"status"`
+	ClosedAt   *time.Time   `db:"closed_at"`
+	ClosedBy   *string      `db:"closed_by"`
+	LockedAt   *time.Time   `db:"locked_at"`
+	LockedBy   *string      `db:"locked_by"`
+	ReopenedAt *time.Time   `db:"reopened_at"`
+	ReopenedBy *string      `db:"reopened_by"`
+	CreatedAt  time.Time    `db:"created_at"`
+}
+
+type AccountingPeriodBalance struct {
+	PeriodID       uuid.UUID         `db:"period_id"`
+	AccountID      uuid.UUID         `db:"account_id"`
+	AccountCode    string            `db:"account_code"`
+	AccountName    string            `db:"account_name"`
+	AccountType    LedgerAccountType `db:"account_type"`
+	OpeningDebit   decimal.Decimal   `db:"opening_debit"`
+	OpeningCredit  decimal.Decimal   `db:"opening_credit"`
+	PeriodDebit    decimal.Decimal   `db:"period_debit"`
+	PeriodCredit   decimal.Decimal   `db:"period_credit"`
+	ClosingBalance decimal.Decimal   `db:"closing_balance"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ApprovalStatus string
+
+const (
+	ApprovalStatusPending  ApprovalStatus = "PENDING"
+	ApprovalStatusApproved ApprovalStatus = "APPROVED"
+	ApprovalStatusRejected ApprovalStatus = "REJECTED"
+	ApprovalStatusExpired  ApprovalStatus = "EXPIRED"
+)
+
+type RewardApproval struct {
+	ID          uuid.UUID       `db:"id"`
+	EventID     uuid.UUID       `db:"event_id"`
+	Status      ApprovalStatus  `db:"status"`
+	ValueINR    decimal.Decimal `db:"value_inr"`
+	RequestedBy string          `db:"requested_by"`
+	DecidedBy   *string         `db:"decided_by"`
+	Reason      *string         `db:"reason"`
+	ExpiresAt   time.Time       `db:"expires_at"`
+	DecidedAt   *time.Time      `db:"decided_at"`
+	CreatedAt   time.Time       `db:"created_at"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"stocky/pkg/fees"
+)
+
+type BrokerOrderStatus string
+
+const (
+	BrokerOrderStatusPending   BrokerOrderStatus = "PENDING"
+	BrokerOrderStatusSubmitted BrokerOrderStatus = "SUBMITTED"
+	BrokerOrderStatusFilled    BrokerOrderStatus = "FILLED"
+	BrokerOrderStatusFailed    BrokerOrderStatus = "FAILED"
+)
+
+type BrokerOrderPurpose string
+
+const (
+	BrokerOrderPurposeRewards       BrokerOrderPurpose = "REWARDS"
+	BrokerOrderPurposeReplenishment BrokerOrderPurpose = "REPLENISHMENT"
+)
+
+type BrokerOrder struct {
+	ID             uuid.UUID          `db:"id"`
+	Symbol         string             `db:"symbol"`
+	Side           string             `db:"side"`
+	Purpose        BrokerOrderPurpose `db:"purpose"`
+	Quantity       int64              `db:"quantity"`
+	RewardQuantity decimal.Decimal    `db:"reward_quantity"`
+	BookedValue    decimal.Decimal    `db:"booked_value"`
+	Status         BrokerOrderStatus  `db:"status"`
+	BrokerOrderID  *string            `db:"broker_order_id"`
+	FailureReason  *string            `db:"failure_reason"`
+	SubmittedAt    *time.Time         `db:"submitted_at"`
+	FilledAt       *time.Time         `db:"filled_at"`
+	CreatedAt      time.Time          `db:"created_at"`
+}
+
+type BrokerFill struct {
+	ID              uuid.UUID       `db:"id"`
+	OrderID         uuid.UUID       `db:"order_id"`
+	BrokerFillID    string          `db:"broker_fill_id"`
+	Quantity        decimal.Decimal `db:"quantity"`
+	Price           decimal.Decimal `db:"price"`
+	Fees            decimal.Decimal `db:"fees"`
+	Brokerage       decimal.Decimal `db:"brokerage"`
+	STT             decimal.Decimal `db:"stt"`
+	GST             decimal.Decimal `db:"gst"`
+	ExchangeCharges decimal.Decimal `db:"exchange_charges"`
+	SEBICharges     decimal.Decimal `db:"sebi_charges"`
+	StampDuty       decimal.Decimal `db:"stamp_duty"`
+	ExecutedAt      time.Time       `db:"executed_at"`
+	CreatedAt       time.Time       `db:"created_at"`
+}
+
+func (f *BrokerFill) FeeBreakdown() fees.Breakdown {
+	return fees.Breakdown{
+		Brokerage:       f.Brokerage,
+		STT:             f.STT,
+		GST:             f.GST,
+		ExchangeCharges: f.ExchangeCharges,
+		SEBICharges:     f.SEBICharges,
+		StampDuty:       f.StampDuty,
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DeadLetterStatus string
+
+const (
+	DeadLetterStatusOpen     DeadLetterStatus = "OPEN"
+	DeadLetterStatusReplayed DeadLetterStatus = "REPLAYED"
+)
+
+type DeadLetterSource string
+
+const (
+	DeadLetterSourceAPI      DeadLetterSource = "API"
+	DeadLetterSourceQueue    DeadLetterSource = "QUEUE"
+	DeadLetterSourceSchedule DeadLetterSource = "SCHEDULE"
+)
+
+type DeadLetterErrorClass string
+
+const (
+	DeadLetterErrorPriceUnavailable DeadLetterErrorClass = "PRICE_UNAVAILABLE"
+	DeadLetterErrorValidation       DeadLetterErrorClass = "VALIDATION"
+	DeadLetterErrorDatabase         DeadLetterErrorClass = "DATABASE"
+	DeadLetterErrorUnknown          DeadLetterErrorClass = "UNKNOWN"
+)
+
+type RewardDeadLetter struct {
+	ID          uuid.UUID            `db:"id"`
+	EventID     uuid.UUID            `db:"event_id"`
+	Source      DeadLetterSource     `db:"source"`
+	Payload     []byte               `db:"payload"`
+	Error       string               `db:"error"`
+	ErrorClass  DeadLetterErrorClass `db:"error_class"`
+	Attempts    int                  `db:"attempts"`
+	Status      DeadLetterStatus     `db:"status"`
+	ReplayCount int                  `db:"replay_count"`
+	ReplayedBy  *string              `db:"replayed_by"`
+	ReplayedAt  *time.Time           `db:"replayed_at"`
+	CreatedAt   time.Time            `db:"created_at"`
+	UpdatedAt   time.Time            `db:"updated_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type WithdrawalStatus string
+
+const (
+	WithdrawalStatusRequested WithdrawalStatus = "REQUESTED"
+	WithdrawalStatusSubmitted WithdrawalStatus = "SUBMITTED"
+	WithdrawalStatusCompleted WithdrawalStatus = "COMPLETED"
+	WithdrawalStatusFailed    WithdrawalStatus = "FAILED"
+)
+
+type DematWithdrawal struct {
+	ID            uuid.UUID        `db:"id"`
+	UserID        uuid.UUID        `db:"user_id"`
+	StockSymbol   string           `db:"stock_symbol"`
+	Quantity      decimal.Decimal  `db:"quantity"`
+	Price         decimal.Decimal  `db:"price"`
+	ValueINR      decimal.Decimal  `db:"value_inr"`
+	DPID          string           `db:"dp_id"`
+	ClientID      string           `db:"client_id"`
+	Status        WithdrawalStatus `db:"status"`
+	DepositoryRef *string          `db:"depository_ref"`
+	FailureReason *string          `db:"failure_reason"`
+	SubmittedAt   *time.Time       `db:"submitted_at"`
+	CompletedAt   *time.Time       `db:"completed_at"`
+	FailedAt      *time.Time       `db:"failed_at"`
+	CreatedAt     time.Time        `db:"created_at"`
+	UpdatedAt     time.Time        `db:"updated_at"`
+}
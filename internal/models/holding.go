@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type HoldingMovement struct {
+	ID          uuid.UUID       `db:"id"`
+	UserID      uuid.UUID       `db:"user_id"`
+	Symbol      string          `db:"symbol"`
+	Quantity    decimal.Decimal `db:"quantity"`
+	ReferenceID uuid.UUID       `db:"reference_id"`
+	EntryID     *uuid.UUID      `db:"entry_id"`
+	EffectiveAt time.Time       `db:"effective_at"`
+	CreatedAt   time.Time       `db:"created_at"`
+}
+
+type HoldingSnapshot struct {
+	SnapshotDate time.Time       `db:"snapshot_date"`
+	Symbol       *string         `db:"symbol"`
+	Quantity     decimal.Decimal `db:"quantity"`
+}
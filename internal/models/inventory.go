@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type InventoryMovementType string
+
+const (
+	InventoryMovementPurchase   InventoryMovementType = "PURCHASE"
+	InventoryMovementAllocation InventoryMovementType = "ALLOCATION"
+	InventoryMovementReversal   InventoryMovementType = "REVERSAL"
+	InventoryMovementBuyback    InventoryMovementType = "BUYBACK"
+)
+
+type InventoryPool struct {
+	Symbol             string          `db:"symbol"`
+	Quantity           decimal.Decimal `db:"quantity"`
+	Cost               decimal.Decimal `db:"cost"`
+	ReplenishThreshold decimal.Decimal `db:"replenish_threshold"`
+	ReplenishQuantity  int64           `db:"replenish_quantity"`
+	UpdatedAt          time.Time       `db:"updated_at"`
+}
+
+type InventoryMovement struct {
+	ID           uuid.UUID             `db:"id"`
+	Symbol       string                `db:"symbol"`
+	Quantity     decimal.Decimal       `db:"quantity"`
+	Cost         decimal.Decimal       `db:"cost"`
+	MovementType InventoryMovementType `db:"movement_type"`
+	ReferenceID  uuid.UUID             `db:"reference_id"`
+	CreatedAt    time.Time             `db:"created_at"`
+}
@@ -10,18 +10,96 @@ import (
 type LedgerEntryType string
 
 const (
-	LedgerEntryTypeStock LedgerEntryType = "STOCK"
-	LedgerEntryTypeCash  LedgerEntryType = "CASH"
-	LedgerEntryTypeFee   LedgerEntryType = "FEE"
+	LedgerEntryTypeStock         LedgerEntryType = "STOCK"
+	LedgerEntryTypeCash          LedgerEntryType = "CASH"
+	LedgerEntryTypeFee           LedgerEntryType = "FEE"
+	LedgerEntryTypeRewardExpense LedgerEntryType = "REWARD_EXPENSE"
+
+	LedgerEntryTypeBrokerage       LedgerEntryType = "BROKERAGE"
+	LedgerEntryTypeSTT             LedgerEntryType = "STT"
+	LedgerEntryTypeGST             LedgerEntryType = "GST"
+	LedgerEntryTypeExchangeCharges LedgerEntryType = "EXCHANGE_CHARGES"
+	LedgerEntryTypeSEBICharges     LedgerEntryType = "SEBI_CHARGES"
+	LedgerEntryTypeStampDuty       LedgerEntryType = "STAMP_DUTY"
+
+	LedgerEntryTypeSettlement LedgerEntryType = "SETTLEMENT"
+	LedgerEntryTypeInventory  LedgerEntryType = "INVENTORY"
+	LedgerEntryTypeCustody    LedgerEntryType = "CUSTODY"
+
+	LedgerEntryTypeExecutionVariance LedgerEntryType = "EXECUTION_VARIANCE"
+
+	LedgerEntryTypeWallet LedgerEntryType = "WALLET"
+
+	LedgerEntryTypeSaleCharges     LedgerEntryType = "SALE_CHARGES"
+	LedgerEntryTypeGSTPayable      LedgerEntryType = "GST_PAYABLE"
+	LedgerEntryTypeChargesPayable  LedgerEntryType = "CHARGES_PAYABLE"
+	LedgerEntryTypeBuybackGainLoss LedgerEntryType = "BUYBACK_GAIN_LOSS"
+
+	LedgerEntryTypeWithdrawalHold LedgerEntryType = "WITHDRAWAL_HOLD"
+	LedgerEntryTypeDematOut       LedgerEntryType = "DEMAT_OUT"
+
+	LedgerEntryTypeRounding LedgerEntryType = "ROUNDING"
 )
 
 type LedgerEntry struct {
 	ID        uuid.UUID       `db:"id"`
 	EventID   uuid.UUID       `db:"event_id"`
+	UserID    *uuid.UUID      `db:"user_id"`
+	AccountID uuid.UUID       `db:"account_id"`
 	EntryType LedgerEntryType `db:"entry_type"`
-	Symbol    *string          `db:"symbol"`
-	Debit     decimal.Decimal  `db:"debit"`
-	Credit    decimal.Decimal  `db:"credit"`
+	Symbol    *string         `db:"symbol"`
+	Debit     decimal.Decimal `db:"debit"`
+	Credit    decimal.Decimal `db:"credit"`
 	CreatedAt time.Time       `db:"created_at"`
+	Sequence  int64           `db:"sequence"`
+	PrevHash  string          `db:"prev_hash"`
+	Hash      string          `db:"hash"`
+
+	Quantity    decimal.Decimal `db:"-"`
+	EffectiveAt time.Time       `db:"-"`
 }
 
+type LedgerAccountType string
+
+const (
+	LedgerAccountTypeAsset     LedgerAccountType = "ASSET"
+	LedgerAccountTypeLiability LedgerAccountType = "LIABILITY"
+	LedgerAccountTypeEquity    LedgerAccountType = "EQUITY"
+	LedgerAccountTypeIncome    LedgerAccountType = "INCOME"
+	LedgerAccountTypeExpense   LedgerAccountType = "EXPENSE"
+)
+
+func (t LedgerAccountType) DebitNormal() bool {
+	return t == LedgerAccountTypeAsset || t == LedgerAccountTypeExpense
+}
+
+type LedgerAccount struct {
+	ID          uuid.UUID         `db:"id"`
+	Code        string            `db:"code"`
+	Name        string            `db:"name"`
+	AccountType LedgerAccountType `db:"account_type"`
+	UserID      *uuid.UUID        `db:"user_id"`
+	Symbol      *string           `db:"symbol"`
+	CreatedAt   time.Time         `db:"created_at"`
+}
+
+type LedgerAccountBalance struct {
+	LedgerAccount
+	TotalDebit  decimal.Decimal `db:"total_debit"`
+	TotalCredit decimal.Decimal `db:"total_credit"`
+}
+
+type LedgerPosting struct {
+	ID          uuid.UUID        `db:"id"`
+	EventID     uuid.UUID        `db:"event_id"`
+	AccountID   uuid.UUID        `db:"account_id"`
+	AccountCode string           `db:"account_code"`
+	EntryType   LedgerEntryType  `db:"entry_type"`
+	Symbol      *string          `db:"symbol"`
+	Kind        string           `db:"kind"`
+	BalanceKey  *string          `db:"balance_key"`
+	Debit       decimal.Decimal  `db:"debit"`
+	Credit      decimal.Decimal  `db:"credit"`
+	CreatedAt   time.Time        `db:"created_at"`
+	Quantity    *decimal.Decimal `db:"quantity"`
+}
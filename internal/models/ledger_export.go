@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatTallyXML   ExportFormat = "TALLY_XML"
+	ExportFormatJournalCSV ExportFormat = "JOURNAL_CSV"
+)
+
+func (f ExportFormat) Valid() bool {
+	return f == ExportFormatTallyXML || f == ExportFormatJournalCSV
+}
+
+type LedgerExport struct {
+	ID           uuid.UUID       `db:"id"`
+	ExportDate   time.Time       `db:"export_date"`
+	Format       ExportFormat    `db:"format"`
+	VoucherCount int             `db:"voucher_count"`
+	EntryCount   int             `db:"entry_count"`
+	TotalDebit   decimal.Decimal `db:"total_debit"`
+	TotalCredit  decimal.Decimal `db:"total_credit"`
+	Checksum     string          `db:"checksum"`
+	Content      []byte          `db:"content"`
+	ImportedAt   *time.Time      `db:"imported_at"`
+	ImportedBy   *string         `db:"imported_by"`
+	CreatedAt    time.Time       `db:"created_at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type TrialBalanceRow struct {
+	AccountCode   string            `db:"account_code"`
+	AccountName   string            `db:"account_name"`
+	AccountType   LedgerAccountType `db:"account_type"`
+	EntryType     LedgerEntryType   `db:"entry_type"`
+	OpeningDebit  decimal.Decimal   `db:"opening_debit"`
+	OpeningCredit decimal.Decimal   `db:"opening_credit"`
+	PeriodDebit   decimal.Decimal   `db:"period_debit"`
+	PeriodCredit  decimal.Decimal   `db:"period_credit"`
+}
+
+type AccountActivityRow struct {
+	Day         time.Time         `db:"day"`
+	AccountCode string            `db:"account_code"`
+	AccountName string            `db:"account_name"`
+	AccountType LedgerAccountType `db:"account_type"`
+	EntryType   LedgerEntryType   `db:"entry_type"`
+	Postings    int64             `db:"postings"`
+	Debit       decimal.Decimal   `db:"debit"`
+	Credit      decimal.Decimal   `db:"credit"`
+}
+
+type GeneralLedgerRow struct {
+	Sequence      int64             `db:"sequence"`
+	EntryID       uuid.UUID         `db:"entry_id"`
+	CreatedAt     time.Time         `db:"created_at"`
+	AccountCode   string            `db:"account_code"`
+	AccountType   LedgerAccountType `db:"account_type"`
+	EntryType     LedgerEntryType   `db:"entry_type"`
+	EventID       uuid.UUID         `db:"event_id"`
+	UserID        *uuid.UUID        `db:"user_id"`
+	Symbol        *string           `db:"symbol"`
+	Debit         decimal.Decimal   `db:"debit"`
+	Credit        decimal.Decimal   `db:"credit"`
+	CreditBalance decimal.Decimal   `db:"credit_balance"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxEventType string
+
+const (
+	OutboxEventRewardCreated       OutboxEventType = "RewardCreated"
+	OutboxEventRewardBooked        OutboxEventType = "RewardBooked"
+	OutboxEventRewardStatusChanged OutboxEventType = "RewardStatusChanged"
+	OutboxEventRewardReversed      OutboxEventType = "RewardReversed"
+	OutboxEventPriceUpdated        OutboxEventType = "PriceUpdated"
+	OutboxEventSharesTransferred   OutboxEventType = "SharesTransferred"
+	OutboxEventSharesSold          OutboxEventType = "SharesSold"
+	OutboxEventWithdrawalChanged   OutboxEventType = "WithdrawalStatusChanged"
+)
+
+func (t OutboxEventType) Valid() bool {
+	switch t {
+	case OutboxEventRewardCreated, OutboxEventRewardBooked, OutboxEventRewardStatusChanged,
+		OutboxEventRewardReversed, OutboxEventPriceUpdated, OutboxEventSharesTransferred,
+		OutboxEventSharesSold, OutboxEventWithdrawalChanged:
+		return true
+	}
+	return false
+}
+
+type OutboxEvent struct {
+	ID            int64           `db:"id"`
+	MessageID     uuid.UUID       `db:"message_id"`
+	EventType     OutboxEventType `db:"event_type"`
+	AggregateType string          `db:"aggregate_type"`
+	AggregateID   string          `db:"aggregate_id"`
+	PartitionKey  string          `db:"partition_key"`
+	Sequence      int64           `db:"sequence"`
+	Payload       []byte          `db:"payload"`
+	CreatedAt     time.Time       `db:"created_at"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ReconciliationStatus string
+
+const (
+	ReconciliationStatusPassed ReconciliationStatus = "PASSED"
+	ReconciliationStatusFailed ReconciliationStatus = "FAILED"
+)
+
+type ReconciliationCheck string
+
+const (
+	ReconciliationCheckLedgerTotals      ReconciliationCheck = "LEDGER_TOTALS"
+	ReconciliationCheckEventBalance      ReconciliationCheck = "EVENT_BALANCE"
+	ReconciliationCheckRewardLedger      ReconciliationCheck = "REWARD_LEDGER"
+	ReconciliationCheckHoldingsInventory ReconciliationCheck = "HOLDINGS_INVENTORY"
+	ReconciliationCheckInventoryValue    ReconciliationCheck = "INVENTORY_VALUE"
+	ReconciliationCheckHoldingBalances   ReconciliationCheck = "HOLDING_BALANCES"
+)
+
+type ReconciliationRun struct {
+	ID               uuid.UUID            `db:"id"`
+	Status           ReconciliationStatus `db:"status"`
+	DiscrepancyCount int                  `db:"discrepancy_count"`
+	StartedAt        time.Time            `db:"started_at"`
+	FinishedAt       time.Time            `db:"finished_at"`
+}
+
+type ReconciliationDiscrepancy struct {
+	ID          uuid.UUID           `db:"id"`
+	RunID       uuid.UUID           `db:"run_id"`
+	CheckName   ReconciliationCheck `db:"check_name"`
+	ReferenceID *uuid.UUID          `db:"reference_id"`
+	Symbol      *string             `db:"symbol"`
+	Expected    *decimal.Decimal    `db:"expected"`
+	Actual      *decimal.Decimal    `db:"actual"`
+	Message     string              `db:"message"`
+	CreatedAt   time.Time           `db:"created_at"`
+}
@@ -7,13 +7,46 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+type RewardStatus string
+
+const (
+	RewardStatusPendingApproval RewardStatus = "PENDING_APPROVAL"
+	RewardStatusBooked          RewardStatus = "BOOKED"
+	RewardStatusRejected        RewardStatus = "REJECTED"
+	RewardStatusExpired         RewardStatus = "EXPIRED"
+	RewardStatusOrdered         RewardStatus = "ORDERED"
+	RewardStatusSettled         RewardStatus = "SETTLED"
+	RewardStatusFailed          RewardStatus = "FAILED"
+)
+
+func (s RewardStatus) Held() bool {
+	return s == RewardStatusBooked || s == RewardStatusOrdered || s == RewardStatusSettled
+}
+
 type RewardEvent struct {
-	ID          uuid.UUID       `db:"id"`
-	EventID     uuid.UUID       `db:"event_id"`
-	UserID      uuid.UUID       `db:"user_id"`
-	StockSymbol string          `db:"stock_symbol"`
-	Quantity    decimal.Decimal `db:"quantity"`
-	Timestamp   time.Time       `db:"timestamp"`
-	CreatedAt   time.Time       `db:"created_at"`
+	ID              uuid.UUID        `db:"id"`
+	EventID         uuid.UUID        `db:"event_id"`
+	UserID          uuid.UUID        `db:"user_id"`
+	StockSymbol     string           `db:"stock_symbol"`
+	Quantity        decimal.Decimal  `db:"quantity"`
+	BookingPrice    *decimal.Decimal `db:"booking_price"`
+	PricedAt        *time.Time       `db:"priced_at"`
+	Status          RewardStatus     `db:"status"`
+	BookedAt        *time.Time       `db:"booked_at"`
+	OrderedAt       *time.Time       `db:"ordered_at"`
+	SettlementDueAt *time.Time       `db:"settlement_due_at"`
+	SettledAt       *time.Time       `db:"settled_at"`
+	FailedAt        *time.Time       `db:"failed_at"`
+	FailureReason   *string          `db:"failure_reason"`
+	Timestamp       time.Time        `db:"timestamp"`
+	ReceivedAt      *time.Time       `db:"received_at"`
+	CreatedAt       time.Time        `db:"created_at"`
 }
 
+type RewardReversal struct {
+	ID        uuid.UUID       `db:"id"`
+	EventID   uuid.UUID       `db:"event_id"`
+	Quantity  decimal.Decimal `db:"quantity"`
+	Reason    string          `db:"reason"`
+	CreatedAt time.Time       `db:"created_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RewardJobStatus string
+
+const (
+	RewardJobStatusQueued     RewardJobStatus = "QUEUED"
+	RewardJobStatusProcessing RewardJobStatus = "PROCESSING"
+	RewardJobStatusSucceeded  RewardJobStatus = "SUCCEEDED"
+	RewardJobStatusFailed     RewardJobStatus = "FAILED"
+)
+
+type RewardJob struct {
+	ID            uuid.UUID       `db:"id"`
+	EventID       uuid.UUID       `db:"event_id"`
+	Payload       []byte          `db:"payload"`
+	Status        RewardJobStatus `db:"status"`
+	Attempts      int             `db:"attempts"`
+	MaxAttempts   int             `db:"max_attempts"`
+	LastError     *string         `db:"last_error"`
+	NextAttemptAt time.Time       `db:"next_attempt_at"`
+	LockedAt      *time.Time      `db:"locked_at"`
+	CompletedAt   *time.Time      `db:"completed_at"`
+	CreatedAt     time.Time       `db:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type RewardOfferStatus string
+
+const (
+	RewardOfferStatusOffered RewardOfferStatus = "OFFERED"
+	RewardOfferStatusClaimed RewardOfferStatus = "CLAIMED"
+	RewardOfferStatusExpired RewardOfferStatus = "EXPIRED"
+)
+
+type RewardCampaign struct {
+	ID          uuid.UUID       `db:"id"`
+	Name        string          `db:"name"`
+	BudgetINR   decimal.Decimal `db:"budget_inr"`
+	ReservedINR decimal.Decimal `db:"reserved_inr"`
+	SpentINR    decimal.Decimal `db:"spent_inr"`
+	CreatedAt   time.Time       `db:"created_at"`
+	UpdatedAt   time.Time       `db:"updated_at"`
+}
+
+func (c *RewardCampaign) AvailableINR() decimal.Decimal {
+	return c.BudgetINR.Sub(c.ReservedINR).Sub(c.SpentINR)
+}
+
+type RewardOffer struct {
+	ID            uuid.UUID         `db:"id"`
+	EventID       uuid.UUID         `db:"event_id"`
+	CampaignID    *uuid.UUID        `db:"campaign_id"`
+	UserID        uuid.UUID         `db:"user_id"`
+	StockSymbol   string            `db:"stock_symbol"`
+	Quantity      decimal.Decimal   `db:"quantity"`
+	Payload       []byte            `db:"payload"`
+	ReservedINR   decimal.Decimal   `db:"reserved_inr"`
+	ClaimDeadline time.Time         `db:"claim_deadline"`
+	Status        RewardOfferStatus `db:"status"`
+	ClaimedAt     *time.Time        `db:"claimed_at"`
+	ExpiredAt     *time.Time        `db:"expired_at"`
+	CreatedAt     time.Time         `db:"created_at"`
+	UpdatedAt     time.Time         `db:"updated_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ScheduledRewardStatus string
+
+const (
+	ScheduledRewardStatusScheduled ScheduledRewardStatus = "SCHEDULED"
+	ScheduledRewardStatusExecuting ScheduledRewardStatus = "EXECUTING"
+	ScheduledRewardStatusExecuted  ScheduledRewardStatus = "EXECUTED"
+	ScheduledRewardStatusCancelled ScheduledRewardStatus = "CANCELLED"
+	ScheduledRewardStatusFailed    ScheduledRewardStatus = "FAILED"
+)
+
+type ScheduledReward struct {
+	ID           uuid.UUID             `db:"id"`
+	EventID      uuid.UUID             `db:"event_id"`
+	UserID       uuid.UUID             `db:"user_id"`
+	StockSymbol  string                `db:"stock_symbol"`
+	Quantity     decimal.Decimal       `db:"quantity"`
+	Payload      []byte                `db:"payload"`
+	ScheduledFor time.Time             `db:"scheduled_for"`
+	Status       ScheduledRewardStatus `db:"status"`
+	LockedAt     *time.Time            `db:"locked_at"`
+	ExecutedAt   *time.Time            `db:"executed_at"`
+	CancelledAt  *time.Time            `db:"cancelled_at"`
+	CancelledBy  *string               `db:"cancelled_by"`
+	LastError    *string               `db:"last_error"`
+	CreatedAt    time.Time             `db:"created_at"`
+	UpdatedAt    time.Time             `db:"updated_at"`
+}
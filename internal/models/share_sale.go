@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ShareSale struct {
+	ID          uuid.UUID       `db:"id"`
+	UserID      uuid.UUID       `db:"user_id"`
+	StockSymbol string          `db:"stock_symbol"`
+	Quantity    decimal.Decimal `db:"quantity"`
+	Price       decimal.Decimal `db:"price"`
+	GrossINR    decimal.Decimal `db:"gross_inr"`
+	FeesINR     decimal.Decimal `db:"fees_inr"`
+	NetINR      decimal.Decimal `db:"net_inr"`
+	CreatedAt   time.Time       `db:"created_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ShareTransfer struct {
+	ID          uuid.UUID       `db:"id"`
+	FromUserID  uuid.UUID       `db:"from_user_id"`
+	ToUserID    uuid.UUID       `db:"to_user_id"`
+	StockSymbol string          `db:"stock_symbol"`
+	Quantity    decimal.Decimal `db:"quantity"`
+	Price       decimal.Decimal `db:"price"`
+	ValueINR    decimal.Decimal `db:"value_inr"`
+	Note        *string         `db:"note"`
+	CreatedAt   time.Time       `db:"created_at"`
+}
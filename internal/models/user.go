@@ -8,6 +8,7 @@ import (
 
 type User struct {
 	ID        uuid.UUID `db:"id"`
+	PartnerID *string   `db:"partner_id"`
 	CreatedAt time.Time `db:"created_at"`
 }
 
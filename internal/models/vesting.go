@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type VestingType string
+
+const (
+	VestingTypeCliff  VestingType = "CLIFF"
+	VestingTypeLinear VestingType = "LINEAR"
+)
+
+type VestingTranche struct {
+	ID               uuid.UUID       `db:"id"`
+	EventID          uuid.UUID       `db:"event_id"`
+	VestDate         time.Time       `db:"vest_date"`
+	Quantity         decimal.Decimal `db:"quantity"`
+	ReversedQuantity decimal.Decimal `db:"reversed_quantity"`
+	Vested           bool            `db:"vested"`
+	VestedAt         *time.Time      `db:"vested_at"`
+	CreatedAt        time.Time       `db:"created_at"`
+}
+
+func (t VestingTranche) Outstanding() decimal.Decimal {
+	return t.Quantity.Sub(t.ReversedQuantity)
+}
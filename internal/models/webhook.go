@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type WebhookStatus string
+
+const (
+	WebhookStatusActive   WebhookStatus = "ACTIVE"
+	WebhookStatusDisabled WebhookStatus = "DISABLED"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+type WebhookSubscription struct {
+	ID                  uuid.UUID      `db:"id"`
+	PartnerID           *string        `db:"partner_id"`
+	Name                string         `db:"name"`
+	URL                 string         `db:"url"`
+	EventTypes          pq.StringArray `db:"event_types"`
+	Secret              string         `db:"secret"`
+	Status              WebhookStatus  `db:"status"`
+	ConsecutiveFailures int            `db:"consecutive_failures"`
+	DisabledReason      *string        `db:"disabled_reason"`
+	DisabledAt          *time.Time     `db:"disabled_at"`
+	CreatedAt           time.Time      `db:"created_at"`
+	UpdatedAt           time.Time      `db:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID             `db:"id"`
+	SubscriptionID uuid.UUID             `db:"subscription_id"`
+	MessageID      uuid.UUID             `db:"message_id"`
+	EventType      string                `db:"event_type"`
+	Payload        []byte                `db:"payload"`
+	Status         WebhookDeliveryStatus `db:"status"`
+	Attempts       int                   `db:"attempts"`
+	NextAttemptAt  time.Time             `db:"next_attempt_at"`
+	LastStatusCode *int                  `db:"last_status_code"`
+	LastError      *string               `db:"last_error"`
+	DeliveredAt    *time.Time            `db:"delivered_at"`
+	CreatedAt      time.Time             `db:"created_at"`
+	UpdatedAt      time.Time             `db:"updated_at"`
+}
+
+type WebhookDeliveryAttempt struct {
+	ID           uuid.UUID `db:"id"`
+	DeliveryID   uuid.UUID `db:"delivery_id"`
+	Attempt      int       `db:"attempt"`
+	StatusCode   *int      `db:"status_code"`
+	Error        *string   `db:"error"`
+	ResponseBody *string   `db:"response_body"`
+	DurationMs   int64     `db:"duration_ms"`
+	CreatedAt    time.Time `db:"created_at"`
+}
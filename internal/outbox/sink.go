@@ -0,0 +1,26 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Message struct {
+	ID             uuid.UUID       `json:"id"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	Type           string          `json:"type"`
+	AggregateType  string          `json:"aggregate_type"`
+	AggregateID    string          `json:"aggregate_id"`
+	PartitionKey   string          `json:"partition_key"`
+	Sequence       int64           `json:"sequence"`
+	Payload        json.RawMessage `json:"payload"`
+	OccurredAt     time.Time       `json:"occurred_at"`
+}
+
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, msg Message) error
+}
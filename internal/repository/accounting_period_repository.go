@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+const accountingPeriodColumns = `id, period, starts_at, ends_at, status, closed_at, closed_by, locked_at, locked_by,
+	reopened_at, reopened_by, created_at`
+
+type AccountingPeriodRepository struct {
+	db *sqlx.DB
+}
+
+func NewAccountingPeriodRepository(db *sqlx.DB) *AccountingPeriodRepository {
+	return &AccountingPeriodRepository{db: db}
+}
+
+func (r *AccountingPeriodRepository) Ensure(ctx context.Context, tx *sqlx.Tx, period *models.AccountingPeriod) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO accounting_periods (id, period, starts_at, ends_at, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (period) DO NOTHING
+	`, period.ID, period.Period, period.StartsAt, period.EndsAt, period.Status, period.CreatedAt)
+	return err
+}
+
+func (r *AccountingPeriodRepository) GetForUpdate(ctx context.Context, tx *sqlx.Tx, period string) (*models.AccountingPeriod, error) {
+	p := &models.AccountingPeriod{}
+	err := tx.GetContext(ctx, p, `
+		SELECT `+accountingPeriodColumns+`
+		FROM accounting_periods WHERE period = $1
+		FOR UPDATE
+	`, period)
+	return p, err
+}
+
+func (r *AccountingPeriodRepository) GetByPeriod(ctx context.Context, period string) (*models.AccountingPeriod, error) {
+	p := &models.AccountingPeriod{}
+	err := r.db.GetContext(ctx, p, `
+		SELECT `+accountingPeriodColumns+`
+		FROM accounting_periods WHERE period = $1
+	`, period)
+	return p, err
+}
+
+func (r *AccountingPeriodRepository) List(ctx context.Context) ([]models.AccountingPeriod, error) {
+	var periods []models.AccountingPeriod
+	err := r.db.SelectContext(ctx, &periods, `
+		SELECT `+accountingPeriodColumns+`
+		FROM accounting_periods
+		ORDER BY period DESC
+	`)
+	return periods, err
+}
+
+func (r *AccountingPeriodRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, period *models.AccountingPeriod) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE accounting_periods
+		SET status = $2, closed_at = $3, closed_by = $4, locked_at = $5, locked_by = $6,
+			reopened_at = $7, reopened_by = $8
+		WHERE id = $1
+	`, period.ID, period.Status, period.ClosedAt, period.ClosedBy, period.LockedAt, period.LockedBy,
+		period.ReopenedAt, period.ReopenedBy)
+	return err
+}
+
+func (r *AccountingPeriodRepository) SnapshotBalances(ctx context.Context, tx *sqlx.Tx, period *models.AccountingPeriod) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM accounting_period_balances WHERE period_id = $1`, period.ID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO accounting_period_balances (period_id, account_id, opening_debit, opening_credit,
+			period_debit, period_credit, closing_balance)
+		SELECT $1, a.id,
+			COALESCE(SUM(le.debit) FILTER (WHERE le.created_at < $2), 0),
+			COALESCE(SUM(le.credit) FILTER (WHERE le.created_at < $2), 0),
+			COALESCE(SUM(le.debit) FILTER (WHERE le.created_at >= $2), 0),
+			COALESCE(SUM(le.credit) FILTER (WHERE le.created_at >= $2), 0),
+			CASE WHEN a.account_type IN ('ASSET', 'EXPENSE')
+				THEN COALESCE(SUM(le.debit - le.credit), 0)
+				ELSE COALESCE(SUM(le.credit - le.debit), 0)
+			END
+		FROM ledger_accounts a
+		JOIN ledger_entries le ON le.account_id = a.id AND le.created_at < $3
+		GROUP BY a.id
+	`, period.ID, period.StartsAt, period.EndsAt)
+	return err
+}
+
+func (r *AccountingPeriodRepository) ListBalances(ctx context.Context, periodID uuid.UUID) ([]models.AccountingPeriodBalance, error) {
+	var balances []models.AccountingPeriodBalance
+	err := r.db.SelectContext(ctx, &balances, `
+		SELECT b.period_id, b.account_id, a.code AS account_code, a.name AS account_name, a.account_type,
+			b.opening_debit, b.opening_credit, b.period_debit, b.period_credit, b.closing_balance
+		FROM accounting_period_balances b
+		JOIN ledger_accounts a ON a.id = b.account_id
+		WHERE b.period_id = $1
+		ORDER BY a.code
+	`, periodID)
+	return balances, err
+}
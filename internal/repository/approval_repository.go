@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+type ApprovalRepository struct {
+	db *sqlx.DB
+}
+
+func NewApprovalRepository(db *sqlx.DB) *ApprovalRepository {
+	return &ApprovalRepository{db: db}
+}
+
+func (r *ApprovalRepository) Create(ctx context.Context, tx *sqlx.Tx, approval *models.RewardApproval) error {
+	query := `
+		INSERT INTO reward_approvals (id, event_id, status, value_inr, requested_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		approval.ID, approval.EventID, approval.Status, approval.ValueINR,
+		approval.RequestedBy, approval.ExpiresAt, approval.CreatedAt)
+	return err
+}
+
+func (r *ApprovalRepository) GetByEventIDForUpdate(ctx context.Context, tx *sqlx.Tx, eventID uuid.UUID) (*models.RewardApproval, error) {
+	approval := &models.RewardApproval{}
+	err := tx.GetContext(ctx, approval, `
+		SELECT id, event_id, status, value_inr, requested_by, decided_by, reason, expires_at, decided_at, created_at
+		FROM reward_approvals WHERE event_id = $1
+		FOR UPDATE
+	`, eventID)
+	return approval, err
+}
+
+func (r *ApprovalRepository) UpdateDecision(ctx context.Context, tx *sqlx.Tx, approval *models.RewardApproval) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE reward_approvals
+		SET status = $2, decided_by = $3, reason = $4, decided_at = $5
+		WHERE id = $1
+	`, approval.ID, approval.Status, approval.DecidedBy, approval.Reason, approval.DecidedAt)
+	return err
+}
+
+func (r *ApprovalRepository) ListByStatus(ctx context.Context, status models.ApprovalStatus) ([]models.RewardApproval, error) {
+	var approvals []models.RewardApproval
+	err := r.db.SelectContext(ctx, &approvals, `
+		SELECT id, event_id, status, value_inr, requested_by, decided_by, reason, expires_at, decided_at, created_at
+		FROM reward_approvals
+		WHERE status = $1
+		ORDER BY created_at
+	`, status)
+	return approvals, err
+}
+
+func (r *ApprovalRepository) ListExpiredPendingIDs(ctx context.Context, asOf time.Time) ([]uuid.UUID, error) {
+	var eventIDs []uuid.UUID
+	err := r.db.SelectContext(ctx, &eventIDs, `
+		SELECT event_id FROM reward_approvals
+		WHERE status = 'PENDING' AND expires_at <= $1
+		ORDER BY expires_at
+	`, asOf)
+	return eventIDs, err
+}
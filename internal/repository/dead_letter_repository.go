@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+const deadLetterColumns = `id, event_id, source, payload, error, error_class, attempts, status,
+	replay_count, replayed_by, replayed_at, created_at, updated_at`
+
+type DeadLetterRepository struct {
+	db *sqlx.DB
+}
+
+func NewDeadLetterRepository(db *sqlx.DB) *DeadLetterRepository {
+	return &DeadLetterRepository{db: db}
+}
+
+func (r *DeadLetterRepository) Upsert(ctx context.Context, letter *models.RewardDeadLetter) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO reward_dead_letters (id, event_id, source, payload, error, error_class, attempts, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 'OPEN', $8, $8)
+		ON CONFLICT (event_id) DO UPDATE
+		SET source = EXCLUDED.source,
+			payload = EXCLUDED.payload,
+			error = EXCLUDED.error,
+			error_class = EXCLUDED.error_class,
+			attempts = reward_dead_letters.attempts + EXCLUDED.attempts,
+			status = 'OPEN',
+			updated_at = EXCLUDED.updated_at
+	`, letter.ID, letter.EventID, letter.Source, letter.Payload, letter.Error,
+		letter.ErrorClass, letter.Attempts, letter.CreatedAt)
+	return err
+}
+
+func (r *DeadLetterRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RewardDeadLetter, error) {
+	letter := &models.RewardDeadLetter{}
+	err := r.db.GetContext(ctx, letter, `
+		SELECT `+deadLetterColumns+`
+		FROM reward_dead_letters WHERE id = $1
+	`, id)
+	return letter, err
+}
+
+func (r *DeadLetterRepository) List(ctx context.Context, status models.DeadLetterStatus, errorClass models.DeadLetterErrorClass) ([]models.RewardDeadLetter, error) {
+	var letters []models.RewardDeadLetter
+	err := r.db.SelectContext(ctx, &letters, `
+		SELECT `+deadLetterColumns+`
+		FROM reward_dead_letters
+		WHERE status = $1 AND ($2::text = '' OR error_class = $2)
+		ORDER BY created_at
+	`, status, errorClass)
+	return letters, err
+}
+
+func (r *DeadLetterRepository) UpdatePayload(ctx context.Context, id uuid.UUID, payload []byte) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE reward_dead_letters
+		SET payload = $2, updated_at = $3
+		WHERE id = $1
+	`, id, payload, time.Now())
+	return err
+}
+
+func (r *DeadLetterRepository) RecordReplay(ctx context.Context, letter *models.RewardDeadLetter) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE reward_dead_letters
+		SET status = $2, error = $3, error_class = $4, attempts = $5, replay_count = $6,
+			replayed_by = $7, replayed_at = $8, updated_at = $8
+		WHERE id = $1
+	`, letter.ID, letter.Status, letter.Error, letter.ErrorClass, letter.Attempts,
+		letter.ReplayCount, letter.ReplayedBy, letter.ReplayedAt)
+	return err
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+const dematWithdrawalColumns = `id, user_id, stock_symbol, quantity, price, value_inr, dp_id, client_id, status,
+	depository_ref, failure_reason, submitted_at, completed_at, failed_at, created_at, updated_at`
+
+type DematWithdrawalRepository struct {
+	db *sqlx.DB
+}
+
+func NewDematWithdrawalRepository(db *sqlx.DB) *DematWithdrawalRepository {
+	return &DematWithdrawalRepository{db: db}
+}
+
+func (r *DematWithdrawalRepository) Create(ctx context.Context, tx *sqlx.Tx, w *models.DematWithdrawal) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO demat_withdrawals (id, user_id, stock_symbol, quantity, price, value_inr, dp_id, client_id,
+			status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+		ON CONFLICT (id) DO NOTHING
+	`, w.ID, w.UserID, w.StockSymbol, w.Quantity, w.Price, w.ValueINR, w.DPID, w.ClientID, w.Status, w.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	return inserted > 0, err
+}
+
+func (r *DematWithdrawalRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.DematWithdrawal, error) {
+	w := &models.DematWithdrawal{}
+	err := r.db.GetContext(ctx, w, `
+		SELECT `+dematWithdrawalColumns+`
+		FROM demat_withdrawals WHERE id = $1
+	`, id)
+	return w, err
+}
+
+func (r *DematWithdrawalRepository) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*models.DematWithdrawal, error) {
+	w := &models.DematWithdrawal{}
+	err := tx.GetContext(ctx, w, `
+		SELECT `+dematWithdrawalColumns+`
+		FROM demat_withdrawals WHERE id = $1
+		FOR UPDATE
+	`, id)
+	return w, err
+}
+
+func (r *DematWithdrawalRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.DematWithdrawal, error) {
+	var withdrawals []models.DematWithdrawal
+	err := r.db.SelectContext(ctx, &withdrawals, `
+		SELECT `+dematWithdrawalColumns+`
+		FROM demat_withdrawals
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`, userID)
+	return withdrawals, err
+}
+
+func (r *DematWithdrawalRepository) ListByStatus(ctx context.Context, status models.WithdrawalStatus) ([]models.DematWithdrawal, error) {
+	var withdrawals []models.DematWithdrawal
+	err := r.db.SelectContext(ctx, &withdrawals, `
+		SELECT `+dematWithdrawalColumns+`
+		FROM demat_withdrawals
+		WHERE status = $1
+		ORDER BY created_at
+	`, status)
+	return withdrawals, err
+}
+
+func (r *DematWithdrawalRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, w *models.DematWithdrawal) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE demat_withdrawals
+		SET status = $2, depository_ref = $3, failure_reason = $4, submitted_at = $5, completed_at = $6,
+			failed_at = $7, updated_at = $8
+		WHERE id = $1
+	`, w.ID, w.Status, w.DepositoryRef, w.FailureReason, w.SubmittedAt, w.CompletedAt, w.FailedAt, time.Now())
+	return err
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"stocky/internal/models"
+)
+
+type InventoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewInventoryRepository(db *sqlx.DB) *InventoryRepository {
+	return &InventoryRepository{db: db}
+}
+
+func (r *InventoryRepository) GetForUpdate(ctx context.Context, tx *sqlx.Tx, symbol string, defaults *models.InventoryPool) (*models.InventoryPool, error) {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO inventory_pools (symbol, quantity, replenish_threshold, replenish_quantity, updated_at)
+		VALUES ($1, 0, $2, $3, $4)
+		ON CONFLICT (symbol) DO NOTHING
+	`, symbol, defaults.ReplenishThreshold, defaults.ReplenishQuantity, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	pool := &models.InventoryPool{}
+	err = tx.GetContext(ctx, pool, `
+		SELECT symbol, quantity, cost, replenish_threshold, replenish_quantity, updated_at
+		FROM inventory_pools WHERE symbol = $1
+		FOR UPDATE
+	`, symbol)
+	return pool, err
+}
+
+func (r *InventoryRepository) AddMovement(ctx context.Context, tx *sqlx.Tx, movement *models.InventoryMovement) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE inventory_pools SET quantity = quantity + $2, cost = cost + $3, updated_at = $4 WHERE symbol = $1
+	`, movement.Symbol, movement.Quantity, movement.Cost, movement.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO inventory_movements (id, symbol, quantity, cost, movement_type, reference_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, movement.ID, movement.Symbol, movement.Quantity, movement.Cost, movement.MovementType, movement.ReferenceID, movement.CreatedAt)
+	return err
+}
+
+func (r *InventoryRepository) UpdateSettings(ctx context.Context, tx *sqlx.Tx, pool *models.InventoryPool) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE inventory_pools
+		SET replenish_threshold = $2, replenish_quantity = $3, updated_at = $4
+		WHERE symbol = $1
+	`, pool.Symbol, pool.ReplenishThreshold, pool.ReplenishQuantity, time.Now())
+	return err
+}
+
+func (r *InventoryRepository) ListPools(ctx context.Context) ([]models.InventoryPool, error) {
+	var pools []models.InventoryPool
+	err := r.db.SelectContext(ctx, &pools, `
+		SELECT symbol, quantity, cost, replenish_threshold, replenish_quantity, updated_at
+		FROM inventory_pools
+		ORDER BY symbol
+	`)
+	return pools, err
+}
+
+func (r *InventoryRepository) GetAllocatedBySymbol(ctx context.Context) (map[string]decimal.Decimal, error) {
+	type result struct {
+		Symbol   string          `db:"symbol"`
+		Quantity decimal.Decimal `db:"quantity"`
+	}
+
+	var results []result
+	err := r.db.SelectContext(ctx, &results, `
+		SELECT symbol, -SUM(quantity) as quantity
+		FROM inventory_movements
+		WHERE movement_type IN ('ALLOCATION', 'REVERSAL')
+		GROUP BY symbol
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]decimal.Decimal)
+	for _, r := range results {
+		totals[r.Symbol] = r.Quantity
+	}
+	return totals, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+const ledgerExportColumns = `id, export_date, format, voucher_count, entry_count, total_debit, total_credit,
+	checksum, content, imported_at, imported_by, created_at`
+
+const ledgerExportSummaryColumns = `id, export_date, format, voucher_count, entry_count, total_debit, total_credit,
+	checksum, ''::bytea AS content, imported_at, imported_by, created_at`
+
+type LedgerExportRepository struct {
+	db *sqlx.DB
+}
+
+func NewLedgerExportRepository(db *sqlx.DB) *LedgerExportRepository {
+	return &LedgerExportRepository{db: db}
+}
+
+func (r *LedgerExportRepository) Create(ctx context.Context, export *models.LedgerExport) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO ledger_exports (id, export_date, format, voucher_count, entry_count, total_debit, total_credit,
+			checksum, content, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (export_date, format) DO NOTHING
+	`, export.ID, export.ExportDate, export.Format, export.VoucherCount, export.EntryCount,
+		export.TotalDebit, export.TotalCredit, export.Checksum, export.Content, export.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	return inserted > 0, err
+}
+
+func (r *LedgerExportRepository) GetByDate(ctx context.Context, date time.Time, format models.ExportFormat) (*models.LedgerExport, error) {
+	export := &models.LedgerExport{}
+	err := r.db.GetContext(ctx, export, `
+		SELECT `+ledgerExportColumns+`
+		FROM ledger_exports WHERE export_date = $1 AND format = $2
+	`, date, format)
+	return export, err
+}
+
+func (r *LedgerExportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.LedgerExport, error) {
+	export := &models.LedgerExport{}
+	err := r.db.GetContext(ctx, export, `
+		SELECT `+ledgerExportColumns+`
+		FROM ledger_exports WHERE id = $1
+	`, id)
+	return export, err
+}
+
+func (r *LedgerExportRepository) List(ctx context.Context, format models.ExportFormat) ([]models.LedgerExport, error) {
+	var exports []models.LedgerExport
+	err := r.db.SelectContext(ctx, &exports, `
+		SELECT `+ledgerExportSummaryColumns+`
+		FROM ledger_exports
+		WHERE $1 = '' OR format = $1
+		ORDER BY export_date DESC, format
+	`, format)
+	return exports, err
+}
+
+func (r *LedgerExportRepository) MarkImported(ctx context.Context, id uuid.UUID, operator string, at time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE ledger_exports
+		SET imported_at = $2, imported_by = $3
+		WHERE id = $1 AND imported_at IS NULL
+	`, id, at, operator)
+	if err != nil {
+		return false, err
+	}
+	updated, err := result.RowsAffected()
+	return updated > 0, err
+}
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
 	"stocky/internal/models"
 )
 
@@ -18,32 +21,329 @@ func NewLedgerRepository(db *sqlx.DB) *LedgerRepository {
 
 func (r *LedgerRepository) Create(ctx context.Context, tx *sqlx.Tx, entry *models.LedgerEntry) error {
 	query := `
-		INSERT INTO ledger_entries (id, event_id, entry_type, symbol, debit, credit, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO ledger_entries (id, event_id, user_id, account_id, entry_type, symbol, debit, credit, created_at,
+			sequence, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 	_, err := tx.ExecContext(ctx, query,
-		entry.ID, entry.EventID, entry.EntryType, entry.Symbol,
-		entry.Debit, entry.Credit, entry.CreatedAt)
+		entry.ID, entry.EventID, entry.UserID, entry.AccountID, entry.EntryType, entry.Symbol,
+		entry.Debit, entry.Credit, entry.CreatedAt, entry.Sequence, entry.PrevHash, entry.Hash)
 	return err
 }
 
-func (r *LedgerRepository) VerifyBalance(ctx context.Context) (bool, error) {
+func (r *LedgerRepository) GetTotals(ctx context.Context) (decimal.Decimal, decimal.Decimal, error) {
 	var result struct {
-		TotalDebit  string `db:"total_debit"`
-		TotalCredit string `db:"total_credit"`
+		TotalDebit  decimal.Decimal `db:"total_debit"`
+		TotalCredit decimal.Decimal `db:"total_credit"`
 	}
 
 	err := r.db.GetContext(ctx, &result, `
-		SELECT 
-			SUM(debit) as total_debit,
-			SUM(credit) as total_credit
+		SELECT
+			COALESCE(SUM(debit), 0) as total_debit,
+			COALESCE(SUM(credit), 0) as total_credit
 		FROM ledger_entries
 	`)
+	return result.TotalDebit, result.TotalCredit, err
+}
+
+func (r *LedgerRepository) GetWalletBalance(ctx context.Context, userID uuid.UUID) (decimal.Decimal, error) {
+	var balance decimal.Decimal
+	err := r.db.GetContext(ctx, &balance, `
+		SELECT COALESCE(SUM(credit - debit), 0)
+		FROM ledger_entries
+		WHERE user_id = $1 AND entry_type = 'WALLET'
+	`, userID)
+	return balance, err
+}
 
+func (r *LedgerRepository) GetStockValue(ctx context.Context, userID uuid.UUID, symbol string) (decimal.Decimal, error) {
+	var value decimal.Decimal
+	err := r.db.GetContext(ctx, &value, `
+		SELECT COALESCE(SUM(credit - debit), 0)
+		FROM ledger_entries
+		WHERE user_id = $1 AND entry_type = 'STOCK' AND symbol = $2
+	`, userID, symbol)
+	return value, err
+}
+
+func (r *LedgerRepository) EnsureAccount(ctx context.Context, tx *sqlx.Tx, account *models.LedgerAccount) (uuid.UUID, error) {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO ledger_accounts (id, code, name, account_type, user_id, symbol, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (code) DO NOTHING
+	`, account.ID, account.Code, account.Name, account.AccountType, account.UserID, account.Symbol, account.CreatedAt)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var id uuid.UUID
+	err = tx.GetContext(ctx, &id, `SELECT id FROM ledger_accounts WHERE code = $1`, account.Code)
+	return id, err
+}
+
+func (r *LedgerRepository) ListAccountBalances(ctx context.Context, userID *uuid.UUID) ([]models.LedgerAccountBalance, error) {
+	var balances []models.LedgerAccountBalance
+	err := r.db.SelectContext(ctx, &balances, `
+		SELECT a.id, a.code, a.name, a.account_type, a.user_id, a.symbol, a.created_at,
+			COALESCE(SUM(le.debit), 0) AS total_debit,
+			COALESCE(SUM(le.credit), 0) AS total_credit
+		FROM ledger_accounts a
+		LEFT JOIN ledger_entries le ON le.account_id = a.id
+		WHERE $1::uuid IS NULL OR a.user_id = $1
+		GROUP BY a.id
+		ORDER BY a.user_id NULLS FIRST, a.code
+	`, userID)
+	return balances, err
+}
+
+const postingBalanceKey = `CASE
+			WHEN a.user_id IS NULL THEN NULL
+			WHEN le.entry_type = 'WALLET' THEN 'INR'
+			WHEN le.entry_type = 'WITHDRAWAL_HOLD' THEN 'HOLD:' || le.symbol
+			ELSE le.symbol
+		END`
+
+func (r *LedgerRepository) ListUserPostings(ctx context.Context, userID uuid.UUID, from, to time.Time, afterTime *time.Time, afterID *uuid.UUID, limit int) ([]models.LedgerPosting, error) {
+	var postings []models.LedgerPosting
+	err := r.db.SelectContext(ctx, &postings, `
+		SELECT le.id, le.event_id, le.account_id, a.code AS account_code, le.entry_type, le.symbol,
+			le.debit, le.credit, le.created_at, hm.quantity,
+			CASE
+				WHEN le.entry_type IN ('FEE', 'BROKERAGE', 'STT', 'GST', 'EXCHANGE_CHARGES', 'SEBI_CHARGES', 'STAMP_DUTY',
+					'SALE_CHARGES', 'GST_PAYABLE', 'CHARGES_PAYABLE') THEN 'FEE'
+				WHEN EXISTS (SELECT 1 FROM share_sales x WHERE x.id = le.event_id) THEN 'SALE'
+				WHEN EXISTS (SELECT 1 FROM share_transfers x WHERE x.id = le.event_id) THEN 'TRANSFER'
+				WHEN EXISTS (SELECT 1 FROM demat_withdrawals x WHERE x.id = le.event_id) THEN 'WITHDRAWAL'
+				WHEN le.debit > 0 THEN 'REVERSAL'
+				ELSE 'REWARD'
+			END AS kind,
+			`+postingBalanceKey+` AS balance_key
+		FROM ledger_entries le
+		JOIN ledger_accounts a ON a.id = le.account_id
+		LEFT JOIN holding_movements hm ON hm.entry_id = le.id
+		WHERE (a.user_id = $1 OR (a.user_id IS NULL AND le.user_id = $1))
+			AND le.created_at >= $2 AND le.created_at < $3
+			AND ($4::timestamp IS NULL OR (le.created_at, le.id) > ($4::timestamp, $5::uuid))
+		ORDER BY le.created_at, le.id
+		LIMIT $6
+	`, userID, storedTime(from), storedTime(to), afterTime, afterID, limit)
+	return postings, err
+}
+
+func (r *LedgerRepository) GetUserBalances(ctx context.Context, userID uuid.UUID, until time.Time) (map[string]decimal.Decimal, error) {
+	return r.sumUserBalances(ctx, `le.created_at < $2`, userID, storedTime(until))
+}
+
+func (r *LedgerRepository) GetUserBalancesThrough(ctx context.Context, userID uuid.UUID, lastTime time.Time, lastID uuid.UUID) (map[string]decimal.Decimal, error) {
+	return r.sumUserBalances(ctx, `(le.created_at, le.id) <= ($2, $3::uuid)`, userID, lastTime, lastID)
+}
+
+func (r *LedgerRepository) sumUserBalances(ctx context.Context, bound string, args ...interface{}) (map[string]decimal.Decimal, error) {
+	type result struct {
+		BalanceKey string          `db:"balance_key"`
+		Balance    decimal.Decimal `db:"balance"`
+	}
+
+	var results []result
+	err := r.db.SelectContext(ctx, &results, `
+		SELECT `+postingBalanceKey+` AS balance_key, SUM(le.credit - le.debit) AS balance
+		FROM ledger_entries le
+		JOIN ledger_accounts a ON a.id = le.account_id
+		WHERE a.user_id = $1 AND `+bound+`
+		GROUP BY 1
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]decimal.Decimal)
+	for _, r := range results {
+		balances[r.BalanceKey] = r.Balance
+	}
+	return balances, nil
+}
+
+func (r *LedgerRepository) GetUserShareBalances(ctx context.Context, userID uuid.UUID, until time.Time) (map[string]decimal.Decimal, error) {
+	return r.sumUserShareBalances(ctx, `COALESCE(le.created_at, hm.created_at) < $2`, userID, storedTime(until))
+}
+
+func (r *LedgerRepository) GetUserShareBalancesThrough(ctx context.Context, userID uuid.UUID, lastTime time.Time, lastID uuid.UUID) (map[string]decimal.Decimal, error) {
+	return r.sumUserShareBalances(ctx, `(COALESCE(le.created_at, hm.created_at) < $2 OR (le.created_at = $2 AND le.id <= $3::uuid))`,
+		userID, lastTime, lastID)
+}
+
+func (r *LedgerRepository) sumUserShareBalances(ctx context.Context, bound string, args ...interface{}) (map[string]decimal.Decimal, error) {
+	var rows []struct {
+		Symbol   string          `db:"symbol"`
+		Quantity decimal.Decimal `db:"quantity"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT hm.symbol, SUM(hm.quantity) AS quantity
+		FROM holding_movements hm
+		LEFT JOIN ledger_entries le ON le.id = hm.entry_id
+		WHERE hm.user_id = $1 AND `+bound+`
+		GROUP BY hm.symbol
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]decimal.Decimal)
+	for _, row := range rows {
+		balances[row.Symbol] = row.Quantity
+	}
+	return balances, nil
+}
+
+func (r *LedgerRepository) LockChain(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('ledger_chain'))`)
+	return err
+}
+
+func (r *LedgerRepository) GetPeriodStatus(ctx context.Context, tx *sqlx.Tx, period string) (models.PeriodStatus, error) {
+	var status models.PeriodStatus
+	err := tx.GetContext(ctx, &status, `SELECT status FROM accounting_periods WHERE period = $1`, period)
+	if err == sql.ErrNoRows {
+		return models.PeriodStatusOpen, nil
+	}
+	return status, err
+}
+
+func (r *LedgerRepository) GetChainHead(ctx context.Context, tx *sqlx.Tx) (int64, string, error) {
+	var head struct {
+		Sequence int64  `db:"sequence"`
+		Hash     string `db:"hash"`
+	}
+	err := tx.GetContext(ctx, &head, `SELECT sequence, hash FROM ledger_entries ORDER BY sequence DESC LIMIT 1`)
+	if err == sql.ErrNoRows {
+		return 0, "", nil
+	}
+	return head.Sequence, head.Hash, err
+}
+
+func (r *LedgerRepository) ListChain(ctx context.Context, afterSequence int64, limit int) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	err := r.db.SelectContext(ctx, &entries, `
+		SELECT id, event_id, user_id, account_id, entry_type, symbol, debit, credit, created_at, sequence, prev_hash, hash
+		FROM ledger_entries
+		WHERE sequence > $1
+		ORDER BY sequence
+		LIMIT $2
+	`, afterSequence, limit)
+	return entries, err
+}
+
+func (r *LedgerRepository) AddHoldingMovement(ctx context.Context, tx *sqlx.Tx, movement *models.HoldingMovement, snapshotsFrom time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO holding_movements (id, user_id, symbol, quantity, reference_id, entry_id, effective_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, movement.ID, movement.UserID, movement.Symbol, movement.Quantity, movement.ReferenceID, movement.EntryID,
+		storedTime(movement.EffectiveAt), movement.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO holding_balances (user_id, symbol, quantity, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, symbol) DO UPDATE
+		SET quantity = holding_balances.quantity + EXCLUDED.quantity, updated_at = EXCLUDED.updated_at
+	`, movement.UserID, movement.Symbol, movement.Quantity, movement.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO holding_snapshots (user_id, snapshot_date, symbol, quantity)
+		SELECT $1, snapshot_date, $2, $3
+		FROM holding_snapshot_days
+		WHERE snapshot_date >= $4
+		ON CONFLICT (user_id, snapshot_date, symbol) DO UPDATE
+		SET quantity = holding_snapshots.quantity + EXCLUDED.quantity
+	`, movement.UserID, movement.Symbol, movement.Quantity, storedDate(snapshotsFrom))
+	return err
+}
+
+func (r *LedgerRepository) GetHoldingBalances(ctx context.Context, userID uuid.UUID) (map[string]decimal.Decimal, error) {
+	var rows []struct {
+		Symbol   string          `db:"symbol"`
+		Quantity decimal.Decimal `db:"quantity"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT symbol, quantity FROM holding_balances
+		WHERE user_id = $1 AND quantity <> 0
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]decimal.Decimal)
+	for _, row := range rows {
+		balances[row.Symbol] = row.Quantity
+	}
+	return balances, nil
+}
+
+func (r *LedgerRepository) SnapshotHoldings(ctx context.Context, tx *sqlx.Tx, date time.Time) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO holding_snapshot_days (snapshot_date, created_at)
+		VALUES ($1, $2)
+		ON CONFLICT (snapshot_date) DO NOTHING
+	`, storedDate(date), time.Now())
 	if err != nil {
 		return false, err
 	}
+	inserted, err := result.RowsAffected()
+	if err != nil || inserted == 0 {
+		return false, err
+	}
 
-	return result.TotalDebit == result.TotalCredit, nil
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO holding_snapshots (user_id, snapshot_date, symbol, quantity)
+		SELECT b.user_id, $1, b.symbol, b.quantity - COALESCE(later.quantity, 0)
+		FROM holding_balances b
+		LEFT JOIN (
+			SELECT user_id, symbol, SUM(quantity) AS quantity
+			FROM holding_movements
+			WHERE effective_at >= $2
+			GROUP BY user_id, symbol
+		) later ON later.user_id = b.user_id AND later.symbol = b.symbol
+		WHERE b.quantity - COALESCE(later.quantity, 0) <> 0
+	`, storedDate(date), storedTime(date.AddDate(0, 0, 1)))
+	return true, err
 }
 
+func (r *LedgerRepository) GetHoldingSnapshots(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.HoldingSnapshot, error) {
+	var snapshots []models.HoldingSnapshot
+	err := r.db.SelectContext(ctx, &snapshots, `
+		SELECT d.snapshot_date, s.symbol, COALESCE(s.quantity, 0) AS quantity
+		FROM holding_snapshot_days d
+		LEFT JOIN holding_snapshots s ON s.snapshot_date = d.snapshot_date AND s.user_id = $1 AND s.quantity <> 0
+		WHERE d.snapshot_date BETWEEN $2 AND $3
+		ORDER BY d.snapshot_date, s.symbol
+	`, userID, storedDate(from), storedDate(to))
+	return snapshots, err
+}
+
+func (r *LedgerRepository) GetHoldingBalancesAt(ctx context.Context, userID uuid.UUID, until time.Time) (map[string]decimal.Decimal, error) {
+	var rows []struct {
+		Symbol   string          `db:"symbol"`
+		Quantity decimal.Decimal `db:"quantity"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT symbol, SUM(quantity) AS quantity
+		FROM holding_movements
+		WHERE user_id = $1 AND effective_at < $2
+		GROUP BY symbol
+		HAVING SUM(quantity) <> 0
+	`, userID, storedTime(until))
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]decimal.Decimal)
+	for _, row := range rows {
+		balances[row.Symbol] = row.Quantity
+	}
+	return balances, nil
+}
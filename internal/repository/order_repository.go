@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"stocky/internal/models"
+)
+
+const brokerOrderColumns = `id, symbol, side, purpose, quantity, reward_quantity, booked_value, status,
+	broker_order_id, failure_reason, submitted_at, filled_at, created_at`
+
+type OrderRepository struct {
+	db *sqlx.DB
+}
+
+func NewOrderRepository(db *sqlx.DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+func (r *OrderRepository) Create(ctx context.Context, tx *sqlx.Tx, order *models.BrokerOrder) error {
+	query := `
+		INSERT INTO broker_orders (id, symbol, side, purpose, quantity, reward_quantity, booked_value, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		order.ID, order.Symbol, order.Side, order.Purpose, order.Quantity, order.RewardQuantity,
+		order.BookedValue, order.Status, order.CreatedAt)
+	return err
+}
+
+func (r *OrderRepository) LinkReward(ctx context.Context, tx *sqlx.Tx, orderID, eventID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO broker_order_rewards (order_id, event_id) VALUES ($1, $2)
+	`, orderID, eventID)
+	return err
+}
+
+func (r *OrderRepository) GetLinkedEventIDs(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID) ([]uuid.UUID, error) {
+	var eventIDs []uuid.UUID
+	err := tx.SelectContext(ctx, &eventIDs, `
+		SELECT event_id FROM broker_order_rewards WHERE order_id = $1
+	`, orderID)
+	return eventIDs, err
+}
+
+func (r *OrderRepository) GetBookedFees(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID) (map[models.LedgerEntryType]decimal.Decimal, error) {
+	type result struct {
+		EntryType models.LedgerEntryType `db:"entry_type"`
+		Amount    decimal.Decimal        `db:"amount"`
+	}
+
+	var results []result
+	err := tx.SelectContext(ctx, &results, `
+		SELECT CASE WHEN le.entry_type = 'FEE' THEN 'BROKERAGE' ELSE le.entry_type END AS entry_type,
+			SUM(le.debit - le.credit) AS amount
+		FROM ledger_entries le
+		JOIN broker_order_rewards bor ON bor.event_id = le.event_id
+		WHERE bor.order_id = $1
+			AND le.entry_type IN ('FEE', 'BROKERAGE', 'STT', 'GST', 'EXCHANGE_CHARGES', 'SEBI_CHARGES', 'STAMP_DUTY')
+		GROUP BY 1
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	booked := make(map[models.LedgerEntryType]decimal.Decimal)
+	for _, r := range results {
+		booked[r.EntryType] = r.Amount
+	}
+	return booked, nil
+}
+
+func (r *OrderRepository) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*models.BrokerOrder, error) {
+	order := &models.BrokerOrder{}
+	err := tx.GetContext(ctx, order, `
+		SELECT `+brokerOrderColumns+`
+		FROM broker_orders WHERE id = $1
+		FOR UPDATE
+	`, id)
+	return order, err
+}
+
+func (r *OrderRepository) ListByStatus(ctx context.Context, status models.BrokerOrderStatus) ([]models.BrokerOrder, error) {
+	var orders []models.BrokerOrder
+	err := r.db.SelectContext(ctx, &orders, `
+		SELECT `+brokerOrderColumns+`
+		FROM broker_orders
+		WHERE status = $1
+		ORDER BY created_at
+	`, status)
+	return orders, err
+}
+
+func (r *OrderRepository) HasOpenReplenishment(ctx context.Context, tx *sqlx.Tx, symbol string) (bool, error) {
+	var exists bool
+	err := tx.GetContext(ctx, &exists, `
+		SELECT EXISTS (
+			SELECT 1 FROM broker_orders
+			WHERE symbol = $1 AND purpose = 'REPLENISHMENT' AND status IN ('PENDING', 'SUBMITTED')
+		)
+	`, symbol)
+	return exists, err
+}
+
+func (r *OrderRepository) Update(ctx context.Context, tx *sqlx.Tx, order *models.BrokerOrder) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE broker_orders
+		SET status = $2, broker_order_id = $3, failure_reason = $4, submitted_at = $5, filled_at = $6
+		WHERE id = $1
+	`, order.ID, order.Status, order.BrokerOrderID, order.FailureReason, order.SubmittedAt, order.FilledAt)
+	return err
+}
+
+func (r *OrderRepository) CreateFill(ctx context.Context, tx *sqlx.Tx, fill *models.BrokerFill) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO broker_fills (id, order_id, broker_fill_id, quantity, price, fees,
+			brokerage, stt, gst, exchange_charges, sebi_charges, stamp_duty, executed_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (broker_fill_id) DO NOTHING
+	`, fill.ID, fill.OrderID, fill.BrokerFillID, fill.Quantity, fill.Price, fill.Fees,
+		fill.Brokerage, fill.STT, fill.GST, fill.ExchangeCharges, fill.SEBICharges, fill.StampDuty,
+		fill.ExecutedAt, fill.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	return inserted > 0, err
+}
+
+func (r *OrderRepository) GetFills(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID) ([]models.BrokerFill, error) {
+	var fills []models.BrokerFill
+	err := tx.SelectContext(ctx, &fills, `
+		SELECT id, order_id, broker_fill_id, quantity, price, fees,
+			brokerage, stt, gst, exchange_charges, sebi_charges, stamp_duty, executed_at, created_at
+		FROM broker_fills
+		WHERE order_id = $1
+		ORDER BY executed_at
+	`, orderID)
+	return fills, err
+}
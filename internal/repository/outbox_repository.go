@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+type OutboxRepository struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) Add(ctx context.Context, tx *sqlx.Tx, event *models.OutboxEvent) error {
+	err := tx.GetContext(ctx, &event.Sequence, `
+		INSERT INTO outbox_partitions (partition_key, last_sequence)
+		VALUES ($1, 1)
+		ON CONFLICT (partition_key) DO UPDATE SET last_sequence = outbox_partitions.last_sequence + 1
+		RETURNING last_sequence
+	`, event.PartitionKey)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO outbox_events (message_id, event_type, aggregate_type, aggregate_id, partition_key, sequence, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, event.MessageID, event.EventType, event.AggregateType, event.AggregateID,
+		event.PartitionKey, event.Sequence, event.Payload, event.CreatedAt)
+	return err
+}
+
+func (r *OutboxRepository) ClaimLease(ctx context.Context, sink string, leaseID uuid.UUID, now, until time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO outbox_sink_leases (sink, lease_id, leased_until)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (sink) DO UPDATE SET lease_id = EXCLUDED.lease_id, leased_until = EXCLUDED.leased_until
+		WHERE outbox_sink_leases.leased_until < $4
+	`, sink, leaseID, until, now)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func (r *OutboxRepository) RenewLease(ctx context.Context, sink string, leaseID uuid.UUID, until time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE outbox_sink_leases SET leased_until = $3
+		WHERE sink = $1 AND lease_id = $2
+	`, sink, leaseID, until)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func (r *OutboxRepository) ReleaseLease(ctx context.Context, sink string, leaseID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM outbox_sink_leases WHERE sink = $1 AND lease_id = $2
+	`, sink, leaseID)
+	return err
+}
+
+func (r *OutboxRepository) ListUndelivered(ctx context.Context, sink, afterPartition string, afterSequence int64, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.db.SelectContext(ctx, &events, `
+		SELECT e.id, e.message_id, e.event_type, e.aggregate_type, e.aggregate_id, e.partition_key, e.sequence, e.payload, e.created_at
+		FROM outbox_events e
+		WHERE (e.partition_key, e.sequence) > ($2, $3)
+		  AND NOT EXISTS (
+			SELECT 1 FROM outbox_deliveries d WHERE d.sink = $1 AND d.event_id = e.id
+		  )
+		ORDER BY e.partition_key, e.sequence
+		LIMIT $4
+	`, sink, afterPartition, afterSequence, limit)
+	return events, err
+}
+
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, sink string, eventID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO outbox_deliveries (sink, event_id)
+		VALUES ($1, $2)
+		ON CONFLICT (sink, event_id) DO NOTHING
+	`, sink, eventID)
+	return err
+}
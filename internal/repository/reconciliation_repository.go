@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+const reconciliationRunColumns = `id, status, discrepancy_count, started_at, finished_at`
+
+const reconciliationDiscrepancyColumns = `id, run_id, check_name, reference_id, symbol, expected, actual, message, created_at`
+
+const rewardValueTolerance = `0.001`
+
+type ReconciliationRepository struct {
+	db *sqlx.DB
+}
+
+func NewReconciliationRepository(db *sqlx.DB) *ReconciliationRepository {
+	return &ReconciliationRepository{db: db}
+}
+
+func (r *ReconciliationRepository) CreateRun(ctx context.Context, tx *sqlx.Tx, run *models.ReconciliationRun) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO reconciliation_runs (id, status, discrepancy_count, started_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, run.ID, run.Status, run.DiscrepancyCount, run.StartedAt, run.FinishedAt)
+	return err
+}
+
+func (r *ReconciliationRepository) CreateDiscrepancy(ctx context.Context, tx *sqlx.Tx, d *models.ReconciliationDiscrepancy) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO reconciliation_discrepancies (id, run_id, check_name, reference_id, symbol, expected, actual, message, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, d.ID, d.RunID, d.CheckName, d.ReferenceID, d.Symbol, d.Expected, d.Actual, d.Message, d.CreatedAt)
+	return err
+}
+
+func (r *ReconciliationRepository) GetLatestRun(ctx context.Context) (*models.ReconciliationRun, error) {
+	run := &models.ReconciliationRun{}
+	err := r.db.GetContext(ctx, run, `
+		SELECT `+reconciliationRunColumns+`
+		FROM reconciliation_runs
+		ORDER BY started_at DESC
+		LIMIT 1
+	`)
+	return run, err
+}
+
+func (r *ReconciliationRepository) ListDiscrepancies(ctx context.Context, runID uuid.UUID) ([]models.ReconciliationDiscrepancy, error) {
+	var discrepancies []models.ReconciliationDiscrepancy
+	err := r.db.SelectContext(ctx, &discrepancies, `
+		SELECT `+reconciliationDiscrepancyColumns+`
+		FROM reconciliation_discrepancies
+		WHERE run_id = $1
+		ORDER BY check_name, symbol, reference_id
+	`, runID)
+	return discrepancies, err
+}
+
+func (r *ReconciliationRepository) FindEventImbalances(ctx context.Context) ([]models.ReconciliationDiscrepancy, error) {
+	var discrepancies []models.ReconciliationDiscrepancy
+	err := r.db.SelectContext(ctx, &discrepancies, `
+		SELECT 'EVENT_BALANCE' AS check_name, event_id AS reference_id,
+			SUM(debit) AS expected, SUM(credit) AS actual,
+			'debits and credits posted for the event differ' AS message
+		FROM ledger_entries
+		GROUP BY event_id
+		HAVING SUM(debit) <> SUM(credit)
+	`)
+	return discrepancies, err
+}
+
+func (r *ReconciliationRepository) FindRewardValueMismatches(ctx context.Context) ([]models.ReconciliationDiscrepancy, error) {
+	var discrepancies []models.ReconciliationDiscrepancy
+	err := r.db.SelectContext(ctx, &discrepancies, `
+		SELECT 'REWARD_LEDGER' AS check_name, re.event_id AS reference_id, re.stock_symbol AS symbol,
+			expected.value AS expected, COALESCE(posted.value, 0) AS actual,
+			'stock posted for reward in status ' || re.status || ' does not match quantity x booking price' AS message
+		FROM reward_events re
+		CROSS JOIN LATERAL (
+			SELECT CASE WHEN re.status IN `+heldRewardStatuses+`
+				THEN ROUND(re.booking_price * re.quantity, 4) - COALESCE((
+					SELECT SUM(ROUND(re.booking_price * rr.quantity, 4))
+					FROM reward_reversals rr
+					WHERE rr.event_id = re.event_id
+				), 0)
+				ELSE 0
+			END AS value
+		) expected
+		LEFT JOIN (
+			SELECT event_id, SUM(credit - debit) AS value
+			FROM ledger_entries
+			WHERE entry_type = 'STOCK'
+			GROUP BY event_id
+		) posted ON posted.event_id = re.event_id
+		WHERE ABS(COALESCE(expected.value, 0) - COALESCE(posted.value, 0)) > `+rewardValueTolerance+`
+			OR (expected.value IS NULL AND re.status IN `+heldRewardStatuses+`)
+	`)
+	return discrepancies, err
+}
+
+func (r *ReconciliationRepository) FindMisattributedPostings(ctx context.Context) ([]models.ReconciliationDiscrepancy, error) {
+	var discrepancies []models.ReconciliationDiscrepancy
+	err := r.db.SelectContext(ctx, &discrepancies, `
+		SELECT 'REWARD_LEDGER' AS check_name, le.event_id AS reference_id, le.symbol,
+			'stock entry ' || le.id || ' is posted to a different user than the reward' AS message
+		FROM ledger_entries le
+		JOIN reward_events re ON re.event_id = le.event_id
+		WHERE le.entry_type = 'STOCK' AND le.user_id IS DISTINCT FROM re.user_id
+	`)
+	return discrepancies, err
+}
+
+func (r *ReconciliationRepository) FindOrphanPostings(ctx context.Context) ([]models.ReconciliationDiscrepancy, error) {
+	var discrepancies []models.ReconciliationDiscrepancy
+	err := r.db.SelectContext(ctx, &discrepancies, `
+		SELECT 'REWARD_LEDGER' AS check_name, le.event_id AS reference_id, le.symbol,
+			le.entry_type || ' entry ' || le.id || ' has no reward, transfer, sale or withdrawal' AS message
+		FROM ledger_entries le
+		WHERE le.entry_type IN ('STOCK', 'WALLET', 'WITHDRAWAL_HOLD')
+			AND NOT EXISTS (SELECT 1 FROM reward_events x WHERE x.event_id = le.event_id)
+			AND NOT EXISTS (SELECT 1 FROM share_transfers x WHERE x.id = le.event_id)
+			AND NOT EXISTS (SELECT 1 FROM share_sales x WHERE x.id = le.event_id)
+			AND NOT EXISTS (SELECT 1 FROM demat_withdrawals x WHERE x.id = le.event_id)
+	`)
+	return discrepancies, err
+}
+
+func (r *ReconciliationRepository) FindHoldingsMismatches(ctx context.Context) ([]models.ReconciliationDiscrepancy, error) {
+	var discrepancies []models.ReconciliationDiscrepancy
+	err := r.db.SelectContext(ctx, &discrepancies, `
+		WITH held AS (
+			SELECT re.event_id, re.stock_symbol, re.quantity, re.status, re.ordered_at
+			FROM reward_events re
+			WHERE re.status = 'SETTLED'
+				OR (re.status = 'ORDERED' AND EXISTS (
+					SELECT 1 FROM broker_order_rewards bor
+					JOIN broker_orders bo ON bo.id = bor.order_id
+					WHERE bor.event_id = re.event_id AND bo.status = 'FILLED'
+				))
+		)
+		SELECT 'HOLDINGS_INVENTORY' AS check_name, symbol,
+			SUM(bought) AS expected, SUM(accounted) AS actual,
+			'shares bought do not match held rewards plus the inventory pool' AS message
+		FROM (
+			SELECT bo.symbol, bf.quantity AS bought, 0 AS accounted
+			FROM broker_fills bf
+			JOIN broker_orders bo ON bo.id = bf.order_id
+			WHERE bo.status = 'FILLED'
+			UNION ALL
+			SELECT symbol, 0, quantity
+			FROM inventory_pools
+			UNION ALL
+			SELECT stock_symbol, 0, quantity
+			FROM held
+			UNION ALL
+			SELECT h.stock_symbol, 0, -rr.quantity
+			FROM reward_reversals rr
+			JOIN held h ON h.event_id = rr.event_id
+			WHERE h.status = 'SETTLED' OR rr.created_at < h.ordered_at
+			UNION ALL
+			SELECT stock_symbol, 0, -quantity
+			FROM share_sales
+			UNION ALL
+			SELECT stock_symbol, 0, -quantity
+			FROM demat_withdrawals
+			WHERE status = 'COMPLETED'
+		) custody
+		GROUP BY symbol
+		HAVING SUM(bought) <> SUM(accounted)
+	`)
+	return discrepancies, err
+}
+
+func (r *ReconciliationRepository) FindPoolMismatches(ctx context.Context) ([]models.ReconciliationDiscrepancy, error) {
+	var discrepancies []models.ReconciliationDiscrepancy
+	err := r.db.SelectContext(ctx, &discrepancies, `
+		SELECT 'HOLDINGS_INVENTORY' AS check_name, p.symbol,
+			COALESCE(m.quantity, 0) AS expected, p.quantity AS actual,
+			'inventory pool does not match its movements' AS message
+		FROM inventory_pools p
+		LEFT JOIN (
+			SELECT symbol, SUM(quantity) AS quantity
+			FROM inventory_movements
+			GROUP BY symbol
+		) m ON m.symbol = p.symbol
+		WHERE p.quantity <> COALESCE(m.quantity, 0)
+	`)
+	return discrepancies, err
+}
+
+func (r *ReconciliationRepository) FindInventoryValueMismatches(ctx context.Context) ([]models.ReconciliationDiscrepancy, error) {
+	var discrepancies []models.ReconciliationDiscrepancy
+	err := r.db.SelectContext(ctx, &discrepancies, `
+		SELECT 'INVENTORY_VALUE' AS check_name, COALESCE(p.symbol, le.symbol) AS symbol,
+			COALESCE(p.cost, 0) AS expected, COALESCE(le.value, 0) AS actual,
+			'INVENTORY ledger balance does not match the cost of the inventory pool' AS message
+		FROM inventory_pools p
+		FULL JOIN (
+			SELECT symbol, SUM(debit - credit) AS value
+			FROM ledger_entries
+			WHERE entry_type = 'INVENTORY'
+			GROUP BY symbol
+		) le ON le.symbol = p.symbol
+		WHERE COALESCE(p.cost, 0) <> COALESCE(le.value, 0)
+	`)
+	return discrepancies, err
+}
+
+func (r *ReconciliationRepository) FindHoldingBalanceMismatches(ctx context.Context) ([]models.ReconciliationDiscrepancy, error) {
+	var discrepancies []models.ReconciliationDiscrepancy
+	err := r.db.SelectContext(ctx, &discrepancies, `
+		SELECT 'HOLDING_BALANCES' AS check_name, COALESCE(b.user_id, m.user_id) AS reference_id,
+			COALESCE(b.symbol, m.symbol) AS symbol,
+			COALESCE(m.quantity, 0) AS expected, COALESCE(b.quantity, 0) AS actual,
+			'holding balance does not match its movements' AS message
+		FROM holding_balances b
+		FULL JOIN (
+			SELECT user_id, symbol, SUM(quantity) AS quantity
+			FROM holding_movements
+			GROUP BY user_id, symbol
+		) m ON m.user_id = b.user_id AND m.symbol = b.symbol
+		WHERE COALESCE(b.quantity, 0) <> COALESCE(m.quantity, 0)
+	`)
+	return discrepancies, err
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+type ReportRepository struct {
+	db *sqlx.DB
+}
+
+func NewReportRepository(db *sqlx.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+func (r *ReportRepository) GetTrialBalance(ctx context.Context, from, to time.Time, accountPrefix string) ([]models.TrialBalanceRow, error) {
+	var rows []models.TrialBalanceRow
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT a.code AS account_code, a.name AS account_name, a.account_type, le.entry_type,
+			COALESCE(SUM(le.debit) FILTER (WHERE le.created_at < $1), 0) AS opening_debit,
+			COALESCE(SUM(le.credit) FILTER (WHERE le.created_at < $1), 0) AS opening_credit,
+			COALESCE(SUM(le.debit) FILTER (WHERE le.created_at >= $1), 0) AS period_debit,
+			COALESCE(SUM(le.credit) FILTER (WHERE le.created_at >= $1), 0) AS period_credit
+		FROM ledger_entries le
+		JOIN ledger_accounts a ON a.id = le.account_id
+		WHERE le.created_at < $2
+			AND a.code LIKE $3 || '%'
+		GROUP BY a.id, le.entry_type
+		ORDER BY a.code, le.entry_type
+	`, storedTime(from), storedTime(to), escapeLike(accountPrefix))
+	return rows, err
+}
+
+func (r *ReportRepository) GetAccountActivity(ctx context.Context, from, to time.Time, accountPrefix string) ([]models.AccountActivityRow, error) {
+	var rows []models.AccountActivityRow
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT date_trunc('day', le.created_at) AS day, a.code AS account_code, a.name AS account_name,
+			a.account_type, le.entry_type, COUNT(*) AS postings,
+			SUM(le.debit) AS debit, SUM(le.credit) AS credit
+		FROM ledger_entries le
+		JOIN ledger_accounts a ON a.id = le.account_id
+		WHERE le.created_at >= $1 AND le.created_at < $2
+			AND a.code LIKE $3 || '%'
+		GROUP BY 1, a.id, le.entry_type
+		ORDER BY 1, a.code, le.entry_type
+	`, storedTime(from), storedTime(to), escapeLike(accountPrefix))
+	return rows, err
+}
+
+func (r *ReportRepository) EachGeneralLedgerRow(ctx context.Context, from, to time.Time, accountPrefix string, fn func(*models.GeneralLedgerRow) error) error {
+	rows, err := r.db.QueryxContext(ctx, `
+		WITH opening AS (
+			SELECT account_id, SUM(credit - debit) AS credit_balance
+			FROM ledger_entries
+			WHERE created_at < $1
+			GROUP BY account_id
+		)
+		SELECT le.sequence, le.id AS entry_id, le.created_at, a.code AS account_code, a.account_type,
+			le.entry_type, le.event_id, le.user_id, le.symbol, le.debit, le.credit,
+			COALESCE(o.credit_balance, 0)
+				+ SUM(le.credit - le.debit) OVER (PARTITION BY le.account_id ORDER BY le.sequence) AS credit_balance
+		FROM ledger_entries le
+		JOIN ledger_accounts a ON a.id = le.account_id
+		LEFT JOIN opening o ON o.account_id = le.account_id
+		WHERE le.created_at >= $1 AND le.created_at < $2
+			AND a.code LIKE $3 || '%'
+		ORDER BY a.code, le.sequence
+	`, storedTime(from), storedTime(to), escapeLike(accountPrefix))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.GeneralLedgerRow
+		if err := rows.StructScan(&row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+const rewardJobColumns = `id, event_id, payload, status, attempts, max_attempts, last_error,
+	next_attempt_at, locked_at, completed_at, created_at, updated_at`
+
+type RewardJobRepository struct {
+	db *sqlx.DB
+}
+
+func NewRewardJobRepository(db *sqlx.DB) *RewardJobRepository {
+	return &RewardJobRepository{db: db}
+}
+
+func (r *RewardJobRepository) Enqueue(ctx context.Context, job *models.RewardJob) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO reward_jobs (id, event_id, payload, status, attempts, max_attempts, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 0, $5, $6, $7, $7)
+		ON CONFLICT (event_id) DO NOTHING
+	`, job.ID, job.EventID, job.Payload, job.Status, job.MaxAttempts, job.NextAttemptAt, job.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	return inserted > 0, err
+}
+
+func (r *RewardJobRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) (*models.RewardJob, error) {
+	job := &models.RewardJob{}
+	err := r.db.GetContext(ctx, job, `
+		SELECT `+rewardJobColumns+`
+		FROM reward_jobs WHERE event_id = $1
+	`, eventID)
+	return job, err
+}
+
+func (r *RewardJobRepository) ClaimNext(ctx context.Context, now time.Time, staleBefore time.Time) (*models.RewardJob, error) {
+	job := &models.RewardJob{}
+	err := r.db.GetContext(ctx, job, `
+		UPDATE reward_jobs
+		SET status = 'PROCESSING', attempts = attempts + 1, locked_at = $1, updated_at = $1
+		WHERE id = (
+			SELECT id FROM reward_jobs
+			WHERE (status = 'QUEUED' AND next_attempt_at <= $1)
+				OR (status = 'PROCESSING' AND locked_at < $2)
+			ORDER BY next_attempt_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING `+rewardJobColumns+`
+	`, now, staleBefore)
+	return job, err
+}
+
+func (r *RewardJobRepository) Update(ctx context.Context, job *models.RewardJob) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE reward_jobs
+		SET status = $2, last_error = $3, next_attempt_at = $4, locked_at = $5, completed_at = $6, updated_at = $7
+		WHERE id = $1
+	`, job.ID, job.Status, job.LastError, job.NextAttemptAt, job.LockedAt, job.CompletedAt, time.Now())
+	return err
+}
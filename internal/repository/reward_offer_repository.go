@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+const rewardCampaignColumns = `id, name, budget_inr, reserved_inr, spent_inr, created_at, updated_at`
+
+const rewardOfferColumns = `id, event_id, campaign_id, user_id, stock_symbol, quantity, payload, reserved_inr,
+	claim_deadline, status, claimed_at, expired_at, created_at, updated_at`
+
+type RewardOfferRepository struct {
+	db *sqlx.DB
+}
+
+func NewRewardOfferRepository(db *sqlx.DB) *RewardOfferRepository {
+	return &RewardOfferRepository{db: db}
+}
+
+func (r *RewardOfferRepository) CreateCampaign(ctx context.Context, campaign *models.RewardCampaign) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO reward_campaigns (id, name, budget_inr, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+	`, campaign.ID, campaign.Name, campaign.BudgetINR, campaign.CreatedAt)
+	return err
+}
+
+func (r *RewardOfferRepository) GetCampaign(ctx context.Context, id uuid.UUID) (*models.RewardCampaign, error) {
+	campaign := &models.RewardCampaign{}
+	err := r.db.GetContext(ctx, campaign, `
+		SELECT `+rewardCampaignColumns+`
+		FROM reward_campaigns WHERE id = $1
+	`, id)
+	return campaign, err
+}
+
+func (r *RewardOfferRepository) GetCampaignForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*models.RewardCampaign, error) {
+	campaign := &models.RewardCampaign{}
+	err := tx.GetContext(ctx, campaign, `
+		SELECT `+rewardCampaignColumns+`
+		FROM reward_campaigns WHERE id = $1
+		FOR UPDATE
+	`, id)
+	return campaign, err
+}
+
+func (r *RewardOfferRepository) ListCampaigns(ctx context.Context) ([]models.RewardCampaign, error) {
+	var campaigns []models.RewardCampaign
+	err := r.db.SelectContext(ctx, &campaigns, `
+		SELECT `+rewardCampaignColumns+`
+		FROM reward_campaigns
+		ORDER BY created_at
+	`)
+	return campaigns, err
+}
+
+func (r *RewardOfferRepository) UpdateCampaignTotals(ctx context.Context, tx *sqlx.Tx, campaign *models.RewardCampaign) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE reward_campaigns
+		SET reserved_inr = $2, spent_inr = $3, updated_at = $4
+		WHERE id = $1
+	`, campaign.ID, campaign.ReservedINR, campaign.SpentINR, time.Now())
+	return err
+}
+
+func (r *RewardOfferRepository) Create(ctx context.Context, tx *sqlx.Tx, offer *models.RewardOffer) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO reward_offers (id, event_id, campaign_id, user_id, stock_symbol, quantity, payload,
+			reserved_inr, claim_deadline, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11)
+		ON CONFLICT (event_id) DO NOTHING
+	`, offer.ID, offer.EventID, offer.CampaignID, offer.UserID, offer.StockSymbol, offer.Quantity, offer.Payload,
+		offer.ReservedINR, offer.ClaimDeadline, offer.Status, offer.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	return inserted > 0, err
+}
+
+func (r *RewardOfferRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) (*models.RewardOffer, error) {
+	offer := &models.RewardOffer{}
+	err := r.db.GetContext(ctx, offer, `
+		SELECT `+rewardOfferColumns+`
+		FROM reward_offers WHERE event_id = $1
+	`, eventID)
+	return offer, err
+}
+
+func (r *RewardOfferRepository) GetByEventIDForUpdate(ctx context.Context, tx *sqlx.Tx, eventID uuid.UUID) (*models.RewardOffer, error) {
+	offer := &models.RewardOffer{}
+	err := tx.GetContext(ctx, offer, `
+		SELECT `+rewardOfferColumns+`
+		FROM reward_offers WHERE event_id = $1
+		FOR UPDATE
+	`, eventID)
+	return offer, err
+}
+
+func (r *RewardOfferRepository) ListClaimable(ctx context.Context, userID uuid.UUID, now time.Time) ([]models.RewardOffer, error) {
+	var offers []models.RewardOffer
+	err := r.db.SelectContext(ctx, &offers, `
+		SELECT `+rewardOfferColumns+`
+		FROM reward_offers
+		WHERE user_id = $1 AND status = 'OFFERED' AND claim_deadline > $2
+		ORDER BY claim_deadline
+	`, userID, now)
+	return offers, err
+}
+
+func (r *RewardOfferRepository) ListExpiredIDs(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	var eventIDs []uuid.UUID
+	err := r.db.SelectContext(ctx, &eventIDs, `
+		SELECT event_id FROM reward_offers
+		WHERE status = 'OFFERED' AND claim_deadline <= $1
+		ORDER BY claim_deadline
+	`, now)
+	return eventIDs, err
+}
+
+func (r *RewardOfferRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, offer *models.RewardOffer) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE reward_offers
+		SET status = $2, claimed_at = $3, expired_at = $4, updated_at = $5
+		WHERE id = $1
+	`, offer.ID, offer.Status, offer.ClaimedAt, offer.ExpiredAt, time.Now())
+	return err
+}
@@ -10,6 +10,12 @@ import (
 	"stocky/internal/models"
 )
 
+const heldRewardStatuses = `('BOOKED', 'ORDERED', 'SETTLED')`
+
+const rewardColumns = `id, event_id, user_id, stock_symbol, quantity, booking_price, priced_at, status,
+	booked_at, ordered_at, settlement_due_at, settled_at, failed_at, failure_reason,
+	timestamp, received_at, created_at`
+
 type RewardRepository struct {
 	db *sqlx.DB
 }
@@ -21,7 +27,7 @@ func NewRewardRepository(db *sqlx.DB) *RewardRepository {
 func (r *RewardRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) (*models.RewardEvent, error) {
 	reward := &models.RewardEvent{}
 	err := r.db.GetContext(ctx, reward, `
-		SELECT id, event_id, user_id, stock_symbol, quantity, timestamp, created_at
+		SELECT `+rewardColumns+`
 		FROM reward_events WHERE event_id = $1
 	`, eventID)
 	return reward, err
@@ -29,33 +35,103 @@ func (r *RewardRepository) GetByEventID(ctx context.Context, eventID uuid.UUID)
 
 func (r *RewardRepository) Create(ctx context.Context, tx *sqlx.Tx, reward *models.RewardEvent) error {
 	query := `
-		INSERT INTO reward_events (id, event_id, user_id, stock_symbol, quantity, timestamp, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO reward_events (id, event_id, user_id, stock_symbol, quantity, booking_price, priced_at, status, booked_at, timestamp, received_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 	_, err := tx.ExecContext(ctx, query,
 		reward.ID, reward.EventID, reward.UserID, reward.StockSymbol,
-		reward.Quantity, reward.Timestamp, reward.CreatedAt)
+		reward.Quantity, reward.BookingPrice, reward.PricedAt, reward.Status, reward.BookedAt,
+		reward.Timestamp, reward.ReceivedAt, reward.CreatedAt)
 	return err
 }
 
-func (r *RewardRepository) GetTodayRewards(ctx context.Context, userID uuid.UUID, istDate time.Time) ([]models.RewardEvent, error) {
-	startOfDay := time.Date(istDate.Year(), istDate.Month(), istDate.Day(), 0, 0, 0, 0, istDate.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
+func (r *RewardRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, reward *models.RewardEvent) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE reward_events
+		SET status = $2, booking_price = $3, priced_at = $4, booked_at = $5, ordered_at = $6,
+			settlement_due_at = $7, settled_at = $8, failed_at = $9, failure_reason = $10
+		WHERE event_id = $1
+	`, reward.EventID, reward.Status, reward.BookingPrice, reward.PricedAt, reward.BookedAt, reward.OrderedAt,
+		reward.SettlementDueAt, reward.SettledAt, reward.FailedAt, reward.FailureReason)
+	return err
+}
 
+func (r *RewardRepository) ListBookedForUpdate(ctx context.Context, tx *sqlx.Tx) ([]models.RewardEvent, error) {
 	var rewards []models.RewardEvent
-	err := r.db.SelectContext(ctx, &rewards, `
-		SELECT id, event_id, user_id, stock_symbol, quantity, timestamp, created_at
+	err := tx.SelectContext(ctx, &rewards, `
+		SELECT `+rewardColumns+`
 		FROM reward_events
-		WHERE user_id = $1 AND timestamp >= $2 AND timestamp < $3
-		ORDER BY timestamp DESC
-	`, userID, startOfDay, endOfDay)
+		WHERE status = 'BOOKED'
+		ORDER BY stock_symbol, booked_at
+		FOR UPDATE SKIP LOCKED
+	`)
 	return rewards, err
 }
 
-func (r *RewardRepository) GetTotalSharesByStockToday(ctx context.Context, userID uuid.UUID, istDate time.Time) (map[string]decimal.Decimal, error) {
-	startOfDay := time.Date(istDate.Year(), istDate.Month(), istDate.Day(), 0, 0, 0, 0, istDate.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
+func (r *RewardRepository) GetReversedQuantity(ctx context.Context, tx *sqlx.Tx, eventID uuid.UUID) (decimal.Decimal, error) {
+	var reversed decimal.Decimal
+	err := tx.GetContext(ctx, &reversed, `
+		SELECT COALESCE(SUM(quantity), 0) FROM reward_reversals WHERE event_id = $1
+	`, eventID)
+	return reversed, err
+}
+
+func (r *RewardRepository) GetReversedQuantitySince(ctx context.Context, tx *sqlx.Tx, eventID uuid.UUID, since time.Time) (decimal.Decimal, error) {
+	var reversed decimal.Decimal
+	err := tx.GetContext(ctx, &reversed, `
+		SELECT COALESCE(SUM(quantity), 0) FROM reward_reversals WHERE event_id = $1 AND created_at >= $2
+	`, eventID, since)
+	return reversed, err
+}
+
+func (r *RewardRepository) ListDueForSettlement(ctx context.Context, asOf time.Time) ([]uuid.UUID, error) {
+	var eventIDs []uuid.UUID
+	err := r.db.SelectContext(ctx, &eventIDs, `
+		SELECT re.event_id FROM reward_events re
+		WHERE re.status = 'ORDERED' AND re.settlement_due_at <= $1
+			AND NOT EXISTS (
+				SELECT 1 FROM broker_order_rewards bor
+				JOIN broker_orders bo ON bo.id = bor.order_id
+				WHERE bor.event_id = re.event_id AND bo.status <> 'FILLED'
+			)
+		ORDER BY re.settlement_due_at
+	`, asOf)
+	return eventIDs, err
+}
+
+func (r *RewardRepository) GetHeldSharesByStockAndStatus(ctx context.Context, userID uuid.UUID) (map[string]map[models.RewardStatus]decimal.Decimal, error) {
+	type result struct {
+		StockSymbol string              `db:"stock_symbol"`
+		Status      models.RewardStatus `db:"status"`
+		TotalQty    decimal.Decimal     `db:"total_quantity"`
+	}
+
+	var results []result
+	err := r.db.SelectContext(ctx, &results, `
+		SELECT re.stock_symbol, re.status, SUM(re.quantity - COALESCE(rev.quantity, 0)) as total_quantity
+		FROM reward_events re
+		LEFT JOIN (
+			SELECT event_id, SUM(quantity) as quantity FROM reward_reversals GROUP BY event_id
+		) rev ON rev.event_id = re.event_id
+		WHERE re.user_id = $1 AND re.status IN `+heldRewardStatuses+`
+		GROUP BY re.stock_symbol, re.status
+	`, userID)
+
+	if err != nil {
+		return nil, err
+	}
 
+	totals := make(map[string]map[models.RewardStatus]decimal.Decimal)
+	for _, r := range results {
+		if totals[r.StockSymbol] == nil {
+			totals[r.StockSymbol] = make(map[models.RewardStatus]decimal.Decimal)
+		}
+		totals[r.StockSymbol][r.Status] = r.TotalQty
+	}
+	return totals, nil
+}
+
+func (r *RewardRepository) GetRestrictedSharesByStock(ctx context.Context, userID uuid.UUID) (map[string]decimal.Decimal, error) {
 	type result struct {
 		StockSymbol string          `db:"stock_symbol"`
 		TotalQty    decimal.Decimal `db:"total_quantity"`
@@ -63,11 +139,21 @@ func (r *RewardRepository) GetTotalSharesByStockToday(ctx context.Context, userI
 
 	var results []result
 	err := r.db.SelectContext(ctx, &results, `
-		SELECT stock_symbol, SUM(quantity) as total_quantity
-		FROM reward_events
-		WHERE user_id = $1 AND timestamp >= $2 AND timestamp < $3
-		GROUP BY stock_symbol
-	`, userID, startOfDay, endOfDay)
+		SELECT re.stock_symbol, SUM(
+			CASE WHEN re.status IN ('BOOKED', 'ORDERED') THEN re.quantity - COALESCE(rev.quantity, 0)
+			ELSE COALESCE(t.quantity, 0) END
+		) as total_quantity
+		FROM reward_events re
+		LEFT JOIN (
+			SELECT event_id, SUM(quantity) as quantity FROM reward_reversals GROUP BY event_id
+		) rev ON rev.event_id = re.event_id
+		LEFT JOIN (
+			SELECT event_id, SUM(quantity - reversed_quantity) as quantity
+			FROM reward_vesting_tranches WHERE vested = FALSE GROUP BY event_id
+		) t ON t.event_id = re.event_id
+		WHERE re.user_id = $1 AND re.status IN `+heldRewardStatuses+`
+		GROUP BY re.stock_symbol
+	`, userID)
 
 	if err != nil {
 		return nil, err
@@ -80,7 +166,50 @@ func (r *RewardRepository) GetTotalSharesByStockToday(ctx context.Context, userI
 	return totals, nil
 }
 
-func (r *RewardRepository) GetTotalSharesByStockUpToDate(ctx context.Context, userID uuid.UUID, endDate time.Time) (map[string]decimal.Decimal, error) {
+func (r *RewardRepository) LockHolding(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, symbol string) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('holding:' || $1 || ':' || $2))`, userID.String(), symbol)
+	return err
+}
+
+func (r *RewardRepository) GetByEventIDForUpdate(ctx context.Context, tx *sqlx.Tx, eventID uuid.UUID) (*models.RewardEvent, error) {
+	reward := &models.RewardEvent{}
+	err := tx.GetContext(ctx, reward, `
+		SELECT `+rewardColumns+`
+		FROM reward_events WHERE event_id = $1
+		FOR UPDATE
+	`, eventID)
+	return reward, err
+}
+
+func (r *RewardRepository) CreateReversal(ctx context.Context, tx *sqlx.Tx, reversal *models.RewardReversal) error {
+	query := `
+		INSERT INTO reward_reversals (id, event_id, quantity, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		reversal.ID, reversal.EventID, reversal.Quantity, reversal.Reason, reversal.CreatedAt)
+	return err
+}
+
+func (r *RewardRepository) GetTodayRewards(ctx context.Context, userID uuid.UUID, istDate time.Time) ([]models.RewardEvent, error) {
+	startOfDay := time.Date(istDate.Year(), istDate.Month(), istDate.Day(), 0, 0, 0, 0, istDate.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	var rewards []models.RewardEvent
+	err := r.db.SelectContext(ctx, &rewards, `
+		SELECT `+rewardColumns+`
+		FROM reward_events
+		WHERE user_id = $1 AND timestamp >= $2 AND timestamp < $3
+			AND status IN `+heldRewardStatuses+`
+		ORDER BY timestamp DESC
+	`, userID, storedTime(startOfDay), storedTime(endOfDay))
+	return rewards, err
+}
+
+func (r *RewardRepository) GetTotalSharesByStockToday(ctx context.Context, userID uuid.UUID, istDate time.Time) (map[string]decimal.Decimal, error) {
+	startOfDay := time.Date(istDate.Year(), istDate.Month(), istDate.Day(), 0, 0, 0, 0, istDate.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
 	type result struct {
 		StockSymbol string          `db:"stock_symbol"`
 		TotalQty    decimal.Decimal `db:"total_quantity"`
@@ -90,9 +219,10 @@ func (r *RewardRepository) GetTotalSharesByStockUpToDate(ctx context.Context, us
 	err := r.db.SelectContext(ctx, &results, `
 		SELECT stock_symbol, SUM(quantity) as total_quantity
 		FROM reward_events
-		WHERE user_id = $1 AND timestamp <= $2
+		WHERE user_id = $1 AND timestamp >= $2 AND timestamp < $3
+			AND status IN `+heldRewardStatuses+`
 		GROUP BY stock_symbol
-	`, userID, endDate)
+	`, userID, storedTime(startOfDay), storedTime(endOfDay))
 
 	if err != nil {
 		return nil, err
@@ -104,4 +234,3 @@ func (r *RewardRepository) GetTotalSharesByStockUpToDate(ctx context.Context, us
 	}
 	return totals, nil
 }
-
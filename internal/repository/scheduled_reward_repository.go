@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+const scheduledRewardColumns = `id, event_id, user_id, stock_symbol, quantity, payload, scheduled_for, status,
+	locked_at, executed_at, cancelled_at, cancelled_by, last_error, created_at, updated_at`
+
+type ScheduledRewardRepository struct {
+	db *sqlx.DB
+}
+
+func NewScheduledRewardRepository(db *sqlx.DB) *ScheduledRewardRepository {
+	return &ScheduledRewardRepository{db: db}
+}
+
+func (r *ScheduledRewardRepository) Create(ctx context.Context, scheduled *models.ScheduledReward) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO scheduled_rewards (id, event_id, user_id, stock_symbol, quantity, payload, scheduled_for, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+		ON CONFLICT (event_id) DO NOTHING
+	`, scheduled.ID, scheduled.EventID, scheduled.UserID, scheduled.StockSymbol, scheduled.Quantity,
+		scheduled.Payload, scheduled.ScheduledFor, scheduled.Status, scheduled.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	return inserted > 0, err
+}
+
+func (r *ScheduledRewardRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) (*models.ScheduledReward, error) {
+	scheduled := &models.ScheduledReward{}
+	err := r.db.GetContext(ctx, scheduled, `
+		SELECT `+scheduledRewardColumns+`
+		FROM scheduled_rewards WHERE event_id = $1
+	`, eventID)
+	return scheduled, err
+}
+
+func (r *ScheduledRewardRepository) Cancel(ctx context.Context, eventID uuid.UUID, cancelledBy *string, now time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE scheduled_rewards
+		SET status = 'CANCELLED', cancelled_at = $2, cancelled_by = $3, updated_at = $2
+		WHERE event_id = $1 AND status = 'SCHEDULED'
+	`, eventID, now, cancelledBy)
+	if err != nil {
+		return false, err
+	}
+	cancelled, err := result.RowsAffected()
+	return cancelled > 0, err
+}
+
+func (r *ScheduledRewardRepository) ClaimDue(ctx context.Context, now time.Time, staleBefore time.Time, limit int) ([]models.ScheduledReward, error) {
+	var due []models.ScheduledReward
+	err := r.db.SelectContext(ctx, &due, `
+		UPDATE scheduled_rewards
+		SET status = 'EXECUTING', locked_at = $1, updated_at = $1
+		WHERE id IN (
+			SELECT id FROM scheduled_rewards
+			WHERE (status = 'SCHEDULED' AND scheduled_for <= $1)
+				OR (status = 'EXECUTING' AND locked_at < $2)
+			ORDER BY scheduled_for
+			FOR UPDATE SKIP LOCKED
+			LIMIT $3
+		)
+		RETURNING `+scheduledRewardColumns+`
+	`, now, staleBefore, limit)
+	return due, err
+}
+
+func (r *ScheduledRewardRepository) Complete(ctx context.Context, scheduled *models.ScheduledReward) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE scheduled_rewards
+		SET status = $2, executed_at = $3, last_error = $4, locked_at = NULL, updated_at = $5
+		WHERE id = $1
+	`, scheduled.ID, scheduled.Status, scheduled.ExecutedAt, scheduled.LastError, time.Now())
+	return err
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+const shareSaleColumns = `id, user_id, stock_symbol, quantity, price, gross_inr, fees_inr, net_inr, created_at`
+
+type ShareSaleRepository struct {
+	db *sqlx.DB
+}
+
+func NewShareSaleRepository(db *sqlx.DB) *ShareSaleRepository {
+	return &ShareSaleRepository{db: db}
+}
+
+func (r *ShareSaleRepository) Create(ctx context.Context, tx *sqlx.Tx, sale *models.ShareSale) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO share_sales (id, user_id, stock_symbol, quantity, price, gross_inr, fees_inr, net_inr, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO NOTHING
+	`, sale.ID, sale.UserID, sale.StockSymbol, sale.Quantity, sale.Price, sale.GrossINR, sale.FeesINR, sale.NetINR, sale.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	return inserted > 0, err
+}
+
+func (r *ShareSaleRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ShareSale, error) {
+	sale := &models.ShareSale{}
+	err := r.db.GetContext(ctx, sale, `
+		SELECT `+shareSaleColumns+`
+		FROM share_sales WHERE id = $1
+	`, id)
+	return sale, err
+}
+
+func (r *ShareSaleRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.ShareSale, error) {
+	var sales []models.ShareSale
+	err := r.db.SelectContext(ctx, &sales, `
+		SELECT `+shareSaleColumns+`
+		FROM share_sales
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`, userID)
+	return sales, err
+}
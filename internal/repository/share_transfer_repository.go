@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+const shareTransferColumns = `id, from_user_id, to_user_id, stock_symbol, quantity, price, value_inr, note, created_at`
+
+type ShareTransferRepository struct {
+	db *sqlx.DB
+}
+
+func NewShareTransferRepository(db *sqlx.DB) *ShareTransferRepository {
+	return &ShareTransferRepository{db: db}
+}
+
+func (r *ShareTransferRepository) Create(ctx context.Context, tx *sqlx.Tx, transfer *models.ShareTransfer) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO share_transfers (id, from_user_id, to_user_id, stock_symbol, quantity, price, value_inr, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO NOTHING
+	`, transfer.ID, transfer.FromUserID, transfer.ToUserID, transfer.StockSymbol, transfer.Quantity,
+		transfer.Price, transfer.ValueINR, transfer.Note, transfer.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	return inserted > 0, err
+}
+
+func (r *ShareTransferRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ShareTransfer, error) {
+	transfer := &models.ShareTransfer{}
+	err := r.db.GetContext(ctx, transfer, `
+		SELECT `+shareTransferColumns+`
+		FROM share_transfers WHERE id = $1
+	`, id)
+	return transfer, err
+}
+
+func (r *ShareTransferRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.ShareTransfer, error) {
+	var transfers []models.ShareTransfer
+	err := r.db.SelectContext(ctx, &transfers, `
+		SELECT `+shareTransferColumns+`
+		FROM share_transfers
+		WHERE from_user_id = $1 OR to_user_id = $1
+		ORDER BY created_at DESC
+	`, userID)
+	return transfers, err
+}
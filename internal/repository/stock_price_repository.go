@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
 	"stocky/internal/models"
 )
@@ -17,7 +18,7 @@ func NewStockPriceRepository(db *sqlx.DB) *StockPriceRepository {
 	return &StockPriceRepository{db: db}
 }
 
-func (r *StockPriceRepository) Upsert(ctx context.Context, price *models.StockPrice) error {
+func (r *StockPriceRepository) Upsert(ctx context.Context, tx *sqlx.Tx, price *models.StockPrice) error {
 	query := `
 		INSERT INTO stock_prices (symbol, price, fetched_at)
 		VALUES ($1, $2, $3)
@@ -25,7 +26,15 @@ func (r *StockPriceRepository) Upsert(ctx context.Context, price *models.StockPr
 			price = EXCLUDED.price,
 			fetched_at = EXCLUDED.fetched_at
 	`
-	_, err := r.db.ExecContext(ctx, query, price.Symbol, price.Price, price.FetchedAt)
+	if _, err := tx.ExecContext(ctx, query, price.Symbol, price.Price, price.FetchedAt); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO stock_price_history (symbol, price, fetched_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (symbol, fetched_at) DO UPDATE SET price = EXCLUDED.price
+	`, price.Symbol, price.Price, price.FetchedAt)
 	return err
 }
 
@@ -69,11 +78,44 @@ func (r *StockPriceRepository) GetHistoricalPrices(ctx context.Context, symbol s
 	price := &models.StockPrice{}
 	err := r.db.GetContext(ctx, price, `
 		SELECT symbol, price, fetched_at
-		FROM stock_prices
+		FROM stock_price_history
 		WHERE symbol = $1 AND fetched_at <= $2
 		ORDER BY fetched_at DESC
 		LIMIT 1
-	`, symbol, date)
+	`, symbol, storedTime(date))
 	return price, err
 }
 
+func (r *StockPriceRepository) GetDailyClosingPrices(ctx context.Context, symbols []string, from time.Time, days int) ([]map[string]decimal.Decimal, error) {
+	type result struct {
+		Day    int             `db:"day"`
+		Symbol string          `db:"symbol"`
+		Price  decimal.Decimal `db:"price"`
+	}
+
+	var results []result
+	err := r.db.SelectContext(ctx, &results, `
+		SELECT d.day, s.symbol, p.price
+		FROM generate_series(0, $3::int - 1) AS d(day)
+		CROSS JOIN unnest($1::text[]) AS s(symbol)
+		CROSS JOIN LATERAL (
+			SELECT price
+			FROM stock_price_history
+			WHERE symbol = s.symbol AND fetched_at < $2::timestamp + (d.day + 1) * INTERVAL '1 day'
+			ORDER BY fetched_at DESC
+			LIMIT 1
+		) p
+	`, pq.Array(symbols), storedTime(from), days)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make([]map[string]decimal.Decimal, days)
+	for i := range prices {
+		prices[i] = make(map[string]decimal.Decimal)
+	}
+	for _, r := range results {
+		prices[r.Day][r.Symbol] = r.Price
+	}
+	return prices, nil
+}
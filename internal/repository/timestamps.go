@@ -0,0 +1,11 @@
+package repository
+
+import "time"
+
+func storedTime(t time.Time) time.Time {
+	return t.In(time.Local)
+}
+
+func storedDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
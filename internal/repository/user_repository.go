@@ -18,21 +18,31 @@ func NewUserRepository(db *sqlx.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) GetOrCreate(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+func (r *UserRepository) GetOrCreate(ctx context.Context, userID uuid.UUID, partnerID *string) (*models.User, error) {
 	user := &models.User{}
 	err := r.db.GetContext(ctx, user, `
-		SELECT id, created_at FROM users WHERE id = $1
+		SELECT id, partner_id, created_at FROM users WHERE id = $1
 	`, userID)
 
 	if err == nil {
+		if user.PartnerID == nil && partnerID != nil {
+			_, err = r.db.ExecContext(ctx, `
+				UPDATE users SET partner_id = $2 WHERE id = $1 AND partner_id IS NULL
+			`, userID, *partnerID)
+			if err != nil {
+				return nil, err
+			}
+			user.PartnerID = partnerID
+		}
 		return user, nil
 	}
 
 	user.ID = userID
+	user.PartnerID = partnerID
 	user.CreatedAt = time.Now()
 	_, err = r.db.ExecContext(ctx, `
-		INSERT INTO users (id, created_at) VALUES ($1, $2)
-	`, userID, user.CreatedAt)
+		INSERT INTO users (id, partner_id, created_at) VALUES ($1, $2, $3)
+	`, userID, partnerID, user.CreatedAt)
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"stocky/internal/models"
+)
+
+type VestingRepository struct {
+	db *sqlx.DB
+}
+
+func NewVestingRepository(db *sqlx.DB) *VestingRepository {
+	return &VestingRepository{db: db}
+}
+
+func (r *VestingRepository) CreateTranche(ctx context.Context, tx *sqlx.Tx, tranche *models.VestingTranche) error {
+	query := `
+		INSERT INTO reward_vesting_tranches (id, event_id, vest_date, quantity, reversed_quantity, vested, vested_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		tranche.ID, tranche.EventID, tranche.VestDate, tranche.Quantity,
+		tranche.ReversedQuantity, tranche.Vested, tranche.VestedAt, tranche.CreatedAt)
+	return err
+}
+
+func (r *VestingRepository) GetUnvestedByEventIDForUpdate(ctx context.Context, tx *sqlx.Tx, eventID uuid.UUID) ([]models.VestingTranche, error) {
+	var tranches []models.VestingTranche
+	err := tx.SelectContext(ctx, &tranches, `
+		SELECT id, event_id, vest_date, quantity, reversed_quantity, vested, vested_at, created_at
+		FROM reward_vesting_tranches
+		WHERE event_id = $1 AND vested = FALSE
+		ORDER BY vest_date DESC
+		FOR UPDATE
+	`, eventID)
+	return tranches, err
+}
+
+func (r *VestingRepository) AddReversedQuantity(ctx context.Context, tx *sqlx.Tx, trancheID uuid.UUID, quantity decimal.Decimal) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE reward_vesting_tranches
+		SET reversed_quantity = reversed_quantity + $2
+		WHERE id = $1
+	`, trancheID, quantity)
+	return err
+}
+
+func (r *VestingRepository) VestDue(ctx context.Context, asOf time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE reward_vesting_tranches
+		SET vested = TRUE, vested_at = $1
+		WHERE vested = FALSE AND vest_date <= $1
+	`, asOf)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *VestingRepository) GetUnvestedSharesByStock(ctx context.Context, userID uuid.UUID) (map[string]decimal.Decimal, error) {
+	type result struct {
+		StockSymbol string          `db:"stock_symbol"`
+		TotalQty    decimal.Decimal `db:"total_quantity"`
+	}
+
+	var results []result
+	err := r.db.SelectContext(ctx, &results, `
+		SELECT re.stock_symbol, SUM(t.quantity - t.reversed_quantity) as total_quantity
+		FROM reward_vesting_tranches t
+		JOIN reward_events re ON re.event_id = t.event_id
+		WHERE re.user_id = $1 AND t.vested = FALSE
+			AND re.status IN `+heldRewardStatuses+`
+		GROUP BY re.stock_symbol
+	`, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]decimal.Decimal)
+	for _, r := range results {
+		totals[r.StockSymbol] = r.TotalQty
+	}
+	return totals, nil
+}
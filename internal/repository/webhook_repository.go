@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"stocky/internal/models"
+)
+
+const webhookSubscriptionColumns = `id, partner_id, name, url, event_types, secret, status, consecutive_failures,
+	disabled_reason, disabled_at, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, subscription_id, message_id, event_type, payload, status, attempts,
+	next_attempt_at, last_status_code, last_error, delivered_at, created_at, updated_at`
+
+type WebhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_subscriptions (id, partner_id, name, url, event_types, secret, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+	`, sub.ID, sub.PartnerID, sub.Name, sub.URL, sub.EventTypes, sub.Secret, sub.Status, sub.CreatedAt)
+	return err
+}
+
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id uuid.UUID) (*models.WebhookSubscription, error) {
+	sub := &models.WebhookSubscription{}
+	err := r.db.GetContext(ctx, sub, `
+		SELECT `+webhookSubscriptionColumns+`
+		FROM webhook_subscriptions WHERE id = $1
+	`, id)
+	return sub, err
+}
+
+func (r *WebhookRepository) GetSubscriptionForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*models.WebhookSubscription, error) {
+	sub := &models.WebhookSubscription{}
+	err := tx.GetContext(ctx, sub, `
+		SELECT `+webhookSubscriptionColumns+`
+		FROM webhook_subscriptions WHERE id = $1
+		FOR UPDATE
+	`, id)
+	return sub, err
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	err := r.db.SelectContext(ctx, &subs, `
+		SELECT `+webhookSubscriptionColumns+`
+		FROM webhook_subscriptions
+		ORDER BY created_at
+	`)
+	return subs, err
+}
+
+func (r *WebhookRepository) ListActiveForEvent(ctx context.Context, eventType string, userID *uuid.UUID) ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	err := r.db.SelectContext(ctx, &subs, `
+		SELECT `+webhookSubscriptionColumns+`
+		FROM webhook_subscriptions
+		WHERE status = 'ACTIVE' AND $1 = ANY(event_types)
+			AND ($2::uuid IS NULL OR partner_id = (SELECT partner_id FROM users WHERE id = $2))
+	`, eventType, userID)
+	return subs, err
+}
+
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_subscriptions
+		SET name = $2, url = $3, event_types = $4, status = $5, consecutive_failures = $6,
+			disabled_reason = $7, disabled_at = $8, updated_at = $9
+		WHERE id = $1
+	`, sub.ID, sub.Name, sub.URL, sub.EventTypes, sub.Status, sub.ConsecutiveFailures,
+		sub.DisabledReason, sub.DisabledAt, time.Now())
+	return err
+}
+
+func (r *WebhookRepository) UpdateSubscriptionHealth(ctx context.Context, tx *sqlx.Tx, sub *models.WebhookSubscription) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE webhook_subscriptions
+		SET status = $2, consecutive_failures = $3, disabled_reason = $4, disabled_at = $5, updated_at = $6
+		WHERE id = $1
+	`, sub.ID, sub.Status, sub.ConsecutiveFailures, sub.DisabledReason, sub.DisabledAt, time.Now())
+	return err
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	deleted, err := result.RowsAffected()
+	return deleted > 0, err
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (id, subscription_id, message_id, event_type, payload, status, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (subscription_id, message_id) DO NOTHING
+	`, delivery.ID, delivery.SubscriptionID, delivery.MessageID, delivery.EventType,
+		delivery.Payload, delivery.Status, delivery.NextAttemptAt, delivery.CreatedAt)
+	return err
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	err := r.db.GetContext(ctx, delivery, `
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries WHERE id = $1
+	`, id)
+	return delivery, err
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, status models.WebhookDeliveryStatus) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.SelectContext(ctx, &deliveries, `
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries
+		WHERE subscription_id = $1 AND ($2::text = '' OR status = $2)
+		ORDER BY created_at DESC
+	`, subscriptionID, status)
+	return deliveries, err
+}
+
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.SelectContext(ctx, &deliveries, `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = $2, updated_at = $1
+		WHERE id IN (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhook_subscriptions s ON s.id = d.subscription_id
+			WHERE d.status = 'PENDING' AND d.next_attempt_at <= $1 AND s.status = 'ACTIVE'
+			ORDER BY d.next_attempt_at
+			LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING `+webhookDeliveryColumns+`
+	`, now, leaseUntil, limit)
+	return deliveries, err
+}
+
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, tx *sqlx.Tx, delivery *models.WebhookDelivery) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5, last_error = $6,
+			delivered_at = $7, updated_at = $8
+		WHERE id = $1
+	`, delivery.ID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode,
+		delivery.LastError, delivery.DeliveredAt, time.Now())
+	return err
+}
+
+func (r *WebhookRepository) ResetDelivery(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'PENDING', attempts = 0, next_attempt_at = $2, updated_at = $2
+		WHERE id = $1
+	`, id, time.Now())
+	return err
+}
+
+func (r *WebhookRepository) AddAttempt(ctx context.Context, tx *sqlx.Tx, attempt *models.WebhookDeliveryAttempt) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO webhook_delivery_attempts (id, delivery_id, attempt, status_code, error, response_body, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, attempt.ID, attempt.DeliveryID, attempt.Attempt, attempt.StatusCode, attempt.Error,
+		attempt.ResponseBody, attempt.DurationMs, attempt.CreatedAt)
+	return err
+}
+
+func (r *WebhookRepository) GetAttempts(ctx context.Context, deliveryID uuid.UUID) ([]models.WebhookDeliveryAttempt, error) {
+	var attempts []models.WebhookDeliveryAttempt
+	err := r.db.SelectContext(ctx, &attempts, `
+		SELECT id, delivery_id, attempt, status_code, error, response_body, duration_ms, created_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY created_at
+	`, deliveryID)
+	return attempts, err
+}
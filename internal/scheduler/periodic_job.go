@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type PeriodicJob struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+func NewPeriodicJob(name string, interval time.Duration, run func(ctx context.Context) error) *PeriodicJob {
+	return &PeriodicJob{
+		name:     name,
+		interval: interval,
+		run:      run,
+	}
+}
+
+func (j *PeriodicJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	if err := j.run(ctx); err != nil {
+		logrus.WithError(err).WithField("job", j.name).Error("Initial job run failed")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.WithField("job", j.name).Info("Job stopped")
+			return
+		case <-ticker.C:
+			if err := j.run(ctx); err != nil {
+				logrus.WithError(err).WithField("job", j.name).Error("Job run failed")
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"stocky/internal/service"
+)
+
+type RewardWorkerPool struct {
+	queueService *service.RewardQueueService
+	workers      int
+	pollInterval time.Duration
+}
+
+func NewRewardWorkerPool(queueService *service.RewardQueueService, workers int, pollInterval time.Duration) *RewardWorkerPool {
+	return &RewardWorkerPool{
+		queueService: queueService,
+		workers:      workers,
+		pollInterval: pollInterval,
+	}
+}
+
+func (p *RewardWorkerPool) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			p.run(ctx, worker)
+		}(i)
+	}
+
+	logrus.WithField("workers", p.workers).Info("Reward worker pool started")
+	wg.Wait()
+	logrus.Info("Reward worker pool stopped")
+}
+
+func (p *RewardWorkerPool) run(ctx context.Context, worker int) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		processed, err := p.queueService.ProcessNext(ctx)
+		if err != nil {
+			logrus.WithError(err).WithField("worker", worker).Error("Reward worker failed")
+		}
+		if processed && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.pollInterval):
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+type AccountingPeriodService struct {
+	periodRepo *repository.AccountingPeriodRepository
+	ledgerRepo *repository.LedgerRepository
+	db         *sqlx.DB
+}
+
+func NewAccountingPeriodService(
+	periodRepo *repository.AccountingPeriodRepository,
+	ledgerRepo *repository.LedgerRepository,
+	db *sqlx.DB,
+) *AccountingPeriodService {
+	return &AccountingPeriodService{
+		periodRepo: periodRepo,
+		ledgerRepo: ledgerRepo,
+		db:         db,
+	}
+}
+
+type AccountingPeriodResponse struct {
+	Period     string                   `json:"period"`
+	StartsAt   time.Time                `json:"starts_at"`
+	EndsAt     time.Time                `json:"ends_at"`
+	Status     models.PeriodStatus      `json:"status"`
+	ClosedAt   *time.Time               `json:"closed_at,omitempty"`
+	ClosedBy   *string                  `json:"closed_by,omitempty"`
+	LockedAt   *time.Time               `json:"locked_at,omitempty"`
+	LockedBy   *string                  `json:"locked_by,omitempty"`
+	ReopenedAt *time.Time               `json:"reopened_at,omitempty"`
+	ReopenedBy *string                  `json:"reopened_by,omitempty"`
+	Balances   []*PeriodBalanceResponse `json:"balances,omitempty"`
+}
+
+type PeriodBalanceResponse struct {
+	AccountCode    string                   `json:"account_code"`
+	AccountName    string                   `json:"account_name"`
+	AccountType    models.LedgerAccountType `json:"account_type"`
+	OpeningDebit   decimal.Decimal          `json:"opening_debit"`
+	OpeningCredit  decimal.Decimal          `json:"opening_credit"`
+	PeriodDebit    decimal.Decimal          `json:"period_debit"`
+	PeriodCredit   decimal.Decimal          `json:"period_credit"`
+	ClosingBalance decimal.Decimal          `json:"closing_balance"`
+}
+
+func newAccountingPeriodResponse(p *models.AccountingPeriod) *AccountingPeriodResponse {
+	return &AccountingPeriodResponse{
+		Period:     p.Period,
+		StartsAt:   p.StartsAt,
+		EndsAt:     p.EndsAt,
+		Status:     p.Status,
+		ClosedAt:   p.ClosedAt,
+		ClosedBy:   p.ClosedBy,
+		LockedAt:   p.LockedAt,
+		LockedBy:   p.LockedBy,
+		ReopenedAt: p.ReopenedAt,
+		ReopenedBy: p.ReopenedBy,
+	}
+}
+
+func (s *AccountingPeriodService) List(ctx context.Context) ([]*AccountingPeriodResponse, error) {
+	periods, err := s.periodRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounting periods: %w", err)
+	}
+
+	result := make([]*AccountingPeriodResponse, len(periods))
+	for i := range periods {
+		result[i] = newAccountingPeriodResponse(&periods[i])
+	}
+	return result, nil
+}
+
+func (s *AccountingPeriodService) Get(ctx context.Context, period string) (*AccountingPeriodResponse, error) {
+	if _, _, err := periodBounds(period); err != nil {
+		return nil, err
+	}
+
+	p, err := s.periodRepo.GetByPeriod(ctx, period)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrPeriodNotFound, period)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounting period: %w", err)
+	}
+
+	resp := newAccountingPeriodResponse(p)
+	if p.Status != models.PeriodStatusOpen {
+		balances, err := s.periodRepo.ListBalances(ctx, p.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list period balances: %w", err)
+		}
+		for _, b := range balances {
+			resp.Balances = append(resp.Balances, &PeriodBalanceResponse{
+				AccountCode:    b.AccountCode,
+				AccountName:    b.AccountName,
+				AccountType:    b.AccountType,
+				OpeningDebit:   b.OpeningDebit,
+				OpeningCredit:  b.OpeningCredit,
+				PeriodDebit:    b.PeriodDebit,
+				PeriodCredit:   b.PeriodCredit,
+				ClosingBalance: b.ClosingBalance,
+			})
+		}
+	}
+	return resp, nil
+}
+
+func (s *AccountingPeriodService) Close(ctx context.Context, period, operator string) (*AccountingPeriodResponse, error) {
+	return s.transition(ctx, period, operator, func(tx *sqlx.Tx, p *models.AccountingPeriod, now time.Time) error {
+		if p.Status != models.PeriodStatusOpen {
+			return fmt.Errorf("%w: %s is %s", ErrInvalidPeriodTransition, p.Period, p.Status)
+		}
+		if now.Before(p.EndsAt) {
+			return fmt.Errorf("%w: %s has not ended yet", ErrInvalidPeriodTransition, p.Period)
+		}
+		if err := s.ledgerRepo.LockChain(ctx, tx); err != nil {
+			return fmt.Errorf("failed to lock ledger chain: %w", err)
+		}
+		if err := s.periodRepo.SnapshotBalances(ctx, tx, p); err != nil {
+			return fmt.Errorf("failed to snapshot period balances: %w", err)
+		}
+		p.Status = models.PeriodStatusClosed
+		p.ClosedAt = &now
+		p.ClosedBy = &operator
+		return nil
+	})
+}
+
+func (s *AccountingPeriodService) Lock(ctx context.Context, period, operator string) (*AccountingPeriodResponse, error) {
+	return s.transition(ctx, period, operator, func(tx *sqlx.Tx, p *models.AccountingPeriod, now time.Time) error {
+		if p.Status != models.PeriodStatusClosed {
+			return fmt.Errorf("%w: only closed periods can be locked, %s is %s", ErrInvalidPeriodTransition, p.Period, p.Status)
+		}
+		p.Status = models.PeriodStatusLocked
+		p.LockedAt = &now
+		p.LockedBy = &operator
+		return nil
+	})
+}
+
+func (s *AccountingPeriodService) Reopen(ctx context.Context, period, operator string) (*AccountingPeriodResponse, error) {
+	return s.transition(ctx, period, operator, func(tx *sqlx.Tx, p *models.AccountingPeriod, now time.Time) error {
+		if p.Status != models.PeriodStatusClosed {
+			return fmt.Errorf("%w: only closed periods can be reopened, %s is %s", ErrInvalidPeriodTransition, p.Period, p.Status)
+		}
+		p.Status = models.PeriodStatusOpen
+		p.ReopenedAt = &now
+		p.ReopenedBy = &operator
+		return nil
+	})
+}
+
+func (s *AccountingPeriodService) transition(ctx context.Context, period, operator string, apply func(*sqlx.Tx, *models.AccountingPeriod, time.Time) error) (*AccountingPeriodResponse, error) {
+	if operator == "" {
+		return nil, ErrOperatorRequired
+	}
+	startsAt, endsAt, err := periodBounds(period)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	err = s.periodRepo.Ensure(ctx, tx, &models.AccountingPeriod{
+		ID:        uuid.New(),
+		Period:    period,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		Status:    models.PeriodStatusOpen,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create accounting period: %w", err)
+	}
+	p, err := s.periodRepo.GetForUpdate(ctx, tx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounting period: %w", err)
+	}
+
+	if err := apply(tx, p, now); err != nil {
+		return nil, err
+	}
+	if err := s.periodRepo.UpdateStatus(ctx, tx, p); err != nil {
+		return nil, fmt.Errorf("failed to update accounting period: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"period":   p.Period,
+		"status":   p.Status,
+		"operator": operator,
+	}).Info("Accounting period updated")
+
+	return s.Get(ctx, period)
+}
+
+func periodBounds(period string) (time.Time, time.Time, error) {
+	istLocation, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to load IST timezone: %w", err)
+	}
+	start, err := time.ParseInLocation("2006-01", period, istLocation)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: period must be YYYY-MM", ErrInvalidPeriod)
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+func accountingPeriodOf(t time.Time) (string, error) {
+	istLocation, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		return "", fmt.Errorf("failed to load IST timezone: %w", err)
+	}
+	return t.In(istLocation).Format("2006-01"), nil
+}
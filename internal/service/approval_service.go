@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+type ApprovalPolicy struct {
+	ThresholdINR decimal.Decimal
+	Expiry       time.Duration
+}
+
+type ApprovalService struct {
+	approvalRepo  *repository.ApprovalRepository
+	rewardRepo    *repository.RewardRepository
+	rewardService *RewardService
+	db            *sqlx.DB
+}
+
+func NewApprovalService(
+	approvalRepo *repository.ApprovalRepository,
+	rewardRepo *repository.RewardRepository,
+	rewardService *RewardService,
+	db *sqlx.DB,
+) *ApprovalService {
+	return &ApprovalService{
+		approvalRepo:  approvalRepo,
+		rewardRepo:    rewardRepo,
+		rewardService: rewardService,
+		db:            db,
+	}
+}
+
+type RejectRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type ApprovalResponse struct {
+	EventID     uuid.UUID             `json:"event_id"`
+	Status      models.ApprovalStatus `json:"status"`
+	ValueINR    decimal.Decimal       `json:"value_inr"`
+	RequestedBy string                `json:"requested_by"`
+	DecidedBy   *string               `json:"decided_by,omitempty"`
+	Reason      *string               `json:"reason,omitempty"`
+	ExpiresAt   time.Time             `json:"expires_at"`
+	DecidedAt   *time.Time            `json:"decided_at,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+func newApprovalResponse(a *models.RewardApproval) *ApprovalResponse {
+	return &ApprovalResponse{
+		EventID:     a.EventID,
+		Status:      a.Status,
+		ValueINR:    a.ValueINR,
+		RequestedBy: a.RequestedBy,
+		DecidedBy:   a.DecidedBy,
+		Reason:      a.Reason,
+		ExpiresAt:   a.ExpiresAt,
+		DecidedAt:   a.DecidedAt,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+func (s *ApprovalService) ListApprovals(ctx context.Context, status models.ApprovalStatus) ([]*ApprovalResponse, error) {
+	approvals, err := s.approvalRepo.ListByStatus(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+
+	result := make([]*ApprovalResponse, len(approvals))
+	for i := range approvals {
+		result[i] = newApprovalResponse(&approvals[i])
+	}
+	return result, nil
+}
+
+func (s *ApprovalService) Approve(ctx context.Context, eventID uuid.UUID, approver string) (*ApprovalResponse, error) {
+	if approver == "" {
+		return nil, ErrOperatorRequired
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	approval, err := s.lockPending(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if approval.ExpiresAt.Before(time.Now()) {
+		if err := s.expire(ctx, tx, approval); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil, ErrApprovalExpired
+	}
+	if approval.RequestedBy == approver {
+		return nil, ErrSelfApproval
+	}
+
+	reward, err := s.rewardRepo.GetByEventIDForUpdate(ctx, tx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reward: %w", err)
+	}
+	if err := s.rewardService.checkLockedPeriod(ctx, reward.Timestamp); err != nil {
+		return nil, err
+	}
+
+	stockPrice, err := s.rewardService.priceAt(ctx, reward.StockSymbol, reward.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reward.BookingPrice = &stockPrice.Price
+	reward.PricedAt = &stockPrice.FetchedAt
+	reward.Status = models.RewardStatusBooked
+	reward.BookedAt = &now
+	if err := s.rewardRepo.UpdateStatus(ctx, tx, reward); err != nil {
+		return nil, fmt.Errorf("failed to update reward status: %w", err)
+	}
+	if err := s.rewardService.bookReward(ctx, tx, reward); err != nil {
+		return nil, err
+	}
+
+	if err := s.decide(ctx, tx, approval, models.ApprovalStatusApproved, approver, ""); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event_id":     eventID,
+		"requested_by": approval.RequestedBy,
+		"approved_by":  approver,
+		"price":        stockPrice.Price,
+	}).Info("Reward approved")
+
+	s.rewardService.replenishInventory(ctx, reward.StockSymbol)
+
+	return newApprovalResponse(approval), nil
+}
+
+func (s *ApprovalService) Reject(ctx context.Context, eventID uuid.UUID, approver string, req RejectRequest) (*ApprovalResponse, error) {
+	if approver == "" {
+		return nil, ErrOperatorRequired
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	approval, err := s.lockPending(ctx, tx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if approval.RequestedBy == approver {
+		return nil, ErrSelfApproval
+	}
+
+	if err := s.closeReward(ctx, tx, eventID, models.RewardStatusRejected); err != nil {
+		return nil, err
+	}
+	if err := s.decide(ctx, tx, approval, models.ApprovalStatusRejected, approver, req.Reason); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event_id":    eventID,
+		"rejected_by": approver,
+		"reason":      req.Reason,
+	}).Info("Reward rejected")
+
+	return newApprovalResponse(approval), nil
+}
+
+func (s *ApprovalService) ExpirePending(ctx context.Context) error {
+	eventIDs, err := s.approvalRepo.ListExpiredPendingIDs(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to list expired approvals: %w", err)
+	}
+
+	for _, eventID := range eventIDs {
+		if err := s.expireOne(ctx, eventID); err != nil {
+			logrus.WithError(err).WithField("event_id", eventID).Error("Failed to expire approval")
+		}
+	}
+	return nil
+}
+
+func (s *ApprovalService) expireOne(ctx context.Context, eventID uuid.UUID) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	approval, err := s.lockPending(ctx, tx, eventID)
+	if err != nil {
+		return err
+	}
+	if err := s.expire(ctx, tx, approval); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logrus.WithField("event_id", eventID).Info("Reward approval expired")
+	return nil
+}
+
+func (s *ApprovalService) lockPending(ctx context.Context, tx *sqlx.Tx, eventID uuid.UUID) (*models.RewardApproval, error) {
+	approval, err := s.approvalRepo.GetByEventIDForUpdate(ctx, tx, eventID)
+	if err == sql.ErrNoRows {
+		return nil, ErrApprovalNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval request: %w", err)
+	}
+	if approval.Status != models.ApprovalStatusPending {
+		return nil, fmt.Errorf("%w: status is %s", ErrApprovalNotPending, approval.Status)
+	}
+	return approval, nil
+}
+
+func (s *ApprovalService) expire(ctx context.Context, tx *sqlx.Tx, approval *models.RewardApproval) error {
+	if err := s.closeReward(ctx, tx, approval.EventID, models.RewardStatusExpired); err != nil {
+		return err
+	}
+	return s.decide(ctx, tx, approval, models.ApprovalStatusExpired, "system", "approval window elapsed")
+}
+
+func (s *ApprovalService) closeReward(ctx context.Context, tx *sqlx.Tx, eventID uuid.UUID, status models.RewardStatus) error {
+	reward, err := s.rewardRepo.GetByEventIDForUpdate(ctx, tx, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to get reward: %w", err)
+	}
+
+	from := reward.Status
+	reward.Status = status
+	if err := s.rewardRepo.UpdateStatus(ctx, tx, reward); err != nil {
+		return fmt.Errorf("failed to update reward status: %w", err)
+	}
+	return recordRewardEvent(ctx, tx, s.rewardService.outboxRepo, models.OutboxEventRewardStatusChanged, reward, from)
+}
+
+func (s *ApprovalService) decide(ctx context.Context, tx *sqlx.Tx, approval *models.RewardApproval, status models.ApprovalStatus, operator, reason string) error {
+	now := time.Now()
+	approval.Status = status
+	approval.DecidedBy = &operator
+	approval.DecidedAt = &now
+	if reason != "" {
+		approval.Reason = &reason
+	}
+
+	if err := s.approvalRepo.UpdateDecision(ctx, tx, approval); err != nil {
+		return fmt.Errorf("failed to record approval decision: %w", err)
+	}
+	return nil
+}
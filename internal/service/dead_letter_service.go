@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+type DeadLetterService struct {
+	deadLetterRepo *repository.DeadLetterRepository
+	rewardService  *RewardService
+}
+
+func NewDeadLetterService(deadLetterRepo *repository.DeadLetterRepository, rewardService *RewardService) *DeadLetterService {
+	return &DeadLetterService{
+		deadLetterRepo: deadLetterRepo,
+		rewardService:  rewardService,
+	}
+}
+
+type DeadLetterResponse struct {
+	ID          uuid.UUID                   `json:"id"`
+	EventID     uuid.UUID                   `json:"event_id"`
+	Source      models.DeadLetterSource     `json:"source"`
+	Payload     json.RawMessage             `json:"payload"`
+	Error       string                      `json:"error"`
+	ErrorClass  models.DeadLetterErrorClass `json:"error_class"`
+	Attempts    int                         `json:"attempts"`
+	Status      models.DeadLetterStatus     `json:"status"`
+	ReplayCount int                         `json:"replay_count"`
+	ReplayedBy  *string                     `json:"replayed_by,omitempty"`
+	ReplayedAt  *time.Time                  `json:"replayed_at,omitempty"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	UpdatedAt   time.Time                   `json:"updated_at"`
+}
+
+type BulkReplayRequest struct {
+	ErrorClass models.DeadLetterErrorClass `json:"error_class" binding:"required"`
+}
+
+type BulkReplayResponse struct {
+	ErrorClass models.DeadLetterErrorClass `json:"error_class"`
+	Attempted  int                         `json:"attempted"`
+	Replayed   int                         `json:"replayed"`
+	Failed     int                         `json:"failed"`
+}
+
+func newDeadLetterResponse(l *models.RewardDeadLetter) *DeadLetterResponse {
+	return &DeadLetterResponse{
+		ID:          l.ID,
+		EventID:     l.EventID,
+		Source:      l.Source,
+		Payload:     json.RawMessage(l.Payload),
+		Error:       l.Error,
+		ErrorClass:  l.ErrorClass,
+		Attempts:    l.Attempts,
+		Status:      l.Status,
+		ReplayCount: l.ReplayCount,
+		ReplayedBy:  l.ReplayedBy,
+		ReplayedAt:  l.ReplayedAt,
+		CreatedAt:   l.CreatedAt,
+		UpdatedAt:   l.UpdatedAt,
+	}
+}
+
+func (s *DeadLetterService) CaptureRequest(ctx context.Context, source models.DeadLetterSource, req RewardRequest, attempts int, cause error) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode reward request: %w", err)
+	}
+	return s.capture(ctx, source, req.EventID, payload, attempts, cause)
+}
+
+func (s *DeadLetterService) capture(ctx context.Context, source models.DeadLetterSource, eventID uuid.UUID, payload []byte, attempts int, cause error) error {
+	letter := &models.RewardDeadLetter{
+		ID:         uuid.New(),
+		EventID:    eventID,
+		Source:     source,
+		Payload:    payload,
+		Error:      cause.Error(),
+		ErrorClass: classifyRewardError(cause),
+		Attempts:   attempts,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.deadLetterRepo.Upsert(ctx, letter); err != nil {
+		return fmt.Errorf("failed to store dead letter: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event_id":    eventID,
+		"source":      source,
+		"error_class": letter.ErrorClass,
+	}).Warn("Reward request dead-lettered")
+	return nil
+}
+
+func (s *DeadLetterService) List(ctx context.Context, status models.DeadLetterStatus, errorClass models.DeadLetterErrorClass) ([]*DeadLetterResponse, error) {
+	letters, err := s.deadLetterRepo.List(ctx, status, errorClass)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	result := make([]*DeadLetterResponse, len(letters))
+	for i := range letters {
+		result[i] = newDeadLetterResponse(&letters[i])
+	}
+	return result, nil
+}
+
+func (s *DeadLetterService) Get(ctx context.Context, id uuid.UUID) (*DeadLetterResponse, error) {
+	letter, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return newDeadLetterResponse(letter), nil
+}
+
+func (s *DeadLetterService) UpdatePayload(ctx context.Context, id uuid.UUID, req RewardRequest) (*DeadLetterResponse, error) {
+	letter, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if letter.Status != models.DeadLetterStatusOpen {
+		return nil, ErrDeadLetterNotOpen
+	}
+	if req.EventID != letter.EventID {
+		return nil, fmt.Errorf("%w: event_id cannot be changed", ErrInvalidPayload)
+	}
+
+	var original RewardRequest
+	if err := json.Unmarshal(letter.Payload, &original); err != nil {
+		original = RewardRequest{ReceivedAt: letter.CreatedAt}
+	}
+	req.ReceivedAt = original.ReceivedAt
+	req.RequestedBy = original.RequestedBy
+	req.PartnerID = original.PartnerID
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode reward request: %w", err)
+	}
+	if err := s.deadLetterRepo.UpdatePayload(ctx, id, payload); err != nil {
+		return nil, fmt.Errorf("failed to update dead letter: %w", err)
+	}
+
+	return s.Get(ctx, id)
+}
+
+func (s *DeadLetterService) Replay(ctx context.Context, id uuid.UUID, operator string) (*DeadLetterResponse, error) {
+	letter, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.replay(ctx, letter, operator); err != nil {
+		return nil, err
+	}
+	return newDeadLetterResponse(letter), nil
+}
+
+func (s *DeadLetterService) ReplayByErrorClass(ctx context.Context, req BulkReplayRequest, operator string) (*BulkReplayResponse, error) {
+	letters, err := s.deadLetterRepo.List(ctx, models.DeadLetterStatusOpen, req.ErrorClass)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	resp := &BulkReplayResponse{ErrorClass: req.ErrorClass, Attempted: len(letters)}
+	for i := range letters {
+		letter := &letters[i]
+		if err := s.replay(ctx, letter, operator); err != nil {
+			logrus.WithError(err).WithField("dead_letter_id", letter.ID).Error("Failed to replay dead letter")
+		}
+		if letter.Status == models.DeadLetterStatusReplayed {
+			resp.Replayed++
+		} else {
+			resp.Failed++
+		}
+	}
+	return resp, nil
+}
+
+func (s *DeadLetterService) replay(ctx context.Context, letter *models.RewardDeadLetter, operator string) error {
+	if letter.Status != models.DeadLetterStatusOpen {
+		return ErrDeadLetterNotOpen
+	}
+
+	var req RewardRequest
+	replayErr := json.Unmarshal(letter.Payload, &req)
+	if replayErr != nil {
+		replayErr = fmt.Errorf("%w: %v", ErrInvalidPayload, replayErr)
+	} else {
+		_, replayErr = s.rewardService.ProcessReward(ctx, req)
+	}
+
+	now := time.Now()
+	letter.ReplayCount++
+	letter.ReplayedAt = &now
+	if operator != "" {
+		letter.ReplayedBy = &operator
+	}
+	if replayErr == nil {
+		letter.Status = models.DeadLetterStatusReplayed
+	} else {
+		letter.Attempts++
+		letter.Error = replayErr.Error()
+		letter.ErrorClass = classifyRewardError(replayErr)
+	}
+
+	if err := s.deadLetterRepo.RecordReplay(ctx, letter); err != nil {
+		return fmt.Errorf("failed to record replay: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"dead_letter_id": letter.ID,
+		"event_id":       letter.EventID,
+		"status":         letter.Status,
+		"error_class":    letter.ErrorClass,
+	}).Info("Dead letter replayed")
+	return nil
+}
+
+func (s *DeadLetterService) get(ctx context.Context, id uuid.UUID) (*models.RewardDeadLetter, error) {
+	letter, err := s.deadLetterRepo.GetByID(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead letter: %w", err)
+	}
+	return letter, nil
+}
+
+func classifyRewardError(err error) models.DeadLetterErrorClass {
+	var pqErr *pq.Error
+	switch {
+	case errors.Is(err, ErrPriceUnavailable):
+		return models.DeadLetterErrorPriceUnavailable
+	case errors.Is(err, ErrInvalidVestingSchedule), errors.Is(err, ErrInvalidPayload),
+		errors.Is(err, ErrInvalidTimestamp), errors.Is(err, ErrPeriodClosed):
+		return models.DeadLetterErrorValidation
+	case errors.As(err, &pqErr), errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone):
+		return models.DeadLetterErrorDatabase
+	default:
+		return models.DeadLetterErrorUnknown
+	}
+}
@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/depository"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+var (
+	dpIDPattern     = regexp.MustCompile(`^(IN[0-9]{6}|[0-9]{8})$`)
+	clientIDPattern = regexp.MustCompile(`^[0-9]{8}$`)
+)
+
+type DematWithdrawalService struct {
+	withdrawalRepo *repository.DematWithdrawalRepository
+	rewardRepo     *repository.RewardRepository
+	ledgerRepo     *repository.LedgerRepository
+	rewardService  *RewardService
+	depository     depository.Depository
+	outboxRepo     *repository.OutboxRepository
+	db             *sqlx.DB
+}
+
+func NewDematWithdrawalService(
+	withdrawalRepo *repository.DematWithdrawalRepository,
+	rewardRepo *repository.RewardRepository,
+	ledgerRepo *repository.LedgerRepository,
+	rewardService *RewardService,
+	depositoryClient depository.Depository,
+	outboxRepo *repository.OutboxRepository,
+	db *sqlx.DB,
+) *DematWithdrawalService {
+	return &DematWithdrawalService{
+		withdrawalRepo: withdrawalRepo,
+		rewardRepo:     rewardRepo,
+		ledgerRepo:     ledgerRepo,
+		rewardService:  rewardService,
+		depository:     depositoryClient,
+		outboxRepo:     outboxRepo,
+		db:             db,
+	}
+}
+
+type WithdrawalRequest struct {
+	WithdrawalID uuid.UUID       `json:"withdrawal_id" binding:"required"`
+	UserID       uuid.UUID       `json:"user_id" binding:"required"`
+	StockSymbol  string          `json:"stock_symbol" binding:"required"`
+	Quantity     decimal.Decimal `json:"quantity" binding:"required"`
+	DPID         string          `json:"dp_id" binding:"required"`
+	ClientID     string          `json:"client_id" binding:"required"`
+}
+
+type WithdrawalResponse struct {
+	WithdrawalID  uuid.UUID               `json:"withdrawal_id"`
+	UserID        uuid.UUID               `json:"user_id"`
+	StockSymbol   string                  `json:"stock_symbol"`
+	Quantity      decimal.Decimal         `json:"quantity"`
+	ValueINR      decimal.Decimal         `json:"value_inr"`
+	DPID          string                  `json:"dp_id"`
+	ClientID      string                  `json:"client_id"`
+	Status        models.WithdrawalStatus `json:"status"`
+	DepositoryRef *string                 `json:"depository_ref,omitempty"`
+	FailureReason *string                 `json:"failure_reason,omitempty"`
+	SubmittedAt   *time.Time              `json:"submitted_at,omitempty"`
+	CompletedAt   *time.Time              `json:"completed_at,omitempty"`
+	FailedAt      *time.Time              `json:"failed_at,omitempty"`
+	CreatedAt     time.Time               `json:"created_at"`
+}
+
+func newWithdrawalResponse(w *models.DematWithdrawal) *WithdrawalResponse {
+	return &WithdrawalResponse{
+		WithdrawalID:  w.ID,
+		UserID:        w.UserID,
+		StockSymbol:   w.StockSymbol,
+		Quantity:      w.Quantity,
+		ValueINR:      w.ValueINR,
+		DPID:          w.DPID,
+		ClientID:      w.ClientID,
+		Status:        w.Status,
+		DepositoryRef: w.DepositoryRef,
+		FailureReason: w.FailureReason,
+		SubmittedAt:   w.SubmittedAt,
+		CompletedAt:   w.CompletedAt,
+		FailedAt:      w.FailedAt,
+		CreatedAt:     w.CreatedAt,
+	}
+}
+
+func (s *DematWithdrawalService) RequestWithdrawal(ctx context.Context, req WithdrawalRequest) (*WithdrawalResponse, error) {
+	if !req.Quantity.IsPositive() || !req.Quantity.IsInteger() {
+		return nil, fmt.Errorf("%w: quantity must be a positive whole number of shares", ErrInvalidWithdrawal)
+	}
+	if !dpIDPattern.MatchString(req.DPID) {
+		return nil, fmt.Errorf("%w: dp_id must be IN followed by 6 digits (NSDL) or 8 digits (CDSL)", ErrInvalidWithdrawal)
+	}
+	if !clientIDPattern.MatchString(req.ClientID) {
+		return nil, fmt.Errorf("%w: client_id must be 8 digits", ErrInvalidWithdrawal)
+	}
+
+	existing, err := s.withdrawalRepo.GetByID(ctx, req.WithdrawalID)
+	if err == nil {
+		return s.replay(existing, req)
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+
+	now := time.Now()
+	stockPrice, err := s.rewardService.priceAt(ctx, req.StockSymbol, now)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.rewardRepo.LockHolding(ctx, tx, req.UserID, req.StockSymbol); err != nil {
+		return nil, fmt.Errorf("failed to lock holding: %w", err)
+	}
+
+	available, err := availableShares(ctx, s.ledgerRepo, s.rewardRepo, req.UserID, req.StockSymbol)
+	if err != nil {
+		return nil, err
+	}
+	settled := available.Floor()
+	if req.Quantity.GreaterThan(settled) {
+		return nil, fmt.Errorf("%w: requested=%s, settled whole shares=%s", ErrInsufficientHoldings, req.Quantity, settled)
+	}
+
+	withdrawal := &models.DematWithdrawal{
+		ID:          req.WithdrawalID,
+		UserID:      req.UserID,
+		StockSymbol: req.StockSymbol,
+		Quantity:    req.Quantity,
+		Price:       stockPrice.Price,
+		ValueINR:    stockPrice.Price.Mul(req.Quantity).Round(4),
+		DPID:        req.DPID,
+		ClientID:    req.ClientID,
+		Status:      models.WithdrawalStatusRequested,
+		CreatedAt:   now,
+	}
+	inserted, err := s.withdrawalRepo.Create(ctx, tx, withdrawal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create withdrawal: %w", err)
+	}
+	if !inserted {
+		tx.Rollback()
+		existing, err := s.withdrawalRepo.GetByID(ctx, req.WithdrawalID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get withdrawal: %w", err)
+		}
+		return s.replay(existing, req)
+	}
+
+	if err := s.post(ctx, tx, withdrawal, models.LedgerEntryTypeStock, models.LedgerEntryTypeWithdrawalHold); err != nil {
+		return nil, err
+	}
+	if err := s.recordStatus(ctx, tx, withdrawal); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"withdrawal_id": withdrawal.ID,
+		"user_id":       withdrawal.UserID,
+		"stock_symbol":  withdrawal.StockSymbol,
+		"quantity":      withdrawal.Quantity,
+	}).Info("Demat withdrawal requested")
+
+	return newWithdrawalResponse(withdrawal), nil
+}
+
+func (s *DematWithdrawalService) GetWithdrawal(ctx context.Context, id uuid.UUID) (*WithdrawalResponse, error) {
+	withdrawal, err := s.withdrawalRepo.GetByID(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrWithdrawalNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get withdrawal: %w", err)
+	}
+	return newWithdrawalResponse(withdrawal), nil
+}
+
+func (s *DematWithdrawalService) ListWithdrawals(ctx context.Context, userID uuid.UUID) ([]*WithdrawalResponse, error) {
+	withdrawals, err := s.withdrawalRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list withdrawals: %w", err)
+	}
+
+	result := make([]*WithdrawalResponse, len(withdrawals))
+	for i := range withdrawals {
+		result[i] = newWithdrawalResponse(&withdrawals[i])
+	}
+	return result, nil
+}
+
+func (s *DematWithdrawalService) ProcessWithdrawals(ctx context.Context) error {
+	if err := s.SubmitRequested(ctx); err != nil {
+		return err
+	}
+	return s.PollSubmitted(ctx)
+}
+
+func (s *DematWithdrawalService) SubmitRequested(ctx context.Context) error {
+	withdrawals, err := s.withdrawalRepo.ListByStatus(ctx, models.WithdrawalStatusRequested)
+	if err != nil {
+		return fmt.Errorf("failed to list requested withdrawals: %w", err)
+	}
+
+	for _, w := range withdrawals {
+		reference, err := s.depository.InitiateTransfer(ctx, depository.TransferInstruction{
+			RequestID: w.ID,
+			Symbol:    w.StockSymbol,
+			Quantity:  w.Quantity.IntPart(),
+			DPID:      w.DPID,
+			ClientID:  w.ClientID,
+		})
+		if err != nil {
+			logrus.WithError(err).WithField("withdrawal_id", w.ID).Error("Depository rejected withdrawal")
+			if err := s.finish(ctx, w.ID, models.WithdrawalStatusFailed, err.Error()); err != nil {
+				logrus.WithError(err).WithField("withdrawal_id", w.ID).Error("Failed to record withdrawal failure")
+			}
+			continue
+		}
+
+		if err := s.markSubmitted(ctx, w.ID, reference); err != nil {
+			logrus.WithError(err).WithField("withdrawal_id", w.ID).Error("Failed to record withdrawal submission")
+		}
+	}
+	return nil
+}
+
+func (s *DematWithdrawalService) PollSubmitted(ctx context.Context) error {
+	withdrawals, err := s.withdrawalRepo.ListByStatus(ctx, models.WithdrawalStatusSubmitted)
+	if err != nil {
+		return fmt.Errorf("failed to list submitted withdrawals: %w", err)
+	}
+
+	for _, w := range withdrawals {
+		result, err := s.depository.GetTransferStatus(ctx, *w.DepositoryRef)
+		if err != nil {
+			logrus.WithError(err).WithField("withdrawal_id", w.ID).Error("Failed to fetch depository transfer status")
+			continue
+		}
+
+		var status models.WithdrawalStatus
+		switch result.Status {
+		case depository.TransferStatusCompleted:
+			status = models.WithdrawalStatusCompleted
+		case depository.TransferStatusFailed:
+			status = models.WithdrawalStatusFailed
+		default:
+			continue
+		}
+		if err := s.finish(ctx, w.ID, status, result.Reason); err != nil {
+			logrus.WithError(err).WithField("withdrawal_id", w.ID).Error("Failed to record withdrawal result")
+		}
+	}
+	return nil
+}
+
+func (s *DematWithdrawalService) markSubmitted(ctx context.Context, id uuid.UUID, reference string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	withdrawal, err := s.withdrawalRepo.GetByIDForUpdate(ctx, tx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get withdrawal: %w", err)
+	}
+	if withdrawal.Status != models.WithdrawalStatusRequested {
+		return nil
+	}
+
+	now := time.Now()
+	withdrawal.Status = models.WithdrawalStatusSubmitted
+	withdrawal.DepositoryRef = &reference
+	withdrawal.SubmittedAt = &now
+	if err := s.withdrawalRepo.UpdateStatus(ctx, tx, withdrawal); err != nil {
+		return fmt.Errorf("failed to update withdrawal: %w", err)
+	}
+	if err := s.recordStatus(ctx, tx, withdrawal); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *DematWithdrawalService) finish(ctx context.Context, id uuid.UUID, status models.WithdrawalStatus, reason string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	withdrawal, err := s.withdrawalRepo.GetByIDForUpdate(ctx, tx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get withdrawal: %w", err)
+	}
+	if withdrawal.Status != models.WithdrawalStatusRequested && withdrawal.Status != models.WithdrawalStatusSubmitted {
+		return nil
+	}
+
+	now := time.Now()
+	withdrawal.Status = status
+	if status == models.WithdrawalStatusCompleted {
+		withdrawal.CompletedAt = &now
+		err = s.post(ctx, tx, withdrawal, models.LedgerEntryTypeWithdrawalHold, models.LedgerEntryTypeDematOut)
+	} else {
+		withdrawal.FailedAt = &now
+		withdrawal.FailureReason = &reason
+		err = s.post(ctx, tx, withdrawal, models.LedgerEntryTypeWithdrawalHold, models.LedgerEntryTypeStock)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.withdrawalRepo.UpdateStatus(ctx, tx, withdrawal); err != nil {
+		return fmt.Errorf("failed to update withdrawal: %w", err)
+	}
+	if err := s.recordStatus(ctx, tx, withdrawal); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"withdrawal_id": withdrawal.ID,
+		"status":        withdrawal.Status,
+		"reason":        reason,
+	}).Info("Demat withdrawal finished")
+	return nil
+}
+
+func (s *DematWithdrawalService) post(ctx context.Context, tx *sqlx.Tx, w *models.DematWithdrawal, debitType, creditType models.LedgerEntryType) error {
+	entries := ledgerTransfer(w.ID, debitType, creditType, &w.StockSymbol, w.ValueINR)
+	for _, entry := range entries {
+		entry.UserID = &w.UserID
+	}
+	return postLedgerEntries(ctx, tx, s.ledgerRepo, withHolding(entries, w.Quantity, w.CreatedAt))
+}
+
+func (s *DematWithdrawalService) recordStatus(ctx context.Context, tx *sqlx.Tx, w *models.DematWithdrawal) error {
+	payload := WithdrawalStatusPayload{
+		WithdrawalID:  w.ID,
+		UserID:        w.UserID,
+		StockSymbol:   w.StockSymbol,
+		Quantity:      w.Quantity,
+		Status:        w.Status,
+		FailureReason: w.FailureReason,
+	}
+	return recordOutboxEvent(ctx, tx, s.outboxRepo, models.OutboxEventWithdrawalChanged, "withdrawal", w.ID.String(), w.UserID.String(), payload)
+}
+
+func (s *DematWithdrawalService) replay(existing *models.DematWithdrawal, req WithdrawalRequest) (*WithdrawalResponse, error) {
+	if existing.UserID != req.UserID || existing.StockSymbol != req.StockSymbol || !existing.Quantity.Equal(req.Quantity) ||
+		existing.DPID != req.DPID || existing.ClientID != req.ClientID {
+		return nil, fmt.Errorf("%w: %s", ErrWithdrawalConflict, req.WithdrawalID)
+	}
+	logrus.WithField("withdrawal_id", req.WithdrawalID).Info("Withdrawal already requested (idempotent)")
+	return newWithdrawalResponse(existing), nil
+}
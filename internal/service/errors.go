@@ -0,0 +1,54 @@
+package service
+
+import "errors"
+
+var (
+	ErrRewardNotFound           = errors.New("reward not found")
+	ErrInvalidVestingSchedule   = errors.New("invalid vesting schedule")
+	ErrInsufficientUnvested     = errors.New("quantity exceeds unvested quantity")
+	ErrRewardNotBooked          = errors.New("reward is not booked")
+	ErrApprovalNotFound         = errors.New("approval request not found")
+	ErrApprovalNotPending       = errors.New("approval request is not pending")
+	ErrApprovalExpired          = errors.New("approval request has expired")
+	ErrSelfApproval             = errors.New("approver must differ from requester")
+	ErrOperatorRequired         = errors.New("operator id is required")
+	ErrInvalidTransition        = errors.New("invalid reward status transition")
+	ErrInvalidInventorySettings = errors.New("invalid inventory settings")
+	ErrInvalidPayload           = errors.New("invalid reward payload")
+	ErrPriceUnavailable         = errors.New("stock price unavailable")
+	ErrDeadLetterNotFound       = errors.New("dead letter not found")
+	ErrDeadLetterNotOpen        = errors.New("dead letter is not open")
+	ErrWebhookNotFound          = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound  = errors.New("webhook delivery not found")
+	ErrInvalidWebhook           = errors.New("invalid webhook subscription")
+	ErrPartnerMismatch          = errors.New("user belongs to a different partner")
+	ErrInvalidTimestamp         = errors.New("invalid reward timestamp")
+	ErrPeriodClosed             = errors.New("accounting period is closed")
+	ErrInvalidSchedule          = errors.New("invalid reward schedule")
+	ErrRewardNotScheduled       = errors.New("reward is not scheduled")
+	ErrRewardAlreadyExists      = errors.New("reward already exists")
+	ErrInvalidOffer             = errors.New("invalid reward offer")
+	ErrOfferNotFound            = errors.New("reward offer not found")
+	ErrOfferNotClaimable        = errors.New("reward offer is not claimable")
+	ErrOfferExpired             = errors.New("reward offer has expired")
+	ErrInvalidCampaign          = errors.New("invalid campaign")
+	ErrCampaignNotFound         = errors.New("campaign not found")
+	ErrCampaignBudgetExceeded   = errors.New("campaign budget exceeded")
+	ErrInvalidTransfer          = errors.New("invalid share transfer")
+	ErrInsufficientHoldings     = errors.New("insufficient available holdings")
+	ErrTransferConflict         = errors.New("transfer id already used with different details")
+	ErrInvalidSale              = errors.New("invalid share sale")
+	ErrSaleConflict             = errors.New("sale id already used with different details")
+	ErrInvalidWithdrawal        = errors.New("invalid withdrawal")
+	ErrWithdrawalNotFound       = errors.New("withdrawal not found")
+	ErrWithdrawalConflict       = errors.New("withdrawal id already used with different details")
+	ErrInvalidStatement         = errors.New("invalid statement request")
+	ErrReconciliationNotFound   = errors.New("no reconciliation run found")
+	ErrInvalidReport            = errors.New("invalid report request")
+	ErrInvalidExport            = errors.New("invalid ledger export")
+	ErrExportNotFound           = errors.New("ledger export not found")
+	ErrExportAlreadyImported    = errors.New("ledger export already marked imported")
+	ErrInvalidPeriod            = errors.New("invalid accounting period")
+	ErrPeriodNotFound           = errors.New("accounting period not found")
+	ErrInvalidPeriodTransition  = errors.New("invalid accounting period transition")
+)
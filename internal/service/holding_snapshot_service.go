@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/repository"
+)
+
+type HoldingSnapshotService struct {
+	ledgerRepo   *repository.LedgerRepository
+	lookbackDays int
+	db           *sqlx.DB
+}
+
+func NewHoldingSnapshotService(ledgerRepo *repository.LedgerRepository, lookbackDays int, db *sqlx.DB) *HoldingSnapshotService {
+	return &HoldingSnapshotService{
+		ledgerRepo:   ledgerRepo,
+		lookbackDays: lookbackDays,
+		db:           db,
+	}
+}
+
+func (s *HoldingSnapshotService) SnapshotDue(ctx context.Context) error {
+	today, err := holdingSnapshotDate(time.Now())
+	if err != nil {
+		return err
+	}
+	for days := s.lookbackDays; days >= 1; days-- {
+		date := today.AddDate(0, 0, -days)
+		if err := s.snapshot(ctx, date); err != nil {
+			logrus.WithError(err).WithField("date", date.Format("2006-01-02")).Error("Failed to snapshot holdings")
+		}
+	}
+	return nil
+}
+
+func (s *HoldingSnapshotService) snapshot(ctx context.Context, date time.Time) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.ledgerRepo.LockChain(ctx, tx); err != nil {
+		return fmt.Errorf("failed to lock ledger chain: %w", err)
+	}
+	created, err := s.ledgerRepo.SnapshotHoldings(ctx, tx, date)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot holdings: %w", err)
+	}
+	if !created {
+		return nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logrus.WithField("date", date.Format("2006-01-02")).Info("Holdings snapshot created")
+	return nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"stocky/internal/repository"
+)
+
+func availableShares(ctx context.Context, ledgerRepo *repository.LedgerRepository, rewardRepo *repository.RewardRepository, userID uuid.UUID, symbol string) (decimal.Decimal, error) {
+	held, err := ledgerRepo.GetHoldingBalances(ctx, userID)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get holdings: %w", err)
+	}
+	restricted, err := rewardRepo.GetRestrictedSharesByStock(ctx, userID)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get restricted shares: %w", err)
+	}
+	return held[symbol].Sub(restricted[symbol]), nil
+}
+
+func carryingCost(ctx context.Context, ledgerRepo *repository.LedgerRepository, userID uuid.UUID, symbol string, quantity decimal.Decimal) (decimal.Decimal, error) {
+	held, err := ledgerRepo.GetHoldingBalances(ctx, userID)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get holdings: %w", err)
+	}
+	value, err := ledgerRepo.GetStockValue(ctx, userID, symbol)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get holding value: %w", err)
+	}
+	if !held[symbol].IsPositive() {
+		return decimal.Zero, nil
+	}
+	if quantity.GreaterThanOrEqual(held[symbol]) {
+		return value, nil
+	}
+	return value.Mul(quantity).Div(held[symbol]).Round(ledgerPrecision), nil
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/broker"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+type InventoryPolicy struct {
+	ReplenishThreshold decimal.Decimal
+	ReplenishQuantity  int64
+}
+
+type InventoryService struct {
+	inventoryRepo *repository.InventoryRepository
+	orderRepo     *repository.OrderRepository
+	ledgerRepo    *repository.LedgerRepository
+	policy        InventoryPolicy
+	db            *sqlx.DB
+}
+
+func NewInventoryService(
+	inventoryRepo *repository.InventoryRepository,
+	orderRepo *repository.OrderRepository,
+	ledgerRepo *repository.LedgerRepository,
+	policy InventoryPolicy,
+	db *sqlx.DB,
+) *InventoryService {
+	return &InventoryService{
+		inventoryRepo: inventoryRepo,
+		orderRepo:     orderRepo,
+		ledgerRepo:    ledgerRepo,
+		policy:        policy,
+		db:            db,
+	}
+}
+
+type InventoryPoolResponse struct {
+	Symbol             string          `json:"symbol"`
+	UnallocatedQty     decimal.Decimal `json:"unallocated_quantity"`
+	Cost               decimal.Decimal `json:"cost"`
+	AllocatedQty       decimal.Decimal `json:"allocated_quantity"`
+	ReplenishThreshold decimal.Decimal `json:"replenish_threshold"`
+	ReplenishQuantity  int64           `json:"replenish_quantity"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+type UpdatePoolRequest struct {
+	ReplenishThreshold decimal.Decimal `json:"replenish_threshold" binding:"required"`
+	ReplenishQuantity  int64           `json:"replenish_quantity"`
+}
+
+func (s *InventoryService) ListPools(ctx context.Context) ([]InventoryPoolResponse, error) {
+	pools, err := s.inventoryRepo.ListPools(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inventory pools: %w", err)
+	}
+
+	allocated, err := s.inventoryRepo.GetAllocatedBySymbol(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocated quantities: %w", err)
+	}
+
+	result := make([]InventoryPoolResponse, len(pools))
+	for i, p := range pools {
+		result[i] = InventoryPoolResponse{
+			Symbol:             p.Symbol,
+			UnallocatedQty:     p.Quantity,
+			Cost:               p.Cost,
+			AllocatedQty:       allocated[p.Symbol],
+			ReplenishThreshold: p.ReplenishThreshold,
+			ReplenishQuantity:  p.ReplenishQuantity,
+			UpdatedAt:          p.UpdatedAt,
+		}
+	}
+	return result, nil
+}
+
+func (s *InventoryService) UpdatePool(ctx context.Context, symbol string, req UpdatePoolRequest) error {
+	if req.ReplenishThreshold.IsNegative() || req.ReplenishQuantity < 0 {
+		return fmt.Errorf("%w: replenishment settings must not be negative", ErrInvalidInventorySettings)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	pool, err := s.inventoryRepo.GetForUpdate(ctx, tx, symbol, s.defaults())
+	if err != nil {
+		return fmt.Errorf("failed to get inventory pool: %w", err)
+	}
+
+	pool.ReplenishThreshold = req.ReplenishThreshold
+	pool.ReplenishQuantity = req.ReplenishQuantity
+	if err := s.inventoryRepo.UpdateSettings(ctx, tx, pool); err != nil {
+		return fmt.Errorf("failed to update inventory pool: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return s.ReplenishIfNeeded(ctx, symbol)
+}
+
+func (s *InventoryService) ReplenishIfNeeded(ctx context.Context, symbol string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	pool, err := s.inventoryRepo.GetForUpdate(ctx, tx, symbol, s.defaults())
+	if err != nil {
+		return fmt.Errorf("failed to get inventory pool: %w", err)
+	}
+	if pool.ReplenishQuantity <= 0 || pool.Quantity.GreaterThanOrEqual(pool.ReplenishThreshold) {
+		return nil
+	}
+
+	open, err := s.orderRepo.HasOpenReplenishment(ctx, tx, symbol)
+	if err != nil {
+		return fmt.Errorf("failed to check open replenishment orders: %w", err)
+	}
+	if open {
+		return nil
+	}
+
+	order := &models.BrokerOrder{
+		ID:             uuid.New(),
+		Symbol:         symbol,
+		Side:           string(broker.SideBuy),
+		Purpose:        models.BrokerOrderPurposeReplenishment,
+		Quantity:       pool.ReplenishQuantity,
+		RewardQuantity: decimal.Zero,
+		BookedValue:    decimal.Zero,
+		Status:         models.BrokerOrderStatusPending,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.orderRepo.Create(ctx, tx, order); err != nil {
+		return fmt.Errorf("failed to create replenishment order: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"symbol":    symbol,
+		"pool":      pool.Quantity,
+		"threshold": pool.ReplenishThreshold,
+		"order_id":  order.ID,
+		"quantity":  order.Quantity,
+	}).Info("Inventory replenishment order created")
+
+	return nil
+}
+
+func (s *InventoryService) allocate(ctx context.Context, tx *sqlx.Tx, symbol string, quantity decimal.Decimal, eventID uuid.UUID) (bool, error) {
+	pool, err := s.inventoryRepo.GetForUpdate(ctx, tx, symbol, s.defaults())
+	if err != nil {
+		return false, fmt.Errorf("failed to get inventory pool: %w", err)
+	}
+	if pool.Quantity.LessThan(quantity) {
+		return false, nil
+	}
+
+	cost := pool.Cost
+	if quantity.LessThan(pool.Quantity) {
+		cost = pool.Cost.Mul(quantity).Div(pool.Quantity).Round(ledgerPrecision)
+	}
+	if err := s.addMovement(ctx, tx, symbol, quantity.Neg(), cost.Neg(), models.InventoryMovementAllocation, eventID); err != nil {
+		return false, err
+	}
+	if err := s.post(ctx, tx, eventID, models.LedgerEntryTypeCustody, models.LedgerEntryTypeInventory, symbol, cost); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *InventoryService) addToPool(ctx context.Context, tx *sqlx.Tx, symbol string, quantity, cost decimal.Decimal, from models.LedgerEntryType, movementType models.InventoryMovementType, referenceID uuid.UUID) error {
+	if _, err := s.inventoryRepo.GetForUpdate(ctx, tx, symbol, s.defaults()); err != nil {
+		return fmt.Errorf("failed to get inventory pool: %w", err)
+	}
+	cost = cost.Round(ledgerPrecision)
+	if err := s.addMovement(ctx, tx, symbol, quantity, cost, movementType, referenceID); err != nil {
+		return err
+	}
+	return s.post(ctx, tx, referenceID, models.LedgerEntryTypeInventory, from, symbol, cost)
+}
+
+func (s *InventoryService) addMovement(ctx context.Context, tx *sqlx.Tx, symbol string, quantity, cost decimal.Decimal, movementType models.InventoryMovementType, referenceID uuid.UUID) error {
+	movement := &models.InventoryMovement{
+		ID:           uuid.New(),
+		Symbol:       symbol,
+		Quantity:     quantity,
+		Cost:         cost,
+		MovementType: movementType,
+		ReferenceID:  referenceID,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.inventoryRepo.AddMovement(ctx, tx, movement); err != nil {
+		return fmt.Errorf("failed to record inventory movement: %w", err)
+	}
+	return nil
+}
+
+func (s *InventoryService) post(ctx context.Context, tx *sqlx.Tx, eventID uuid.UUID, debitType, creditType models.LedgerEntryType, symbol string, cost decimal.Decimal) error {
+	if cost.IsZero() {
+		return nil
+	}
+	return postLedgerEntries(ctx, tx, s.ledgerRepo, ledgerTransfer(eventID, debitType, creditType, &symbol, cost))
+}
+
+func (s *InventoryService) defaults() *models.InventoryPool {
+	return &models.InventoryPool{
+		ReplenishThreshold: s.policy.ReplenishThreshold,
+		ReplenishQuantity:  s.policy.ReplenishQuantity,
+	}
+}
@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+	"stocky/pkg/fees"
+)
+
+const ledgerGenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+const ledgerPrecision = 4
+
+func postLedgerEntries(ctx context.Context, tx *sqlx.Tx, ledgerRepo *repository.LedgerRepository, entries []*models.LedgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	debit, credit := ledgerTotals(entries)
+	if !debit.Equal(credit) {
+		return fmt.Errorf("ledger imbalance: debit=%s, credit=%s", debit, credit)
+	}
+	movements, err := holdingMovements(entries)
+	if err != nil {
+		return err
+	}
+	entries = roundLedgerEntries(entries)
+
+	if err := ledgerRepo.LockChain(ctx, tx); err != nil {
+		return fmt.Errorf("failed to lock ledger chain: %w", err)
+	}
+	if err := checkPostingPeriods(ctx, tx, ledgerRepo, entries); err != nil {
+		return err
+	}
+	sequence, prevHash, err := ledgerRepo.GetChainHead(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to get ledger chain head: %w", err)
+	}
+	if sequence == 0 {
+		prevHash = ledgerGenesisHash
+	}
+
+	accounts := make(map[string]uuid.UUID)
+	for _, entry := range entries {
+		account, err := chartAccount(entry)
+		if err != nil {
+			return err
+		}
+		accountID, ok := accounts[account.Code]
+		if !ok {
+			accountID, err = ledgerRepo.EnsureAccount(ctx, tx, account)
+			if err != nil {
+				return fmt.Errorf("failed to resolve ledger account %s: %w", account.Code, err)
+			}
+			accounts[account.Code] = accountID
+		}
+		entry.AccountID = accountID
+
+		sequence++
+		entry.CreatedAt = entry.CreatedAt.Truncate(time.Microsecond)
+		entry.Sequence = sequence
+		entry.PrevHash = prevHash
+		entry.Hash = ledgerEntryHash(entry)
+		prevHash = entry.Hash
+
+		if err := ledgerRepo.Create(ctx, tx, entry); err != nil {
+			return fmt.Errorf("failed to create ledger entry: %w", err)
+		}
+	}
+
+	debit, credit = ledgerTotals(entries)
+	if !debit.Equal(credit) {
+		return fmt.Errorf("ledger imbalance after rounding: debit=%s, credit=%s", debit, credit)
+	}
+
+	for _, movement := range movements {
+		snapshotsFrom, err := holdingSnapshotDate(movement.EffectiveAt)
+		if err != nil {
+			return err
+		}
+		if err := ledgerRepo.AddHoldingMovement(ctx, tx, movement, snapshotsFrom); err != nil {
+			return fmt.Errorf("failed to record holding movement: %w", err)
+		}
+	}
+	return nil
+}
+
+func holdingMovements(entries []*models.LedgerEntry) ([]*models.HoldingMovement, error) {
+	var movements []*models.HoldingMovement
+	for _, entry := range entries {
+		if entry.EntryType != models.LedgerEntryTypeStock {
+			continue
+		}
+		if entry.UserID == nil || entry.Symbol == nil {
+			return nil, fmt.Errorf("stock posting %s has no user or symbol", entry.ID)
+		}
+		if !entry.Quantity.IsPositive() {
+			return nil, fmt.Errorf("stock posting %s has no quantity", entry.ID)
+		}
+
+		quantity := entry.Quantity
+		if entry.Debit.IsPositive() {
+			quantity = quantity.Neg()
+		} else if !entry.Credit.IsPositive() {
+			return nil, fmt.Errorf("stock posting %s has no amount", entry.ID)
+		}
+		effectiveAt := entry.EffectiveAt
+		if effectiveAt.IsZero() {
+			effectiveAt = entry.CreatedAt
+		}
+
+		entryID := entry.ID
+		movements = append(movements, &models.HoldingMovement{
+			ID:          uuid.New(),
+			UserID:      *entry.UserID,
+			Symbol:      *entry.Symbol,
+			Quantity:    quantity,
+			ReferenceID: entry.EventID,
+			EntryID:     &entryID,
+			EffectiveAt: effectiveAt,
+			CreatedAt:   entry.CreatedAt,
+		})
+	}
+	return movements, nil
+}
+
+func holdingSnapshotDate(t time.Time) (time.Time, error) {
+	istLocation, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load IST timezone: %w", err)
+	}
+	t = t.In(istLocation)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, istLocation), nil
+}
+
+func checkPostingPeriods(ctx context.Context, tx *sqlx.Tx, ledgerRepo *repository.LedgerRepository, entries []*models.LedgerEntry) error {
+	checked := make(map[string]bool)
+	for _, entry := range entries {
+		period, err := accountingPeriodOf(entry.CreatedAt)
+		if err != nil {
+			return err
+		}
+		if checked[period] {
+			continue
+		}
+		checked[period] = true
+
+		status, err := ledgerRepo.GetPeriodStatus(ctx, tx, period)
+		if err != nil {
+			return fmt.Errorf("failed to get accounting period status: %w", err)
+		}
+		if status != models.PeriodStatusOpen {
+			return fmt.Errorf("%w: %s is %s, post adjustments to the current period", ErrPeriodClosed, period, status)
+		}
+	}
+	return nil
+}
+
+func ledgerTotals(entries []*models.LedgerEntry) (decimal.Decimal, decimal.Decimal) {
+	totalDebit := decimal.Zero
+	totalCredit := decimal.Zero
+	for _, entry := range entries {
+		totalDebit = totalDebit.Add(entry.Debit)
+		totalCredit = totalCredit.Add(entry.Credit)
+	}
+	return totalDebit, totalCredit
+}
+
+func roundLedgerEntries(entries []*models.LedgerEntry) []*models.LedgerEntry {
+	rounded := make([]*models.LedgerEntry, 0, len(entries)+1)
+	for _, entry := range entries {
+		entry.Debit = entry.Debit.Round(ledgerPrecision)
+		entry.Credit = entry.Credit.Round(ledgerPrecision)
+		if entry.Debit.IsZero() && entry.Credit.IsZero() && entry.Quantity.IsZero() {
+			continue
+		}
+		rounded = append(rounded, entry)
+	}
+
+	debit, credit := ledgerTotals(rounded)
+	residual := debit.Sub(credit)
+	if residual.IsZero() {
+		return rounded
+	}
+
+	first := entries[0]
+	entry := &models.LedgerEntry{
+		ID:        uuid.New(),
+		EventID:   first.EventID,
+		EntryType: models.LedgerEntryTypeRounding,
+		Debit:     decimal.Zero,
+		Credit:    decimal.Zero,
+		CreatedAt: first.CreatedAt,
+	}
+	if residual.IsPositive() {
+		entry.Credit = residual
+	} else {
+		entry.Debit = residual.Neg()
+	}
+	return append(rounded, entry)
+}
+
+func ledgerTransfer(eventID uuid.UUID, debitType, creditType models.LedgerEntryType, symbol *string, amount decimal.Decimal) []*models.LedgerEntry {
+	now := time.Now()
+	return []*models.LedgerEntry{
+		{
+			ID:        uuid.New(),
+			EventID:   eventID,
+			EntryType: debitType,
+			Symbol:    symbol,
+			Debit:     amount,
+			Credit:    decimal.Zero,
+			CreatedAt: now,
+		},
+		{
+			ID:        uuid.New(),
+			EventID:   eventID,
+			EntryType: creditType,
+			Symbol:    symbol,
+			Debit:     decimal.Zero,
+			Credit:    amount,
+			CreatedAt: now,
+		},
+	}
+}
+
+func nonZeroEntries(entries []*models.LedgerEntry) []*models.LedgerEntry {
+	var kept []*models.LedgerEntry
+	for _, entry := range entries {
+		if !entry.Debit.IsZero() || !entry.Credit.IsZero() {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+var feeEntryTypes = map[fees.Component]models.LedgerEntryType{
+	fees.ComponentBrokerage:       models.LedgerEntryTypeBrokerage,
+	fees.ComponentSTT:             models.LedgerEntryTypeSTT,
+	fees.ComponentGST:             models.LedgerEntryTypeGST,
+	fees.ComponentExchangeCharges: models.LedgerEntryTypeExchangeCharges,
+	fees.ComponentSEBICharges:     models.LedgerEntryTypeSEBICharges,
+	fees.ComponentStampDuty:       models.LedgerEntryTypeStampDuty,
+}
+
+func feeEntries(eventID uuid.UUID, breakdown fees.Breakdown, debit bool) []*models.LedgerEntry {
+	now := time.Now()
+	var entries []*models.LedgerEntry
+	for _, item := range breakdown.Items() {
+		if item.Amount.IsZero() {
+			continue
+		}
+		entry := &models.LedgerEntry{
+			ID:        uuid.New(),
+			EventID:   eventID,
+			EntryType: feeEntryTypes[item.Component],
+			Debit:     decimal.Zero,
+			Credit:    decimal.Zero,
+			CreatedAt: now,
+		}
+		if debit {
+			entry.Debit = item.Amount
+		} else {
+			entry.Credit = item.Amount
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func ledgerEntryHash(entry *models.LedgerEntry) string {
+	userID := ""
+	if entry.UserID != nil {
+		userID = entry.UserID.String()
+	}
+	symbol := ""
+	if entry.Symbol != nil {
+		symbol = *entry.Symbol
+	}
+
+	content := strings.Join([]string{
+		fmt.Sprint(entry.Sequence),
+		entry.ID.String(),
+		entry.EventID.String(),
+		userID,
+		entry.AccountID.String(),
+		string(entry.EntryType),
+		symbol,
+		entry.Debit.StringFixed(4),
+		entry.Credit.StringFixed(4),
+		entry.CreatedAt.Format("2006-01-02 15:04:05.000000"),
+		entry.PrevHash,
+	}, "|")
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func withUser(entries []*models.LedgerEntry, userID uuid.UUID) []*models.LedgerEntry {
+	for _, entry := range entries {
+		if isUserAccount(entry.EntryType) {
+			entry.UserID = &userID
+		}
+	}
+	return entries
+}
+
+func withHolding(entries []*models.LedgerEntry, quantity decimal.Decimal, effectiveAt time.Time) []*models.LedgerEntry {
+	for _, entry := range entries {
+		if entry.EntryType == models.LedgerEntryTypeStock {
+			entry.Quantity = quantity
+			entry.EffectiveAt = effectiveAt
+		}
+	}
+	return entries
+}
+
+func isUserAccount(entryType models.LedgerEntryType) bool {
+	switch entryType {
+	case models.LedgerEntryTypeStock, models.LedgerEntryTypeWallet, models.LedgerEntryTypeWithdrawalHold:
+		return true
+	}
+	return false
+}
+
+var systemAccounts = map[models.LedgerEntryType]struct {
+	code        string
+	name        string
+	accountType models.LedgerAccountType
+}{
+	models.LedgerEntryTypeCash:              {"COMPANY_CASH", "Company cash", models.LedgerAccountTypeAsset},
+	models.LedgerEntryTypeFee:               {"FEE_EXPENSE", "Brokerage and statutory fees", models.LedgerAccountTypeExpense},
+	models.LedgerEntryTypeRewardExpense:     {"REWARD_EXPENSE", "Reward expense", models.LedgerAccountTypeExpense},
+	models.LedgerEntryTypeCustody:           {"CLIENT_CUSTODY", "Shares held in custody for users", models.LedgerAccountTypeAsset},
+	models.LedgerEntryTypeSaleCharges:       {"SALE_CHARGES_INCOME", "Charges on share buybacks", models.LedgerAccountTypeIncome},
+	models.LedgerEntryTypeGSTPayable:        {"GST_PAYABLE", "GST collected on sale charges", models.LedgerAccountTypeLiability},
+	models.LedgerEntryTypeChargesPayable:    {"STATUTORY_CHARGES_PAYABLE", "STT, exchange and SEBI charges collected on buybacks", models.LedgerAccountTypeLiability},
+	models.LedgerEntryTypeBuybackGainLoss:   {"BUYBACK_GAIN_LOSS", "Gain or loss on share buybacks", models.LedgerAccountTypeIncome},
+	models.LedgerEntryTypeBrokerage:         {"BROKERAGE_EXPENSE", "Brokerage", models.LedgerAccountTypeExpense},
+	models.LedgerEntryTypeSTT:               {"STT_EXPENSE", "Securities transaction tax", models.LedgerAccountTypeExpense},
+	models.LedgerEntryTypeGST:               {"GST_EXPENSE", "GST on brokerage", models.LedgerAccountTypeExpense},
+	models.LedgerEntryTypeExchangeCharges:   {"EXCHANGE_CHARGES_EXPENSE", "Exchange transaction charges", models.LedgerAccountTypeExpense},
+	models.LedgerEntryTypeSEBICharges:       {"SEBI_CHARGES_EXPENSE", "SEBI turnover fees", models.LedgerAccountTypeExpense},
+	models.LedgerEntryTypeStampDuty:         {"STAMP_DUTY_EXPENSE", "Stamp duty", models.LedgerAccountTypeExpense},
+	models.LedgerEntryTypeRounding:          {"ROUNDING_DIFFERENCE", "Rounding differences", models.LedgerAccountTypeExpense},
+	models.LedgerEntryTypeInventory:         {"COMPANY_INVENTORY", "Company share inventory", models.LedgerAccountTypeAsset},
+	models.LedgerEntryTypeSettlement:        {"SETTLEMENT_CLEARING", "Broker settlement clearing", models.LedgerAccountTypeLiability},
+	models.LedgerEntryTypeExecutionVariance: {"EXECUTION_VARIANCE", "Broker execution variance", models.LedgerAccountTypeExpense},
+	models.LedgerEntryTypeDematOut:          {"DEMAT_TRANSFERS_OUT", "Shares delivered to depository", models.LedgerAccountTypeLiability},
+}
+
+func chartAccount(entry *models.LedgerEntry) (*models.LedgerAccount, error) {
+	account := &models.LedgerAccount{
+		ID:          uuid.New(),
+		AccountType: models.LedgerAccountTypeLiability,
+		CreatedAt:   time.Now(),
+	}
+
+	if system, ok := systemAccounts[entry.EntryType]; ok {
+		account.Code = system.code
+		account.Name = system.name
+		account.AccountType = system.accountType
+		return account, nil
+	}
+
+	if entry.UserID == nil {
+		return nil, fmt.Errorf("ledger entry %s of type %s has no user", entry.ID, entry.EntryType)
+	}
+	account.UserID = entry.UserID
+
+	switch entry.EntryType {
+	case models.LedgerEntryTypeWallet:
+		account.Code = fmt.Sprintf("USER_WALLET:%s", entry.UserID)
+		account.Name = "INR wallet"
+		return account, nil
+	case models.LedgerEntryTypeStock, models.LedgerEntryTypeWithdrawalHold:
+		if entry.Symbol == nil {
+			return nil, fmt.Errorf("ledger entry %s of type %s has no symbol", entry.ID, entry.EntryType)
+		}
+		account.Symbol = entry.Symbol
+		if entry.EntryType == models.LedgerEntryTypeStock {
+			account.Code = fmt.Sprintf("USER_STOCK:%s:%s", entry.UserID, *entry.Symbol)
+			account.Name = fmt.Sprintf("Stock held for user (%s)", *entry.Symbol)
+		} else {
+			account.Code = fmt.Sprintf("USER_WITHDRAWAL_HOLD:%s:%s", entry.UserID, *entry.Symbol)
+			account.Name = fmt.Sprintf("Stock held for demat withdrawal (%s)", *entry.Symbol)
+		}
+		return account, nil
+	}
+	return nil, fmt.Errorf("no ledger account for entry type %s", entry.EntryType)
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/export"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+var defaultExportLedgers = map[string]string{
+	"COMPANY_CASH":              "Cash",
+	"FEE_EXPENSE":               "Brokerage and Charges",
+	"BROKERAGE_EXPENSE":         "Brokerage",
+	"STT_EXPENSE":               "Securities Transaction Tax",
+	"GST_EXPENSE":               "GST on Brokerage",
+	"EXCHANGE_CHARGES_EXPENSE":  "Exchange Charges",
+	"SEBI_CHARGES_EXPENSE":      "SEBI Fees",
+	"STAMP_DUTY_EXPENSE":        "Stamp Duty",
+	"ROUNDING_DIFFERENCE":       "Rounding Differences",
+	"REWARD_EXPENSE":            "Reward Expense",
+	"COMPANY_INVENTORY":         "Stock Inventory",
+	"CLIENT_CUSTODY":            "Client Securities Custody",
+	"SETTLEMENT_CLEARING":       "Broker Settlement",
+	"EXECUTION_VARIANCE":        "Execution Variance",
+	"DEMAT_TRANSFERS_OUT":       "Demat Transfers Out",
+	"SALE_CHARGES_INCOME":       "Buyback Charges",
+	"GST_PAYABLE":               "GST Payable",
+	"STATUTORY_CHARGES_PAYABLE": "Statutory Charges Payable",
+	"BUYBACK_GAIN_LOSS":         "Gain or Loss on Buybacks",
+	"STOCK_UNALLOCATED":         "Unallocated Stock",
+	"USER_STOCK:":               "Client Stock Payable",
+	"USER_WALLET:":              "Client Wallet Payable",
+	"USER_WITHDRAWAL_HOLD:":     "Client Withdrawal Hold",
+}
+
+type ExportPolicy struct {
+	TallyCompany string
+	AccountMap   map[string]string
+	LookbackDays int
+}
+
+type LedgerExportService struct {
+	exportRepo *repository.LedgerExportRepository
+	reportRepo *repository.ReportRepository
+	ledgers    map[string]string
+	policy     ExportPolicy
+}
+
+func NewLedgerExportService(
+	exportRepo *repository.LedgerExportRepository,
+	reportRepo *repository.ReportRepository,
+	policy ExportPolicy,
+) *LedgerExportService {
+	ledgers := make(map[string]string, len(defaultExportLedgers)+len(policy.AccountMap))
+	for prefix, name := range defaultExportLedgers {
+		ledgers[prefix] = name
+	}
+	for prefix, name := range policy.AccountMap {
+		ledgers[prefix] = name
+	}
+
+	return &LedgerExportService{
+		exportRepo: exportRepo,
+		reportRepo: reportRepo,
+		ledgers:    ledgers,
+		policy:     policy,
+	}
+}
+
+type ExportRequest struct {
+	Date   string              `json:"date" binding:"required"`
+	Format models.ExportFormat `json:"format" binding:"required"`
+}
+
+type LedgerExportResponse struct {
+	ID           uuid.UUID           `json:"id"`
+	Date         string              `json:"date"`
+	Format       models.ExportFormat `json:"format"`
+	VoucherCount int                 `json:"voucher_count"`
+	EntryCount   int                 `json:"entry_count"`
+	TotalDebit   decimal.Decimal     `json:"total_debit"`
+	TotalCredit  decimal.Decimal     `json:"total_credit"`
+	Checksum     string              `json:"checksum"`
+	ImportedAt   *time.Time          `json:"imported_at,omitempty"`
+	ImportedBy   *string             `json:"imported_by,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+}
+
+func newLedgerExportResponse(e *models.LedgerExport) *LedgerExportResponse {
+	return &LedgerExportResponse{
+		ID:           e.ID,
+		Date:         e.ExportDate.Format("2006-01-02"),
+		Format:       e.Format,
+		VoucherCount: e.VoucherCount,
+		EntryCount:   e.EntryCount,
+		TotalDebit:   e.TotalDebit,
+		TotalCredit:  e.TotalCredit,
+		Checksum:     e.Checksum,
+		ImportedAt:   e.ImportedAt,
+		ImportedBy:   e.ImportedBy,
+		CreatedAt:    e.CreatedAt,
+	}
+}
+
+func (s *LedgerExportService) Export(ctx context.Context, req ExportRequest) (*LedgerExportResponse, bool, error) {
+	if !req.Format.Valid() {
+		return nil, false, fmt.Errorf("%w: unknown format %q", ErrInvalidExport, req.Format)
+	}
+
+	istLocation, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load IST timezone: %w", err)
+	}
+	date, err := time.ParseInLocation("2006-01-02", req.Date, istLocation)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: date must be YYYY-MM-DD", ErrInvalidExport)
+	}
+	now := time.Now().In(istLocation)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, istLocation)
+	if !date.Before(today) {
+		return nil, false, fmt.Errorf("%w: only completed days can be exported", ErrInvalidExport)
+	}
+
+	existing, err := s.exportRepo.GetByDate(ctx, date, req.Format)
+	if err == nil {
+		return newLedgerExportResponse(existing), false, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, fmt.Errorf("failed to get ledger export: %w", err)
+	}
+
+	ledgerExport, err := s.build(ctx, date, req.Format)
+	if err != nil {
+		return nil, false, err
+	}
+
+	inserted, err := s.exportRepo.Create(ctx, ledgerExport)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create ledger export: %w", err)
+	}
+	if !inserted {
+		existing, err := s.exportRepo.GetByDate(ctx, date, req.Format)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get ledger export: %w", err)
+		}
+		return newLedgerExportResponse(existing), false, nil
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"export_id": ledgerExport.ID,
+		"date":      req.Date,
+		"format":    req.Format,
+		"vouchers":  ledgerExport.VoucherCount,
+	}).Info("Ledger export created")
+
+	return newLedgerExportResponse(ledgerExport), true, nil
+}
+
+func (s *LedgerExportService) List(ctx context.Context, format models.ExportFormat) ([]*LedgerExportResponse, error) {
+	exports, err := s.exportRepo.List(ctx, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledger exports: %w", err)
+	}
+
+	result := make([]*LedgerExportResponse, len(exports))
+	for i := range exports {
+		result[i] = newLedgerExportResponse(&exports[i])
+	}
+	return result, nil
+}
+
+func (s *LedgerExportService) Download(ctx context.Context, id uuid.UUID) (*models.LedgerExport, error) {
+	ledgerExport, err := s.exportRepo.GetByID(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger export: %w", err)
+	}
+	return ledgerExport, nil
+}
+
+func (s *LedgerExportService) MarkImported(ctx context.Context, id uuid.UUID, operator string) (*LedgerExportResponse, error) {
+	if operator == "" {
+		return nil, ErrOperatorRequired
+	}
+
+	ledgerExport, err := s.exportRepo.GetByID(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger export: %w", err)
+	}
+
+	now := time.Now()
+	updated, err := s.exportRepo.MarkImported(ctx, id, operator, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark ledger export imported: %w", err)
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: %s", ErrExportAlreadyImported, ledgerExport.ExportDate.Format("2006-01-02"))
+	}
+
+	ledgerExport.ImportedAt = &now
+	ledgerExport.ImportedBy = &operator
+	return newLedgerExportResponse(ledgerExport), nil
+}
+
+func (s *LedgerExportService) ExportDue(ctx context.Context) error {
+	istLocation, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		return fmt.Errorf("failed to load IST timezone: %w", err)
+	}
+
+	now := time.Now().In(istLocation)
+	for days := s.policy.LookbackDays; days >= 1; days-- {
+		date := now.AddDate(0, 0, -days).Format("2006-01-02")
+		for _, format := range []models.ExportFormat{models.ExportFormatTallyXML, models.ExportFormatJournalCSV} {
+			if _, _, err := s.Export(ctx, ExportRequest{Date: date, Format: format}); err != nil {
+				logrus.WithError(err).WithFields(logrus.Fields{"date": date, "format": format}).Error("Failed to export ledger")
+			}
+		}
+	}
+	return nil
+}
+
+func (s *LedgerExportService) build(ctx context.Context, date time.Time, format models.ExportFormat) (*models.LedgerExport, error) {
+	ledgerExport := &models.LedgerExport{
+		ID:         uuid.New(),
+		ExportDate: date,
+		Format:     format,
+		CreatedAt:  time.Now(),
+	}
+
+	type voucherTotals struct {
+		firstSequence int64
+		net           map[string]decimal.Decimal
+	}
+	byEvent := make(map[uuid.UUID]*voucherTotals)
+
+	err := s.reportRepo.EachGeneralLedgerRow(ctx, date, date.AddDate(0, 0, 1), "", func(row *models.GeneralLedgerRow) error {
+		ledgerExport.EntryCount++
+		ledgerExport.TotalDebit = ledgerExport.TotalDebit.Add(row.Debit)
+		ledgerExport.TotalCredit = ledgerExport.TotalCredit.Add(row.Credit)
+
+		v, ok := byEvent[row.EventID]
+		if !ok {
+			v = &voucherTotals{firstSequence: row.Sequence, net: make(map[string]decimal.Decimal)}
+			byEvent[row.EventID] = v
+		}
+		if row.Sequence < v.firstSequence {
+			v.firstSequence = row.Sequence
+		}
+		ledger := s.ledgerName(row.AccountCode)
+		v.net[ledger] = v.net[ledger].Add(row.Debit).Sub(row.Credit)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger entries: %w", err)
+	}
+
+	eventIDs := make([]uuid.UUID, 0, len(byEvent))
+	for eventID := range byEvent {
+		eventIDs = append(eventIDs, eventID)
+	}
+	sort.Slice(eventIDs, func(i, j int) bool {
+		return byEvent[eventIDs[i]].firstSequence < byEvent[eventIDs[j]].firstSequence
+	})
+
+	var vouchers []export.Voucher
+	for _, eventID := range eventIDs {
+		voucher := export.Voucher{
+			Number:    eventID.String(),
+			Date:      date,
+			Narration: fmt.Sprintf("Ledger event %s", eventID),
+		}
+		for ledger, net := range byEvent[eventID].net {
+			switch {
+			case net.IsPositive():
+				voucher.Lines = append(voucher.Lines, export.Line{Ledger: ledger, Debit: net, Credit: decimal.Zero})
+			case net.IsNegative():
+				voucher.Lines = append(voucher.Lines, export.Line{Ledger: ledger, Debit: decimal.Zero, Credit: net.Neg()})
+			}
+		}
+		if len(voucher.Lines) == 0 {
+			continue
+		}
+		sort.Slice(voucher.Lines, func(i, j int) bool {
+			a, b := voucher.Lines[i], voucher.Lines[j]
+			if a.Debit.IsPositive() != b.Debit.IsPositive() {
+				return a.Debit.IsPositive()
+			}
+			return a.Ledger < b.Ledger
+		})
+		vouchers = append(vouchers, voucher)
+	}
+	ledgerExport.VoucherCount = len(vouchers)
+
+	switch format {
+	case models.ExportFormatTallyXML:
+		ledgerExport.Content, err = export.TallyXML(s.policy.TallyCompany, vouchers)
+	case models.ExportFormatJournalCSV:
+		ledgerExport.Content, err = export.JournalCSV(vouchers)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(ledgerExport.Content)
+	ledgerExport.Checksum = hex.EncodeToString(sum[:])
+	return ledgerExport, nil
+}
+
+func (s *LedgerExportService) ledgerName(accountCode string) string {
+	match := ""
+	for prefix := range s.ledgers {
+		if strings.HasPrefix(accountCode, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+	if match == "" {
+		return accountCode
+	}
+	return s.ledgers[match]
+}
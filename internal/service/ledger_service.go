@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+type LedgerService struct {
+	ledgerRepo *repository.LedgerRepository
+}
+
+func NewLedgerService(ledgerRepo *repository.LedgerRepository) *LedgerService {
+	return &LedgerService{ledgerRepo: ledgerRepo}
+}
+
+type LedgerAccountResponse struct {
+	ID          uuid.UUID                `json:"id"`
+	Code        string                   `json:"code"`
+	Name        string                   `json:"name"`
+	AccountType models.LedgerAccountType `json:"account_type"`
+	UserID      *uuid.UUID               `json:"user_id,omitempty"`
+	Symbol      *string                  `json:"symbol,omitempty"`
+	TotalDebit  decimal.Decimal          `json:"total_debit"`
+	TotalCredit decimal.Decimal          `json:"total_credit"`
+	Balance     decimal.Decimal          `json:"balance"`
+	CreatedAt   time.Time                `json:"created_at"`
+}
+
+func newLedgerAccountResponse(a *models.LedgerAccountBalance) *LedgerAccountResponse {
+	return &LedgerAccountResponse{
+		ID:          a.ID,
+		Code:        a.Code,
+		Name:        a.Name,
+		AccountType: a.AccountType,
+		UserID:      a.UserID,
+		Symbol:      a.Symbol,
+		TotalDebit:  a.TotalDebit,
+		TotalCredit: a.TotalCredit,
+		Balance:     naturalBalance(a.AccountType, a.TotalDebit, a.TotalCredit),
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+func (s *LedgerService) ListAccounts(ctx context.Context, userID *uuid.UUID) ([]*LedgerAccountResponse, error) {
+	accounts, err := s.ledgerRepo.ListAccountBalances(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledger accounts: %w", err)
+	}
+
+	result := make([]*LedgerAccountResponse, len(accounts))
+	for i := range accounts {
+		result[i] = newLedgerAccountResponse(&accounts[i])
+	}
+	return result, nil
+}
+
+const (
+	defaultStatementLimit = 100
+	maxStatementLimit     = 500
+)
+
+type StatementQuery struct {
+	From   string `form:"from"`
+	To     string `form:"to"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+type StatementLine struct {
+	ID          uuid.UUID              `json:"id"`
+	EventID     uuid.UUID              `json:"event_id"`
+	Kind        string                 `json:"kind"`
+	AccountCode string                 `json:"account_code"`
+	EntryType   models.LedgerEntryType `json:"entry_type"`
+	Symbol      *string                `json:"symbol,omitempty"`
+	BalanceKey  *string                `json:"balance_key,omitempty"`
+	Debit       decimal.Decimal        `json:"debit"`
+	Credit      decimal.Decimal        `json:"credit"`
+	Amount      decimal.Decimal        `json:"amount"`
+	Balance     *decimal.Decimal       `json:"balance,omitempty"`
+	Quantity    *decimal.Decimal       `json:"quantity,omitempty"`
+	Shares      *decimal.Decimal       `json:"shares,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+type StatementResponse struct {
+	UserID          uuid.UUID                  `json:"user_id"`
+	From            time.Time                  `json:"from"`
+	To              time.Time                  `json:"to"`
+	OpeningBalances map[string]decimal.Decimal `json:"opening_balances"`
+	OpeningShares   map[string]decimal.Decimal `json:"opening_shares"`
+	Postings        []*StatementLine           `json:"postings"`
+	ClosingBalances map[string]decimal.Decimal `json:"closing_balances"`
+	ClosingShares   map[string]decimal.Decimal `json:"closing_shares"`
+	NextCursor      string                     `json:"next_cursor,omitempty"`
+}
+
+func (s *LedgerService) GetStatement(ctx context.Context, userID uuid.UUID, query StatementQuery) (*StatementResponse, error) {
+	from, to, err := parseDateRange(query.From, query.To, ErrInvalidStatement)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultStatementLimit
+	}
+	if limit < 0 || limit > maxStatementLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidStatement, maxStatementLimit)
+	}
+
+	var afterTime *time.Time
+	var afterID *uuid.UUID
+	if query.Cursor != "" {
+		t, id, err := decodeStatementCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		afterTime, afterID = &t, &id
+	}
+
+	opening, err := s.ledgerRepo.GetUserBalances(ctx, userID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get opening balances: %w", err)
+	}
+	closing, err := s.ledgerRepo.GetUserBalances(ctx, userID, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get closing balances: %w", err)
+	}
+
+	openingShares, err := s.ledgerRepo.GetUserShareBalances(ctx, userID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get opening share balances: %w", err)
+	}
+	closingShares, err := s.ledgerRepo.GetUserShareBalances(ctx, userID, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get closing share balances: %w", err)
+	}
+
+	running, runningShares := opening, openingShares
+	if afterTime != nil {
+		running, err = s.ledgerRepo.GetUserBalancesThrough(ctx, userID, *afterTime, *afterID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get balances at cursor: %w", err)
+		}
+		runningShares, err = s.ledgerRepo.GetUserShareBalancesThrough(ctx, userID, *afterTime, *afterID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get share balances at cursor: %w", err)
+		}
+	}
+	running = copyBalances(running)
+	runningShares = copyBalances(runningShares)
+
+	postings, err := s.ledgerRepo.ListUserPostings(ctx, userID, from, to, afterTime, afterID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list postings: %w", err)
+	}
+
+	response := &StatementResponse{
+		UserID:          userID,
+		From:            from,
+		To:              to,
+		OpeningBalances: opening,
+		OpeningShares:   openingShares,
+		Postings:        make([]*StatementLine, 0, limit),
+		ClosingBalances: closing,
+		ClosingShares:   closingShares,
+	}
+	if len(postings) > limit {
+		postings = postings[:limit]
+		last := postings[limit-1]
+		response.NextCursor = encodeStatementCursor(last.CreatedAt, last.ID)
+	}
+
+	for _, p := range postings {
+		line := &StatementLine{
+			ID:          p.ID,
+			EventID:     p.EventID,
+			Kind:        p.Kind,
+			AccountCode: p.AccountCode,
+			EntryType:   p.EntryType,
+			Symbol:      p.Symbol,
+			BalanceKey:  p.BalanceKey,
+			Debit:       p.Debit,
+			Credit:      p.Credit,
+			Amount:      p.Credit.Sub(p.Debit),
+			CreatedAt:   p.CreatedAt,
+		}
+		if p.BalanceKey != nil {
+			balance := running[*p.BalanceKey].Add(line.Amount)
+			running[*p.BalanceKey] = balance
+			line.Balance = &balance
+		}
+		if p.Quantity != nil && p.Symbol != nil {
+			shares := runningShares[*p.Symbol].Add(*p.Quantity)
+			runningShares[*p.Symbol] = shares
+			line.Quantity = p.Quantity
+			line.Shares = &shares
+		}
+		response.Postings = append(response.Postings, line)
+	}
+
+	return response, nil
+}
+
+func parseDateRange(fromValue, toValue string, invalid error) (time.Time, time.Time, error) {
+	istLocation, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to load IST timezone: %w", err)
+	}
+
+	from := time.Time{}
+	if fromValue != "" {
+		if from, err = parseRangeTime(fromValue, istLocation, false, invalid); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	to := time.Now().In(istLocation)
+	if toValue != "" {
+		if to, err = parseRangeTime(toValue, istLocation, true, invalid); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: to must be after from", invalid)
+	}
+	return from, to, nil
+}
+
+func parseRangeTime(value string, loc *time.Location, endOfDay bool, invalid error) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		if endOfDay {
+			return t.AddDate(0, 0, 1), nil
+		}
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q is neither a date nor an RFC3339 timestamp", invalid, value)
+	}
+	return t.In(loc), nil
+}
+
+func encodeStatementCursor(t time.Time, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(t.Format(time.RFC3339Nano) + "," + id.String()))
+}
+
+func decodeStatementCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("%w: malformed cursor", ErrInvalidStatement)
+	}
+	parts := strings.SplitN(string(raw), ",", 2)
+	if len(parts) != 2 {
+		return time.Time{}, uuid.Nil, fmt.Errorf("%w: malformed cursor", ErrInvalidStatement)
+	}
+	t, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("%w: malformed cursor", ErrInvalidStatement)
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("%w: malformed cursor", ErrInvalidStatement)
+	}
+	return t, id, nil
+}
+
+func copyBalances(balances map[string]decimal.Decimal) map[string]decimal.Decimal {
+	result := make(map[string]decimal.Decimal, len(balances))
+	for k, v := range balances {
+		result[k] = v
+	}
+	return result
+}
+
+const chainVerifyBatchSize = 1000
+
+type ChainBreak struct {
+	Sequence int64     `json:"sequence"`
+	EntryID  uuid.UUID `json:"entry_id"`
+	Reason   string    `json:"reason"`
+}
+
+type ChainVerification struct {
+	Valid          bool        `json:"valid"`
+	EntriesChecked int64       `json:"entries_checked"`
+	HeadSequence   int64       `json:"head_sequence"`
+	HeadHash       string      `json:"head_hash"`
+	BrokenAt       *ChainBreak `json:"broken_at,omitempty"`
+	VerifiedAt     time.Time   `json:"verified_at"`
+}
+
+func (s *LedgerService) VerifyChain(ctx context.Context) (*ChainVerification, error) {
+	result := &ChainVerification{Valid: true, HeadHash: ledgerGenesisHash}
+
+	for {
+		entries, err := s.ledgerRepo.ListChain(ctx, result.HeadSequence, chainVerifyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ledger chain: %w", err)
+		}
+
+		for i := range entries {
+			entry := &entries[i]
+			if reason := chainLinkError(entry, result.HeadSequence, result.HeadHash); reason != "" {
+				result.Valid = false
+				result.BrokenAt = &ChainBreak{Sequence: entry.Sequence, EntryID: entry.ID, Reason: reason}
+				result.VerifiedAt = time.Now()
+				return result, nil
+			}
+			result.EntriesChecked++
+			result.HeadSequence = entry.Sequence
+			result.HeadHash = entry.Hash
+		}
+
+		if len(entries) < chainVerifyBatchSize {
+			break
+		}
+	}
+
+	result.VerifiedAt = time.Now()
+	return result, nil
+}
+
+func chainLinkError(entry *models.LedgerEntry, prevSequence int64, prevHash string) string {
+	if entry.Sequence != prevSequence+1 {
+		return fmt.Sprintf("expected sequence %d, found %d", prevSequence+1, entry.Sequence)
+	}
+	if entry.PrevHash != prevHash {
+		return "previous hash does not match the preceding entry"
+	}
+	if ledgerEntryHash(entry) != entry.Hash {
+		return "entry contents do not match its hash"
+	}
+	return ""
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"stocky/internal/models"
+)
+
+func TestLedgerEntryHash(t *testing.T) {
+	userID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+	symbol := "RELIANCE"
+	genesis := "0000000000000000000000000000000000000000000000000000000000000000"
+
+	tests := []struct {
+		name    string
+		entry   models.LedgerEntry
+		content string
+	}{
+		{
+			name: "user stock credit",
+			entry: models.LedgerEntry{
+				Sequence:  1,
+				ID:        uuid.MustParse("dd0e8400-e29b-41d4-a716-446655440000"),
+				EventID:   uuid.MustParse("660e8400-e29b-41d4-a716-446655440000"),
+				UserID:    &userID,
+				AccountID: uuid.MustParse("aa0e8400-e29b-41d4-a716-446655440000"),
+				EntryType: models.LedgerEntryTypeStock,
+				Symbol:    &symbol,
+				Debit:     decimal.Zero,
+				Credit:    decimal.RequireFromString("2490.1"),
+				CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 123456000, time.UTC),
+				PrevHash:  genesis,
+			},
+			content: "1|dd0e8400-e29b-41d4-a716-446655440000|660e8400-e29b-41d4-a716-446655440000|" +
+				"550e8400-e29b-41d4-a716-446655440000|aa0e8400-e29b-41d4-a716-446655440000|STOCK|RELIANCE|" +
+				"0.0000|2490.1000|2024-01-15 10:30:00.123456|" + genesis,
+		},
+		{
+			name: "company entry without user or symbol",
+			entry: models.LedgerEntry{
+				Sequence:  42,
+				ID:        uuid.MustParse("dd0e8401-e29b-41d4-a716-446655440000"),
+				EventID:   uuid.MustParse("660e8400-e29b-41d4-a716-446655440000"),
+				AccountID: uuid.MustParse("aa0e8401-e29b-41d4-a716-446655440000"),
+				EntryType: models.LedgerEntryTypeFee,
+				Debit:     decimal.NewFromInt(20),
+				Credit:    decimal.Zero,
+				CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+				PrevHash:  "9f2c",
+			},
+			content: "42|dd0e8401-e29b-41d4-a716-446655440000|660e8400-e29b-41d4-a716-446655440000||" +
+				"aa0e8401-e29b-41d4-a716-446655440000|FEE||20.0000|0.0000|2024-01-15 10:30:00.000000|9f2c",
+		},
+		{
+			name: "amount beyond ledger precision",
+			entry: models.LedgerEntry{
+				Sequence:  7,
+				ID:        uuid.MustParse("dd0e8402-e29b-41d4-a716-446655440000"),
+				EventID:   uuid.MustParse("660e8400-e29b-41d4-a716-446655440000"),
+				AccountID: uuid.MustParse("aa0e8402-e29b-41d4-a716-446655440000"),
+				EntryType: models.LedgerEntryTypeSettlement,
+				Debit:     decimal.Zero,
+				Credit:    decimal.RequireFromString("0.00005"),
+				CreatedAt: time.Date(2024, 12, 31, 23, 59, 59, 999999000, time.UTC),
+				PrevHash:  "ab",
+			},
+			content: "7|dd0e8402-e29b-41d4-a716-446655440000|660e8400-e29b-41d4-a716-446655440000||" +
+				"aa0e8402-e29b-41d4-a716-446655440000|SETTLEMENT||0.0000|0.0001|2024-12-31 23:59:59.999999|ab",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum := sha256.Sum256([]byte(tt.content))
+			want := hex.EncodeToString(sum[:])
+			if got := ledgerEntryHash(&tt.entry); got != want {
+				t.Fatalf("ledgerEntryHash() = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestChainLinkError(t *testing.T) {
+	valid := func() *models.LedgerEntry {
+		entry := &models.LedgerEntry{
+			Sequence:  5,
+			ID:        uuid.MustParse("dd0e8400-e29b-41d4-a716-446655440000"),
+			EventID:   uuid.MustParse("660e8400-e29b-41d4-a716-446655440000"),
+			AccountID: uuid.MustParse("aa0e8400-e29b-41d4-a716-446655440000"),
+			EntryType: models.LedgerEntryTypeCash,
+			Debit:     decimal.NewFromInt(100),
+			Credit:    decimal.Zero,
+			CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			PrevHash:  "prev",
+		}
+		entry.Hash = ledgerEntryHash(entry)
+		return entry
+	}
+
+	tests := []struct {
+		name         string
+		modify       func(*models.LedgerEntry)
+		prevSequence int64
+		prevHash     string
+		want         string
+	}{
+		{name: "valid link", modify: func(*models.LedgerEntry) {}, prevSequence: 4, prevHash: "prev"},
+		{name: "sequence gap", modify: func(*models.LedgerEntry) {}, prevSequence: 3, prevHash: "prev", want: "expected sequence 4, found 5"},
+		{name: "repeated sequence", modify: func(*models.LedgerEntry) {}, prevSequence: 5, prevHash: "prev", want: "expected sequence 6, found 5"},
+		{name: "previous hash mismatch", modify: func(*models.LedgerEntry) {}, prevSequence: 4, prevHash: "other", want: "previous hash does not match the preceding entry"},
+		{name: "amount changed", modify: func(e *models.LedgerEntry) { e.Debit = decimal.NewFromInt(101) }, prevSequence: 4, prevHash: "prev", want: "entry contents do not match its hash"},
+		{name: "timestamp changed", modify: func(e *models.LedgerEntry) { e.CreatedAt = e.CreatedAt.Add(time.Microsecond) }, prevSequence: 4, prevHash: "prev", want: "entry contents do not match its hash"},
+		{name: "hash replaced", modify: func(e *models.LedgerEntry) { e.Hash = "deadbeef" }, prevSequence: 4, prevHash: "prev", want: "entry contents do not match its hash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := valid()
+			tt.modify(entry)
+			if got := chainLinkError(entry, tt.prevSequence, tt.prevHash); got != tt.want {
+				t.Fatalf("chainLinkError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundLedgerEntries(t *testing.T) {
+	eventID := uuid.MustParse("660e8400-e29b-41d4-a716-446655440000")
+	createdAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	entry := func(entryType models.LedgerEntryType, debit, credit, quantity string) *models.LedgerEntry {
+		return &models.LedgerEntry{
+			ID:        uuid.New(),
+			EventID:   eventID,
+			EntryType: entryType,
+			Debit:     decimal.RequireFromString(debit),
+			Credit:    decimal.RequireFromString(credit),
+			CreatedAt: createdAt,
+			Quantity:  decimal.RequireFromString(quantity),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		entries     []*models.LedgerEntry
+		wantEntries int
+		wantDebit   string
+		wantCredit  string
+	}{
+		{
+			name: "balanced",
+			entries: []*models.LedgerEntry{
+				entry(models.LedgerEntryTypeCash, "2490.1", "0", "0"),
+				entry(models.LedgerEntryTypeStock, "0", "2490.1", "1"),
+			},
+			wantEntries: 2,
+		},
+		{
+			name: "debits round up",
+			entries: []*models.LedgerEntry{
+				entry(models.LedgerEntryTypeCash, "10.00005", "0", "0"),
+				entry(models.LedgerEntryTypeStock, "0", "10.00004", "0.1"),
+			},
+			wantEntries: 3,
+			wantDebit:   "0",
+			wantCredit:  "0.0001",
+		},
+		{
+			name: "credits round up",
+			entries: []*models.LedgerEntry{
+				entry(models.LedgerEntryTypeStock, "3.33333", "0", "0.1"),
+				entry(models.LedgerEntryTypeStock, "3.33333", "0", "0.1"),
+				entry(models.LedgerEntryTypeStock, "3.33333", "0", "0.1"),
+				entry(models.LedgerEntryTypeCash, "0", "10", "0"),
+			},
+			wantEntries: 5,
+			wantDebit:   "0.0001",
+			wantCredit:  "0",
+		},
+		{
+			name: "zero entry dropped",
+			entries: []*models.LedgerEntry{
+				entry(models.LedgerEntryTypeCash, "5", "0", "0"),
+				entry(models.LedgerEntryTypeGST, "0", "0.00004", "0"),
+				entry(models.LedgerEntryTypeWallet, "0", "5", "0"),
+			},
+			wantEntries: 2,
+		},
+		{
+			name: "zero stock entry with quantity kept",
+			entries: []*models.LedgerEntry{
+				entry(models.LedgerEntryTypeStock, "0", "0.00001", "0.000001"),
+				entry(models.LedgerEntryTypeCash, "5", "0", "0"),
+				entry(models.LedgerEntryTypeWallet, "0", "5", "0"),
+			},
+			wantEntries: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := roundLedgerEntries(tt.entries)
+			if len(got) != tt.wantEntries {
+				t.Fatalf("roundLedgerEntries() returned %d entries, want %d", len(got), tt.wantEntries)
+			}
+
+			debit, credit := ledgerTotals(got)
+			if !debit.Equal(credit) {
+				t.Fatalf("rounded entries are unbalanced: debit %s, credit %s", debit, credit)
+			}
+			for _, e := range got {
+				if !e.Debit.Equal(e.Debit.Round(ledgerPrecision)) || !e.Credit.Equal(e.Credit.Round(ledgerPrecision)) {
+					t.Fatalf("%s entry not rounded: debit %s, credit %s", e.EntryType, e.Debit, e.Credit)
+				}
+			}
+
+			last := got[len(got)-1]
+			if tt.wantDebit == "" {
+				if last.EntryType == models.LedgerEntryTypeRounding {
+					t.Fatalf("unexpected ROUNDING entry: debit %s, credit %s", last.Debit, last.Credit)
+				}
+				return
+			}
+			if last.EntryType != models.LedgerEntryTypeRounding {
+				t.Fatalf("last entry type = %s, want %s", last.EntryType, models.LedgerEntryTypeRounding)
+			}
+			if !last.Debit.Equal(decimal.RequireFromString(tt.wantDebit)) || !last.Credit.Equal(decimal.RequireFromString(tt.wantCredit)) {
+				t.Fatalf("ROUNDING debit %s, credit %s, want debit %s, credit %s", last.Debit, last.Credit, tt.wantDebit, tt.wantCredit)
+			}
+			if last.EventID != eventID || !last.CreatedAt.Equal(createdAt) {
+				t.Fatalf("ROUNDING entry event %s at %s, want event %s at %s", last.EventID, last.CreatedAt, eventID, createdAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,473 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/broker"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+	"stocky/pkg/fees"
+)
+
+type OrderService struct {
+	orderRepo         *repository.OrderRepository
+	rewardRepo        *repository.RewardRepository
+	ledgerRepo        *repository.LedgerRepository
+	settlementService *SettlementService
+	inventoryService  *InventoryService
+	broker            broker.Broker
+	db                *sqlx.DB
+}
+
+func NewOrderService(
+	orderRepo *repository.OrderRepository,
+	rewardRepo *repository.RewardRepository,
+	ledgerRepo *repository.LedgerRepository,
+	settlementService *SettlementService,
+	inventoryService *InventoryService,
+	broker broker.Broker,
+	db *sqlx.DB,
+) *OrderService {
+	return &OrderService{
+		orderRepo:         orderRepo,
+		rewardRepo:        rewardRepo,
+		ledgerRepo:        ledgerRepo,
+		settlementService: settlementService,
+		inventoryService:  inventoryService,
+		broker:            broker,
+		db:                db,
+	}
+}
+
+type BrokerOrderResponse struct {
+	ID             uuid.UUID                 `json:"id"`
+	Symbol         string                    `json:"symbol"`
+	Side           string                    `json:"side"`
+	Purpose        models.BrokerOrderPurpose `json:"purpose"`
+	Quantity       int64                     `json:"quantity"`
+	RewardQuantity decimal.Decimal           `json:"reward_quantity"`
+	BookedValue    decimal.Decimal           `json:"booked_value"`
+	Status         models.BrokerOrderStatus  `json:"status"`
+	BrokerOrderID  *string                   `json:"broker_order_id,omitempty"`
+	FailureReason  *string                   `json:"failure_reason,omitempty"`
+	SubmittedAt    *time.Time                `json:"submitted_at,omitempty"`
+	FilledAt       *time.Time                `json:"filled_at,omitempty"`
+	CreatedAt      time.Time                 `json:"created_at"`
+}
+
+func (s *OrderService) ListOrders(ctx context.Context, status models.BrokerOrderStatus) ([]BrokerOrderResponse, error) {
+	orders, err := s.orderRepo.ListByStatus(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list broker orders: %w", err)
+	}
+
+	result := make([]BrokerOrderResponse, len(orders))
+	for i, o := range orders {
+		result[i] = BrokerOrderResponse{
+			ID:             o.ID,
+			Symbol:         o.Symbol,
+			Side:           o.Side,
+			Purpose:        o.Purpose,
+			Quantity:       o.Quantity,
+			RewardQuantity: o.RewardQuantity,
+			BookedValue:    o.BookedValue,
+			Status:         o.Status,
+			BrokerOrderID:  o.BrokerOrderID,
+			FailureReason:  o.FailureReason,
+			SubmittedAt:    o.SubmittedAt,
+			FilledAt:       o.FilledAt,
+			CreatedAt:      o.CreatedAt,
+		}
+	}
+	return result, nil
+}
+
+func (s *OrderService) RunOrderCycle(ctx context.Context) error {
+	if err := s.GenerateOrders(ctx); err != nil {
+		return err
+	}
+	if err := s.SubmitPending(ctx); err != nil {
+		return err
+	}
+	return s.CollectFills(ctx)
+}
+
+func (s *OrderService) GenerateOrders(ctx context.Context) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rewards, err := s.rewardRepo.ListBookedForUpdate(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to list booked rewards: %w", err)
+	}
+
+	var lots []rewardLot
+	for i := range rewards {
+		reward := &rewards[i]
+		if reward.BookingPrice == nil {
+			continue
+		}
+
+		reversed, err := s.rewardRepo.GetReversedQuantity(ctx, tx, reward.EventID)
+		if err != nil {
+			return fmt.Errorf("failed to get reversed quantity: %w", err)
+		}
+		lots = append(lots, rewardLot{reward: reward, net: reward.Quantity.Sub(reversed)})
+	}
+
+	plans, closed := planOrders(lots)
+	for _, reward := range closed {
+		if err := s.settlementService.applyTransition(ctx, tx, reward, s.settlementService.markReversed); err != nil {
+			return err
+		}
+	}
+
+	for _, plan := range plans {
+		if err := s.orderRepo.Create(ctx, tx, plan.order); err != nil {
+			return fmt.Errorf("failed to create broker order: %w", err)
+		}
+		for _, reward := range plan.rewards {
+			if err := s.orderRepo.LinkReward(ctx, tx, plan.order.ID, reward.EventID); err != nil {
+				return fmt.Errorf("failed to link reward to order: %w", err)
+			}
+			if err := s.settlementService.applyTransition(ctx, tx, reward, s.settlementService.markOrdered); err != nil {
+				return err
+			}
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"order_id":        plan.order.ID,
+			"symbol":          plan.order.Symbol,
+			"quantity":        plan.order.Quantity,
+			"reward_quantity": plan.order.RewardQuantity,
+			"rewards":         len(plan.rewards),
+		}).Info("Broker order generated")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *OrderService) SubmitPending(ctx context.Context) error {
+	orders, err := s.orderRepo.ListByStatus(ctx, models.BrokerOrderStatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to list pending orders: %w", err)
+	}
+
+	for _, order := range orders {
+		brokerOrderID, err := s.broker.PlaceOrder(ctx, broker.Order{
+			ClientOrderID: order.ID,
+			Symbol:        order.Symbol,
+			Side:          broker.Side(order.Side),
+			Quantity:      order.Quantity,
+		})
+		if err != nil {
+			logrus.WithError(err).WithField("order_id", order.ID).Error("Broker rejected order")
+			if err := s.failOrder(ctx, order.ID, err.Error()); err != nil {
+				logrus.WithError(err).WithField("order_id", order.ID).Error("Failed to record order failure")
+			}
+			continue
+		}
+
+		if err := s.markSubmitted(ctx, order.ID, brokerOrderID); err != nil {
+			logrus.WithError(err).WithField("order_id", order.ID).Error("Failed to record order submission")
+		}
+	}
+	return nil
+}
+
+func (s *OrderService) CollectFills(ctx context.Context) error {
+	orders, err := s.orderRepo.ListByStatus(ctx, models.BrokerOrderStatusSubmitted)
+	if err != nil {
+		return fmt.Errorf("failed to list submitted orders: %w", err)
+	}
+
+	for _, order := range orders {
+		fills, err := s.broker.GetFills(ctx, *order.BrokerOrderID)
+		if err != nil {
+			logrus.WithError(err).WithField("order_id", order.ID).Error("Failed to fetch fills")
+			continue
+		}
+
+		if err := s.recordFills(ctx, order.ID, fills); err != nil {
+			logrus.WithError(err).WithField("order_id", order.ID).Error("Failed to record fills")
+		}
+	}
+	return nil
+}
+
+func (s *OrderService) markSubmitted(ctx context.Context, orderID uuid.UUID, brokerOrderID string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	order, err := s.orderRepo.GetByIDForUpdate(ctx, tx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get broker order: %w", err)
+	}
+
+	now := time.Now()
+	order.Status = models.BrokerOrderStatusSubmitted
+	order.BrokerOrderID = &brokerOrderID
+	order.SubmittedAt = &now
+	if err := s.orderRepo.Update(ctx, tx, order); err != nil {
+		return fmt.Errorf("failed to update broker order: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *OrderService) failOrder(ctx context.Context, orderID uuid.UUID, reason string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	order, err := s.orderRepo.GetByIDForUpdate(ctx, tx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get broker order: %w", err)
+	}
+
+	order.Status = models.BrokerOrderStatusFailed
+	order.FailureReason = &reason
+	if err := s.orderRepo.Update(ctx, tx, order); err != nil {
+		return fmt.Errorf("failed to update broker order: %w", err)
+	}
+
+	eventIDs, err := s.orderRepo.GetLinkedEventIDs(ctx, tx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order rewards: %w", err)
+	}
+	for _, eventID := range eventIDs {
+		reward, err := s.rewardRepo.GetByEventIDForUpdate(ctx, tx, eventID)
+		if err != nil {
+			return fmt.Errorf("failed to get reward: %w", err)
+		}
+		if err := s.settlementService.applyTransition(ctx, tx, reward, s.settlementService.markFailed("broker order failed: "+reason)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *OrderService) recordFills(ctx context.Context, orderID uuid.UUID, fills []broker.Fill) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	order, err := s.orderRepo.GetByIDForUpdate(ctx, tx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get broker order: %w", err)
+	}
+	if order.Status != models.BrokerOrderStatusSubmitted {
+		return nil
+	}
+
+	for _, f := range fills {
+		fill := &models.BrokerFill{
+			ID:              uuid.New(),
+			OrderID:         order.ID,
+			BrokerFillID:    f.FillID,
+			Quantity:        f.Quantity,
+			Price:           f.Price,
+			Fees:            f.Fees.Total(),
+			Brokerage:       f.Fees.Brokerage,
+			STT:             f.Fees.STT,
+			GST:             f.Fees.GST,
+			ExchangeCharges: f.Fees.ExchangeCharges,
+			SEBICharges:     f.Fees.SEBICharges,
+			StampDuty:       f.Fees.StampDuty,
+			ExecutedAt:      f.ExecutedAt,
+			CreatedAt:       time.Now(),
+		}
+		if _, err := s.orderRepo.CreateFill(ctx, tx, fill); err != nil {
+			return fmt.Errorf("failed to record fill: %w", err)
+		}
+	}
+
+	recorded, err := s.orderRepo.GetFills(ctx, tx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get fills: %w", err)
+	}
+
+	filledQty := decimal.Zero
+	cost := decimal.Zero
+	var charged fees.Breakdown
+	for i := range recorded {
+		f := &recorded[i]
+		filledQty = filledQty.Add(f.Quantity)
+		cost = cost.Add(f.Price.Mul(f.Quantity))
+		charged = charged.Add(f.FeeBreakdown())
+	}
+
+	if filledQty.LessThan(decimal.NewFromInt(order.Quantity)) {
+		return tx.Commit()
+	}
+
+	booked, err := s.orderRepo.GetBookedFees(ctx, tx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get booked fees: %w", err)
+	}
+
+	avgPrice := cost.Div(filledQty)
+	entries := fillReconciliationEntries(order, avgPrice)
+	entries = append(entries, feeVarianceEntries(order, charged, booked)...)
+	if len(entries) > 0 {
+		if err := postLedgerEntries(ctx, tx, s.ledgerRepo, entries); err != nil {
+			return err
+		}
+	}
+
+	residue := decimal.NewFromInt(order.Quantity).Sub(order.RewardQuantity)
+	if residue.IsPositive() {
+		if err := s.inventoryService.addToPool(ctx, tx, order.Symbol, residue, avgPrice.Mul(residue), models.LedgerEntryTypeCash, models.InventoryMovementPurchase, order.ID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	order.Status = models.BrokerOrderStatusFilled
+	order.FilledAt = &now
+	if err := s.orderRepo.Update(ctx, tx, order); err != nil {
+		return fmt.Errorf("failed to update broker order: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"order_id":      order.ID,
+		"symbol":        order.Symbol,
+		"quantity":      order.Quantity,
+		"average_price": avgPrice.Round(4),
+		"booked_value":  order.BookedValue,
+		"fees":          charged.Total(),
+	}).Info("Broker order filled")
+
+	return nil
+}
+
+type rewardLot struct {
+	reward *models.RewardEvent
+	net    decimal.Decimal
+}
+
+type orderPlan struct {
+	order   *models.BrokerOrder
+	rewards []*models.RewardEvent
+}
+
+func planOrders(lots []rewardLot) ([]*orderPlan, []*models.RewardEvent) {
+	var plans []*orderPlan
+	var closed []*models.RewardEvent
+	bySymbol := make(map[string]*orderPlan)
+	for _, lot := range lots {
+		if !lot.net.IsPositive() {
+			closed = append(closed, lot.reward)
+			continue
+		}
+
+		plan, ok := bySymbol[lot.reward.StockSymbol]
+		if !ok {
+			plan = &orderPlan{order: &models.BrokerOrder{
+				ID:             uuid.New(),
+				Symbol:         lot.reward.StockSymbol,
+				Side:           string(broker.SideBuy),
+				Purpose:        models.BrokerOrderPurposeRewards,
+				RewardQuantity: decimal.Zero,
+				BookedValue:    decimal.Zero,
+				Status:         models.BrokerOrderStatusPending,
+				CreatedAt:      time.Now(),
+			}}
+			bySymbol[lot.reward.StockSymbol] = plan
+			plans = append(plans, plan)
+		}
+		plan.order.RewardQuantity = plan.order.RewardQuantity.Add(lot.net)
+		plan.order.BookedValue = plan.order.BookedValue.Add(lot.reward.BookingPrice.Mul(lot.net))
+		plan.rewards = append(plan.rewards, lot.reward)
+	}
+
+	for _, plan := range plans {
+		plan.order.Quantity = plan.order.RewardQuantity.Ceil().IntPart()
+	}
+	return plans, closed
+}
+
+func fillReconciliationEntries(order *models.BrokerOrder, avgPrice decimal.Decimal) []*models.LedgerEntry {
+	var entries []*models.LedgerEntry
+
+	variance := avgPrice.Mul(order.RewardQuantity).Sub(order.BookedValue).Round(4)
+	switch {
+	case variance.IsPositive():
+		entries = append(entries, ledgerTransfer(order.ID, models.LedgerEntryTypeExecutionVariance, models.LedgerEntryTypeCash, &order.Symbol, variance)...)
+	case variance.IsNegative():
+		entries = append(entries, ledgerTransfer(order.ID, models.LedgerEntryTypeCash, models.LedgerEntryTypeExecutionVariance, &order.Symbol, variance.Neg())...)
+	}
+	return entries
+}
+
+func feeVarianceEntries(order *models.BrokerOrder, charged fees.Breakdown, booked map[models.LedgerEntryType]decimal.Decimal) []*models.LedgerEntry {
+	now := time.Now()
+	var entries []*models.LedgerEntry
+	total := decimal.Zero
+	for _, item := range charged.Items() {
+		entryType := feeEntryTypes[item.Component]
+		variance := item.Amount.Sub(booked[entryType])
+		if variance.IsZero() {
+			continue
+		}
+		entry := &models.LedgerEntry{
+			ID:        uuid.New(),
+			EventID:   order.ID,
+			EntryType: entryType,
+			Symbol:    &order.Symbol,
+			Debit:     decimal.Zero,
+			Credit:    decimal.Zero,
+			CreatedAt: now,
+		}
+		if variance.IsPositive() {
+			entry.Debit = variance
+		} else {
+			entry.Credit = variance.Neg()
+		}
+		entries = append(entries, entry)
+		total = total.Add(variance)
+	}
+
+	if total.IsZero() {
+		return entries
+	}
+	cash := &models.LedgerEntry{
+		ID:        uuid.New(),
+		EventID:   order.ID,
+		EntryType: models.LedgerEntryTypeCash,
+		Symbol:    &order.Symbol,
+		Debit:     decimal.Zero,
+		Credit:    decimal.Zero,
+		CreatedAt: now,
+	}
+	if total.IsPositive() {
+		cash.Credit = total
+	} else {
+		cash.Debit = total.Neg()
+	}
+	return append(entries, cash)
+}
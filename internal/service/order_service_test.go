@@ -0,0 +1,102 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"stocky/internal/models"
+)
+
+func TestPlanOrders(t *testing.T) {
+	lot := func(symbol, price, quantity, reversed string) rewardLot {
+		p := decimal.RequireFromString(price)
+		q := decimal.RequireFromString(quantity)
+		return rewardLot{
+			reward: &models.RewardEvent{
+				EventID:      uuid.New(),
+				StockSymbol:  symbol,
+				Quantity:     q,
+				BookingPrice: &p,
+			},
+			net: q.Sub(decimal.RequireFromString(reversed)),
+		}
+	}
+
+	type order struct {
+		symbol         string
+		quantity       int64
+		rewardQuantity string
+		bookedValue    string
+		rewards        int
+	}
+
+	tests := []struct {
+		name   string
+		lots   []rewardLot
+		orders []order
+		closed int
+	}{
+		{
+			name: "nets rewards per symbol and rounds up",
+			lots: []rewardLot{
+				lot("RELIANCE", "2500", "0.5", "0"),
+				lot("TCS", "3500", "1", "0"),
+				lot("RELIANCE", "2500", "1.25", "0.25"),
+			},
+			orders: []order{
+				{symbol: "RELIANCE", quantity: 2, rewardQuantity: "1.5", bookedValue: "3750", rewards: 2},
+				{symbol: "TCS", quantity: 1, rewardQuantity: "1", bookedValue: "3500", rewards: 1},
+			},
+		},
+		{
+			name: "fully reversed rewards are closed, not ordered",
+			lots: []rewardLot{
+				lot("RELIANCE", "2500", "1", "1"),
+				lot("RELIANCE", "2500", "0.4", "0"),
+			},
+			orders: []order{
+				{symbol: "RELIANCE", quantity: 1, rewardQuantity: "0.4", bookedValue: "1000", rewards: 1},
+			},
+			closed: 1,
+		},
+		{
+			name: "a symbol with nothing left to buy gets no order",
+			lots: []rewardLot{
+				lot("INFY", "1500", "2", "2"),
+				lot("INFY", "1500", "0.5", "0.5"),
+			},
+			closed: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plans, closed := planOrders(tt.lots)
+			if len(closed) != tt.closed {
+				t.Errorf("closed = %d, want %d", len(closed), tt.closed)
+			}
+			if len(plans) != len(tt.orders) {
+				t.Fatalf("orders = %d, want %d", len(plans), len(tt.orders))
+			}
+			for i, want := range tt.orders {
+				got := plans[i]
+				if got.order.Symbol != want.symbol {
+					t.Errorf("order %d symbol = %s, want %s", i, got.order.Symbol, want.symbol)
+				}
+				if got.order.Quantity != want.quantity {
+					t.Errorf("order %d quantity = %d, want %d", i, got.order.Quantity, want.quantity)
+				}
+				if !got.order.RewardQuantity.Equal(decimal.RequireFromString(want.rewardQuantity)) {
+					t.Errorf("order %d reward quantity = %s, want %s", i, got.order.RewardQuantity, want.rewardQuantity)
+				}
+				if !got.order.BookedValue.Equal(decimal.RequireFromString(want.bookedValue)) {
+					t.Errorf("order %d booked value = %s, want %s", i, got.order.BookedValue, want.bookedValue)
+				}
+				if len(got.rewards) != want.rewards {
+					t.Errorf("order %d rewards = %d, want %d", i, len(got.rewards), want.rewards)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+type RewardEventPayload struct {
+	EventID      uuid.UUID           `json:"event_id"`
+	UserID       uuid.UUID           `json:"user_id"`
+	StockSymbol  string              `json:"stock_symbol"`
+	Quantity     decimal.Decimal     `json:"quantity"`
+	BookingPrice *decimal.Decimal    `json:"booking_price,omitempty"`
+	Status       models.RewardStatus `json:"status"`
+	FromStatus   models.RewardStatus `json:"from_status,omitempty"`
+	Timestamp    time.Time           `json:"timestamp"`
+}
+
+type RewardReversedPayload struct {
+	EventID     uuid.UUID       `json:"event_id"`
+	UserID      uuid.UUID       `json:"user_id"`
+	StockSymbol string          `json:"stock_symbol"`
+	ReversalID  uuid.UUID       `json:"reversal_id"`
+	Quantity    decimal.Decimal `json:"quantity"`
+	Value       decimal.Decimal `json:"value"`
+	Reason      string          `json:"reason,omitempty"`
+}
+
+type SharesTransferredPayload struct {
+	TransferID  uuid.UUID       `json:"transfer_id"`
+	FromUserID  uuid.UUID       `json:"from_user_id"`
+	ToUserID    uuid.UUID       `json:"to_user_id"`
+	StockSymbol string          `json:"stock_symbol"`
+	Quantity    decimal.Decimal `json:"quantity"`
+	Value       decimal.Decimal `json:"value"`
+}
+
+type SharesSoldPayload struct {
+	SaleID      uuid.UUID       `json:"sale_id"`
+	UserID      uuid.UUID       `json:"user_id"`
+	StockSymbol string          `json:"stock_symbol"`
+	Quantity    decimal.Decimal `json:"quantity"`
+	Price       decimal.Decimal `json:"price"`
+	Fees        decimal.Decimal `json:"fees"`
+	NetAmount   decimal.Decimal `json:"net_amount"`
+}
+
+type WithdrawalStatusPayload struct {
+	WithdrawalID  uuid.UUID               `json:"withdrawal_id"`
+	UserID        uuid.UUID               `json:"user_id"`
+	StockSymbol   string                  `json:"stock_symbol"`
+	Quantity      decimal.Decimal         `json:"quantity"`
+	Status        models.WithdrawalStatus `json:"status"`
+	FailureReason *string                 `json:"failure_reason,omitempty"`
+}
+
+type PriceUpdatedPayload struct {
+	Symbol    string          `json:"symbol"`
+	Price     decimal.Decimal `json:"price"`
+	FetchedAt time.Time       `json:"fetched_at"`
+}
+
+func recordOutboxEvent(ctx context.Context, tx *sqlx.Tx, outboxRepo *repository.OutboxRepository, eventType models.OutboxEventType, aggregateType, aggregateID, partitionKey string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	event := &models.OutboxEvent{
+		MessageID:     uuid.New(),
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		PartitionKey:  partitionKey,
+		Payload:       data,
+		CreatedAt:     time.Now(),
+	}
+	if err := outboxRepo.Add(ctx, tx, event); err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+func recordRewardEvent(ctx context.Context, tx *sqlx.Tx, outboxRepo *repository.OutboxRepository, eventType models.OutboxEventType, reward *models.RewardEvent, from models.RewardStatus) error {
+	payload := RewardEventPayload{
+		EventID:      reward.EventID,
+		UserID:       reward.UserID,
+		StockSymbol:  reward.StockSymbol,
+		Quantity:     reward.Quantity,
+		BookingPrice: reward.BookingPrice,
+		Status:       reward.Status,
+		FromStatus:   from,
+		Timestamp:    reward.Timestamp,
+	}
+	return recordOutboxEvent(ctx, tx, outboxRepo, eventType, "reward", reward.EventID.String(), reward.UserID.String(), payload)
+}
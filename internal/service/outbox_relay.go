@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/outbox"
+	"stocky/internal/repository"
+)
+
+type OutboxRelay struct {
+	outboxRepo   *repository.OutboxRepository
+	sinks        []outbox.Sink
+	batchSize    int
+	leaseTimeout time.Duration
+}
+
+func NewOutboxRelay(outboxRepo *repository.OutboxRepository, sinks []outbox.Sink, batchSize int, leaseTimeout time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo:   outboxRepo,
+		sinks:        sinks,
+		batchSize:    batchSize,
+		leaseTimeout: leaseTimeout,
+	}
+}
+
+func (r *OutboxRelay) Relay(ctx context.Context) error {
+	for _, sink := range r.sinks {
+		if err := r.relaySink(ctx, sink); err != nil {
+			logrus.WithError(err).WithField("sink", sink.Name()).Error("Failed to relay outbox events")
+		}
+	}
+	return nil
+}
+
+func (r *OutboxRelay) relaySink(ctx context.Context, sink outbox.Sink) error {
+	leaseID := uuid.New()
+	now := time.Now()
+	claimed, err := r.outboxRepo.ClaimLease(ctx, sink.Name(), leaseID, now, now.Add(r.leaseTimeout))
+	if err != nil {
+		return fmt.Errorf("failed to claim sink lease: %w", err)
+	}
+	if !claimed {
+		return nil
+	}
+	defer func() {
+		if err := r.outboxRepo.ReleaseLease(ctx, sink.Name(), leaseID); err != nil {
+			logrus.WithError(err).WithField("sink", sink.Name()).Warn("Failed to release sink lease")
+		}
+	}()
+
+	delivered, err := r.publishPending(ctx, sink, leaseID)
+	if delivered > 0 {
+		logrus.WithFields(logrus.Fields{
+			"sink":      sink.Name(),
+			"delivered": delivered,
+		}).Info("Outbox events relayed")
+	}
+	return err
+}
+
+func (r *OutboxRelay) publishPending(ctx context.Context, sink outbox.Sink, leaseID uuid.UUID) (int, error) {
+	afterPartition, afterSequence := "", int64(0)
+	blocked := make(map[string]bool)
+	delivered := 0
+	for {
+		events, err := r.outboxRepo.ListUndelivered(ctx, sink.Name(), afterPartition, afterSequence, r.batchSize)
+		if err != nil {
+			return delivered, fmt.Errorf("failed to list outbox events: %w", err)
+		}
+		if len(events) == 0 {
+			return delivered, nil
+		}
+
+		for i := range events {
+			event := &events[i]
+			afterPartition, afterSequence = event.PartitionKey, event.Sequence
+			if blocked[event.PartitionKey] {
+				continue
+			}
+
+			held, err := r.outboxRepo.RenewLease(ctx, sink.Name(), leaseID, time.Now().Add(r.leaseTimeout))
+			if err != nil {
+				return delivered, fmt.Errorf("failed to renew sink lease: %w", err)
+			}
+			if !held {
+				logrus.WithField("sink", sink.Name()).Warn("Sink lease lost, stopping relay")
+				return delivered, nil
+			}
+
+			if err := sink.Publish(ctx, newOutboxMessage(event)); err != nil {
+				blocked[event.PartitionKey] = true
+				logrus.WithError(err).WithFields(logrus.Fields{
+					"sink":       sink.Name(),
+					"message_id": event.MessageID,
+					"event_type": event.EventType,
+				}).Warn("Failed to publish outbox event")
+				continue
+			}
+
+			if err := r.outboxRepo.MarkDelivered(ctx, sink.Name(), event.ID); err != nil {
+				return delivered, fmt.Errorf("failed to mark outbox event delivered: %w", err)
+			}
+			delivered++
+		}
+	}
+}
+
+func newOutboxMessage(event *models.OutboxEvent) outbox.Message {
+	return outbox.Message{
+		ID:             event.MessageID,
+		IdempotencyKey: fmt.Sprintf("%s:%d", event.MessageID, event.Sequence),
+		Type:           string(event.EventType),
+		AggregateType:  event.AggregateType,
+		AggregateID:    event.AggregateID,
+		PartitionKey:   event.PartitionKey,
+		Sequence:       event.Sequence,
+		Payload:        json.RawMessage(event.Payload),
+		OccurredAt:     event.CreatedAt,
+	}
+}
@@ -7,32 +7,56 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"stocky/internal/models"
 	"stocky/internal/repository"
 )
 
 type PortfolioService struct {
-	rewardRepo *repository.RewardRepository
-	priceRepo  *repository.StockPriceRepository
+	rewardRepo  *repository.RewardRepository
+	priceRepo   *repository.StockPriceRepository
+	vestingRepo *repository.VestingRepository
+	offerRepo   *repository.RewardOfferRepository
+	ledgerRepo  *repository.LedgerRepository
 }
 
 func NewPortfolioService(
 	rewardRepo *repository.RewardRepository,
 	priceRepo *repository.StockPriceRepository,
+	vestingRepo *repository.VestingRepository,
+	offerRepo *repository.RewardOfferRepository,
+	ledgerRepo *repository.LedgerRepository,
 ) *PortfolioService {
 	return &PortfolioService{
-		rewardRepo: rewardRepo,
-		priceRepo:  priceRepo,
+		rewardRepo:  rewardRepo,
+		priceRepo:   priceRepo,
+		vestingRepo: vestingRepo,
+		offerRepo:   offerRepo,
+		ledgerRepo:  ledgerRepo,
 	}
 }
 
 type TodayReward struct {
-	StockSymbol string          `json:"stock_symbol"`
-	Quantity    decimal.Decimal `json:"quantity"`
-	Timestamp   time.Time       `json:"timestamp"`
-	EventID     uuid.UUID       `json:"event_id"`
+	StockSymbol  string           `json:"stock_symbol"`
+	Quantity     decimal.Decimal  `json:"quantity"`
+	Timestamp    time.Time        `json:"timestamp"`
+	EventID      uuid.UUID        `json:"event_id"`
+	BookedAt     *time.Time       `json:"booked_at,omitempty"`
+	BookingPrice *decimal.Decimal `json:"booking_price,omitempty"`
 }
 
-func (s *PortfolioService) GetTodayRewards(ctx context.Context, userID uuid.UUID) ([]TodayReward, error) {
+type ClaimableOffer struct {
+	StockSymbol   string          `json:"stock_symbol"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	EventID       uuid.UUID       `json:"event_id"`
+	ClaimDeadline time.Time       `json:"claim_deadline"`
+}
+
+type TodayRewardsResponse struct {
+	Rewards         []TodayReward    `json:"rewards"`
+	ClaimableOffers []ClaimableOffer `json:"claimable_offers"`
+}
+
+func (s *PortfolioService) GetTodayRewards(ctx context.Context, userID uuid.UUID) (*TodayRewardsResponse, error) {
 	istLocation, err := time.LoadLocation("Asia/Kolkata")
 	if err != nil {
 		return nil, fmt.Errorf("failed to load IST timezone: %w", err)
@@ -47,67 +71,112 @@ func (s *PortfolioService) GetTodayRewards(ctx context.Context, userID uuid.UUID
 	result := make([]TodayReward, len(rewards))
 	for i, r := range rewards {
 		result[i] = TodayReward{
-			StockSymbol: r.StockSymbol,
-			Quantity:    r.Quantity,
-			Timestamp:   r.Timestamp,
-			EventID:     r.EventID,
+			StockSymbol:  r.StockSymbol,
+			Quantity:     r.Quantity,
+			Timestamp:    r.Timestamp,
+			EventID:      r.EventID,
+			BookedAt:     r.BookedAt,
+			BookingPrice: r.BookingPrice,
 		}
 	}
 
-	return result, nil
+	offers, err := s.offerRepo.ListClaimable(ctx, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list claimable offers: %w", err)
+	}
+
+	claimable := make([]ClaimableOffer, len(offers))
+	for i, o := range offers {
+		claimable[i] = ClaimableOffer{
+			StockSymbol:   o.StockSymbol,
+			Quantity:      o.Quantity,
+			EventID:       o.EventID,
+			ClaimDeadline: o.ClaimDeadline,
+		}
+	}
+
+	return &TodayRewardsResponse{Rewards: result, ClaimableOffers: claimable}, nil
 }
 
+const historicalINRDays = 30
+
 type HistoricalINRValue struct {
 	Date     string          `json:"date"`
 	INRValue decimal.Decimal `json:"inr_value"`
 }
 
 func (s *PortfolioService) GetHistoricalINR(ctx context.Context, userID uuid.UUID) ([]HistoricalINRValue, error) {
-	istLocation, err := time.LoadLocation("Asia/Kolkata")
+	today, err := holdingSnapshotDate(time.Now())
 	if err != nil {
-		return nil, fmt.Errorf("failed to load IST timezone: %w", err)
+		return nil, err
 	}
+	first := today.AddDate(0, 0, -historicalINRDays)
 
-	now := time.Now().In(istLocation)
-	yesterday := now.AddDate(0, 0, -1)
+	snapshots, err := s.ledgerRepo.GetHoldingSnapshots(ctx, userID, first, today.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holding snapshots: %w", err)
+	}
+	snapshotted := make(map[string]map[string]decimal.Decimal)
+	for _, snapshot := range snapshots {
+		day := snapshot.SnapshotDate.Format("2006-01-02")
+		if snapshotted[day] == nil {
+			snapshotted[day] = make(map[string]decimal.Decimal)
+		}
+		if snapshot.Symbol != nil {
+			snapshotted[day][*snapshot.Symbol] = snapshot.Quantity
+		}
+	}
 
-	var dates []time.Time
-	currentDate := yesterday
-	for i := 0; i < 30; i++ {
-		dates = append(dates, currentDate)
-		currentDate = currentDate.AddDate(0, 0, -1)
+	holdings := make([]map[string]decimal.Decimal, historicalINRDays)
+	seen := make(map[string]bool)
+	var symbols []string
+	for i := range holdings {
+		date := first.AddDate(0, 0, i)
+		sharesByStock, ok := snapshotted[date.Format("2006-01-02")]
+		if !ok {
+			sharesByStock, err = s.ledgerRepo.GetHoldingBalancesAt(ctx, userID, date.AddDate(0, 0, 1))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get holdings: %w", err)
+			}
+		}
+		holdings[i] = sharesByStock
+		for stock := range sharesByStock {
+			if !seen[stock] {
+				seen[stock] = true
+				symbols = append(symbols, stock)
+			}
+		}
 	}
 
 	var results []HistoricalINRValue
-	for _, date := range dates {
-		endOfDay := time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, istLocation)
-
-		sharesByStock, err := s.rewardRepo.GetTotalSharesByStockUpToDate(ctx, userID, endOfDay)
-		if err != nil {
-			continue
-		}
+	if len(symbols) == 0 {
+		return results, nil
+	}
 
-		if len(sharesByStock) == 0 {
-			continue
-		}
+	closing, err := s.priceRepo.GetDailyClosingPrices(ctx, symbols, first, historicalINRDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get historical prices: %w", err)
+	}
+	latest, err := s.priceRepo.GetAllLatest(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest prices: %w", err)
+	}
 
+	for i := historicalINRDays - 1; i >= 0; i-- {
 		totalValue := decimal.Zero
-		for stock, qty := range sharesByStock {
-			price, err := s.priceRepo.GetHistoricalPrices(ctx, stock, endOfDay)
-			if err != nil {
-				latestPrice, err := s.priceRepo.GetLatest(ctx, stock)
-				if err != nil {
-					continue
-				}
-				totalValue = totalValue.Add(latestPrice.Price.Mul(qty))
-			} else {
-				totalValue = totalValue.Add(price.Price.Mul(qty))
+		for stock, qty := range holdings[i] {
+			price, ok := closing[i][stock]
+			if !ok {
+				price, ok = latest[stock]
+			}
+			if ok {
+				totalValue = totalValue.Add(price.Mul(qty))
 			}
 		}
 
 		if totalValue.GreaterThan(decimal.Zero) {
 			results = append(results, HistoricalINRValue{
-				Date:     date.Format("2006-01-02"),
+				Date:     first.AddDate(0, 0, i).Format("2006-01-02"),
 				INRValue: totalValue.Round(2),
 			})
 		}
@@ -117,8 +186,11 @@ func (s *PortfolioService) GetHistoricalINR(ctx context.Context, userID uuid.UUI
 }
 
 type StatsResponse struct {
-	TodaySharesByStock  map[string]decimal.Decimal `json:"today_shares_by_stock"`
-	CurrentPortfolioValue decimal.Decimal          `json:"current_portfolio_value"`
+	TodaySharesByStock    map[string]decimal.Decimal `json:"today_shares_by_stock"`
+	VestedSharesByStock   map[string]decimal.Decimal `json:"vested_shares_by_stock"`
+	UnvestedSharesByStock map[string]decimal.Decimal `json:"unvested_shares_by_stock"`
+	CurrentPortfolioValue decimal.Decimal            `json:"current_portfolio_value"`
+	WalletBalanceINR      decimal.Decimal            `json:"wallet_balance_inr"`
 }
 
 func (s *PortfolioService) GetStats(ctx context.Context, userID uuid.UUID) (*StatsResponse, error) {
@@ -139,45 +211,67 @@ func (s *PortfolioService) GetStats(ctx context.Context, userID uuid.UUID) (*Sta
 		return nil, err
 	}
 
-	allShares, err := s.rewardRepo.GetTotalSharesByStockUpToDate(ctx, userID, now)
+	allShares, err := s.ledgerRepo.GetHoldingBalances(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	unvestedShares, err := s.vestingRepo.GetUnvestedSharesByStock(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	walletBalance, err := s.ledgerRepo.GetWalletBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	vestedShares := make(map[string]decimal.Decimal)
 	portfolioValue := decimal.Zero
 	for stock, qty := range allShares {
+		vestedShares[stock] = qty.Sub(unvestedShares[stock])
 		if price, ok := allPrices[stock]; ok {
 			portfolioValue = portfolioValue.Add(price.Mul(qty))
 		}
 	}
 
 	return &StatsResponse{
-		TodaySharesByStock:   todayShares,
+		TodaySharesByStock:    todayShares,
+		VestedSharesByStock:   vestedShares,
+		UnvestedSharesByStock: unvestedShares,
 		CurrentPortfolioValue: portfolioValue.Round(2),
+		WalletBalanceINR:      walletBalance,
 	}, nil
 }
 
 type PortfolioHolding struct {
-	StockSymbol   string          `json:"stock_symbol"`
-	TotalQuantity decimal.Decimal `json:"total_quantity"`
-	CurrentPrice  decimal.Decimal `json:"current_price"`
-	CurrentValue  decimal.Decimal `json:"current_value"`
+	StockSymbol      string          `json:"stock_symbol"`
+	TotalQuantity    decimal.Decimal `json:"total_quantity"`
+	VestedQuantity   decimal.Decimal `json:"vested_quantity"`
+	UnvestedQuantity decimal.Decimal `json:"unvested_quantity"`
+	SettledQuantity  decimal.Decimal `json:"settled_quantity"`
+	InFlightQuantity decimal.Decimal `json:"in_flight_quantity"`
+	CurrentPrice     decimal.Decimal `json:"current_price"`
+	CurrentValue     decimal.Decimal `json:"current_value"`
 }
 
 func (s *PortfolioService) GetPortfolio(ctx context.Context, userID uuid.UUID) ([]PortfolioHolding, error) {
-	istLocation, err := time.LoadLocation("Asia/Kolkata")
+	allShares, err := s.ledgerRepo.GetHoldingBalances(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load IST timezone: %w", err)
+		return nil, err
 	}
 
-	now := time.Now().In(istLocation)
+	allPrices, err := s.priceRepo.GetAllLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	allShares, err := s.rewardRepo.GetTotalSharesByStockUpToDate(ctx, userID, now)
+	unvestedShares, err := s.vestingRepo.GetUnvestedSharesByStock(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	allPrices, err := s.priceRepo.GetAllLatest(ctx)
+	sharesByStatus, err := s.rewardRepo.GetHeldSharesByStockAndStatus(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -189,14 +283,21 @@ func (s *PortfolioService) GetPortfolio(ctx context.Context, userID uuid.UUID) (
 			continue
 		}
 
+		unvested := unvestedShares[stock]
+		inFlight := sharesByStatus[stock][models.RewardStatusBooked].Add(sharesByStatus[stock][models.RewardStatusOrdered])
+		inFlight = decimal.Max(decimal.Min(inFlight, qty), decimal.Zero)
+		settled := qty.Sub(inFlight)
 		holdings = append(holdings, PortfolioHolding{
-			StockSymbol:   stock,
-			TotalQuantity: qty,
-			CurrentPrice:  price,
-			CurrentValue:  price.Mul(qty).Round(2),
+			StockSymbol:      stock,
+			TotalQuantity:    qty,
+			VestedQuantity:   qty.Sub(unvested),
+			UnvestedQuantity: unvested,
+			SettledQuantity:  settled,
+			InFlightQuantity: inFlight,
+			CurrentPrice:     price,
+			CurrentValue:     price.Mul(qty).Round(2),
 		})
 	}
 
 	return holdings, nil
 }
-
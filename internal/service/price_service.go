@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"stocky/internal/models"
@@ -12,18 +14,24 @@ import (
 )
 
 type PriceService struct {
-	priceRepo *repository.StockPriceRepository
+	priceRepo  *repository.StockPriceRepository
+	outboxRepo *repository.OutboxRepository
+	db         *sqlx.DB
 }
 
-func NewPriceService(priceRepo *repository.StockPriceRepository) *PriceService {
-	return &PriceService{priceRepo: priceRepo}
+func NewPriceService(priceRepo *repository.StockPriceRepository, outboxRepo *repository.OutboxRepository, db *sqlx.DB) *PriceService {
+	return &PriceService{
+		priceRepo:  priceRepo,
+		outboxRepo: outboxRepo,
+		db:         db,
+	}
 }
 
 var mockPrices = map[string]decimal.Decimal{
-	"RELIANCE": decimal.NewFromInt(2500),
-	"TCS":      decimal.NewFromInt(3500),
-	"INFY":     decimal.NewFromInt(1500),
-	"HDFCBANK": decimal.NewFromInt(1700),
+	"RELIANCE":  decimal.NewFromInt(2500),
+	"TCS":       decimal.NewFromInt(3500),
+	"INFY":      decimal.NewFromInt(1500),
+	"HDFCBANK":  decimal.NewFromInt(1700),
 	"ICICIBANK": decimal.NewFromInt(950),
 }
 
@@ -42,7 +50,7 @@ func (s *PriceService) FetchAndStorePrices(ctx context.Context) error {
 			FetchedAt: time.Now(),
 		}
 
-		if err := s.priceRepo.Upsert(ctx, price); err != nil {
+		if err := s.storePrice(ctx, price); err != nil {
 			logrus.WithError(err).WithField("symbol", symbol).Error("Failed to store price")
 			continue
 		}
@@ -57,6 +65,29 @@ func (s *PriceService) FetchAndStorePrices(ctx context.Context) error {
 	return nil
 }
 
+func (s *PriceService) storePrice(ctx context.Context, price *models.StockPrice) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.priceRepo.Upsert(ctx, tx, price); err != nil {
+		return err
+	}
+
+	payload := PriceUpdatedPayload{
+		Symbol:    price.Symbol,
+		Price:     price.Price,
+		FetchedAt: price.FetchedAt,
+	}
+	if err := recordOutboxEvent(ctx, tx, s.outboxRepo, models.OutboxEventPriceUpdated, "price", price.Symbol, price.Symbol, payload); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (s *PriceService) GetLatestPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
 	price, err := s.priceRepo.GetLatest(ctx, symbol)
 	if err != nil {
@@ -64,4 +95,3 @@ func (s *PriceService) GetLatestPrice(ctx context.Context, symbol string) (decim
 	}
 	return price.Price, nil
 }
-
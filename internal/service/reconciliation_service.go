@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+type ReconciliationService struct {
+	reconciliationRepo *repository.ReconciliationRepository
+	ledgerRepo         *repository.LedgerRepository
+	db                 *sqlx.DB
+}
+
+func NewReconciliationService(
+	reconciliationRepo *repository.ReconciliationRepository,
+	ledgerRepo *repository.LedgerRepository,
+	db *sqlx.DB,
+) *ReconciliationService {
+	return &ReconciliationService{
+		reconciliationRepo: reconciliationRepo,
+		ledgerRepo:         ledgerRepo,
+		db:                 db,
+	}
+}
+
+type DiscrepancyResponse struct {
+	Check       models.ReconciliationCheck `json:"check"`
+	ReferenceID *uuid.UUID                 `json:"reference_id,omitempty"`
+	Symbol      *string                    `json:"symbol,omitempty"`
+	Expected    *decimal.Decimal           `json:"expected,omitempty"`
+	Actual      *decimal.Decimal           `json:"actual,omitempty"`
+	Message     string                     `json:"message"`
+}
+
+type ReconciliationResponse struct {
+	ID               uuid.UUID                   `json:"id"`
+	Status           models.ReconciliationStatus `json:"status"`
+	DiscrepancyCount int                         `json:"discrepancy_count"`
+	StartedAt        time.Time                   `json:"started_at"`
+	FinishedAt       time.Time                   `json:"finished_at"`
+	Discrepancies    []*DiscrepancyResponse      `json:"discrepancies"`
+}
+
+func newReconciliationResponse(run *models.ReconciliationRun, discrepancies []models.ReconciliationDiscrepancy) *ReconciliationResponse {
+	resp := &ReconciliationResponse{
+		ID:               run.ID,
+		Status:           run.Status,
+		DiscrepancyCount: run.DiscrepancyCount,
+		StartedAt:        run.StartedAt,
+		FinishedAt:       run.FinishedAt,
+		Discrepancies:    make([]*DiscrepancyResponse, len(discrepancies)),
+	}
+	for i, d := range discrepancies {
+		resp.Discrepancies[i] = &DiscrepancyResponse{
+			Check:       d.CheckName,
+			ReferenceID: d.ReferenceID,
+			Symbol:      d.Symbol,
+			Expected:    d.Expected,
+			Actual:      d.Actual,
+			Message:     d.Message,
+		}
+	}
+	return resp
+}
+
+func (s *ReconciliationService) Reconcile(ctx context.Context) error {
+	_, err := s.Run(ctx)
+	return err
+}
+
+func (s *ReconciliationService) Run(ctx context.Context) (*ReconciliationResponse, error) {
+	run := &models.ReconciliationRun{
+		ID:        uuid.New(),
+		Status:    models.ReconciliationStatusPassed,
+		StartedAt: time.Now(),
+	}
+
+	discrepancies, err := s.check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	run.FinishedAt = time.Now()
+	run.DiscrepancyCount = len(discrepancies)
+	if len(discrepancies) > 0 {
+		run.Status = models.ReconciliationStatusFailed
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.reconciliationRepo.CreateRun(ctx, tx, run); err != nil {
+		return nil, fmt.Errorf("failed to create reconciliation run: %w", err)
+	}
+	for i := range discrepancies {
+		d := &discrepancies[i]
+		d.ID = uuid.New()
+		d.RunID = run.ID
+		d.CreatedAt = run.FinishedAt
+		if err := s.reconciliationRepo.CreateDiscrepancy(ctx, tx, d); err != nil {
+			return nil, fmt.Errorf("failed to create reconciliation discrepancy: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"run_id":        run.ID,
+		"discrepancies": run.DiscrepancyCount,
+		"duration":      run.FinishedAt.Sub(run.StartedAt),
+	})
+	if run.Status == models.ReconciliationStatusFailed {
+		log.Error("Reconciliation found discrepancies")
+	} else {
+		log.Info("Reconciliation passed")
+	}
+
+	return newReconciliationResponse(run, discrepancies), nil
+}
+
+func (s *ReconciliationService) GetLatest(ctx context.Context) (*ReconciliationResponse, error) {
+	run, err := s.reconciliationRepo.GetLatestRun(ctx)
+	if err == sql.ErrNoRows {
+		return nil, ErrReconciliationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reconciliation run: %w", err)
+	}
+
+	discrepancies, err := s.reconciliationRepo.ListDiscrepancies(ctx, run.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reconciliation discrepancies: %w", err)
+	}
+	return newReconciliationResponse(run, discrepancies), nil
+}
+
+func (s *ReconciliationService) check(ctx context.Context) ([]models.ReconciliationDiscrepancy, error) {
+	var discrepancies []models.ReconciliationDiscrepancy
+
+	debit, credit, err := s.ledgerRepo.GetTotals(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger totals: %w", err)
+	}
+	if !debit.Equal(credit) {
+		discrepancies = append(discrepancies, models.ReconciliationDiscrepancy{
+			CheckName: models.ReconciliationCheckLedgerTotals,
+			Expected:  &debit,
+			Actual:    &credit,
+			Message:   "total debits and credits differ",
+		})
+	}
+
+	finders := []struct {
+		name string
+		find func(context.Context) ([]models.ReconciliationDiscrepancy, error)
+	}{
+		{"event imbalances", s.reconciliationRepo.FindEventImbalances},
+		{"reward value mismatches", s.reconciliationRepo.FindRewardValueMismatches},
+		{"misattributed postings", s.reconciliationRepo.FindMisattributedPostings},
+		{"orphan postings", s.reconciliationRepo.FindOrphanPostings},
+		{"holdings mismatches", s.reconciliationRepo.FindHoldingsMismatches},
+		{"inventory pool mismatches", s.reconciliationRepo.FindPoolMismatches},
+		{"inventory value mismatches", s.reconciliationRepo.FindInventoryValueMismatches},
+		{"holding balance mismatches", s.reconciliationRepo.FindHoldingBalanceMismatches},
+	}
+	for _, f := range finders {
+		found, err := f.find(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find %s: %w", f.name, err)
+		}
+		discrepancies = append(discrepancies, found...)
+	}
+
+	return discrepancies, nil
+}
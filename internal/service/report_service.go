@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+type ReportService struct {
+	reportRepo *repository.ReportRepository
+}
+
+func NewReportService(reportRepo *repository.ReportRepository) *ReportService {
+	return &ReportService{reportRepo: reportRepo}
+}
+
+type ReportQuery struct {
+	From    string `form:"from"`
+	To      string `form:"to"`
+	Account string `form:"account"`
+}
+
+type TrialBalanceLine struct {
+	AccountCode    string                   `json:"account_code"`
+	AccountName    string                   `json:"account_name"`
+	AccountType    models.LedgerAccountType `json:"account_type"`
+	EntryType      models.LedgerEntryType   `json:"entry_type"`
+	OpeningBalance decimal.Decimal          `json:"opening_balance"`
+	Debit          decimal.Decimal          `json:"debit"`
+	Credit         decimal.Decimal          `json:"credit"`
+	ClosingBalance decimal.Decimal          `json:"closing_balance"`
+	ClosingDebit   decimal.Decimal          `json:"closing_debit"`
+	ClosingCredit  decimal.Decimal          `json:"closing_credit"`
+}
+
+type TrialBalanceReport struct {
+	From          time.Time           `json:"from"`
+	To            time.Time           `json:"to"`
+	Lines         []*TrialBalanceLine `json:"lines"`
+	TotalDebit    decimal.Decimal     `json:"total_debit"`
+	TotalCredit   decimal.Decimal     `json:"total_credit"`
+	ClosingDebit  decimal.Decimal     `json:"closing_debit"`
+	ClosingCredit decimal.Decimal     `json:"closing_credit"`
+	Balanced      bool                `json:"balanced"`
+}
+
+type AccountActivityLine struct {
+	Date        string                   `json:"date"`
+	AccountCode string                   `json:"account_code"`
+	AccountName string                   `json:"account_name"`
+	AccountType models.LedgerAccountType `json:"account_type"`
+	EntryType   models.LedgerEntryType   `json:"entry_type"`
+	Postings    int64                    `json:"postings"`
+	Debit       decimal.Decimal          `json:"debit"`
+	Credit      decimal.Decimal          `json:"credit"`
+}
+
+type AccountActivityReport struct {
+	From  time.Time              `json:"from"`
+	To    time.Time              `json:"to"`
+	Lines []*AccountActivityLine `json:"lines"`
+}
+
+type GeneralLedgerLine struct {
+	Sequence    int64                  `json:"sequence"`
+	EntryID     uuid.UUID              `json:"entry_id"`
+	CreatedAt   time.Time              `json:"created_at"`
+	AccountCode string                 `json:"account_code"`
+	EntryType   models.LedgerEntryType `json:"entry_type"`
+	EventID     uuid.UUID              `json:"event_id"`
+	UserID      *uuid.UUID             `json:"user_id,omitempty"`
+	Symbol      *string                `json:"symbol,omitempty"`
+	Debit       decimal.Decimal        `json:"debit"`
+	Credit      decimal.Decimal        `json:"credit"`
+	Balance     decimal.Decimal        `json:"balance"`
+}
+
+func (s *ReportService) TrialBalance(ctx context.Context, query ReportQuery) (*TrialBalanceReport, error) {
+	from, to, err := parseDateRange(query.From, query.To, ErrInvalidReport)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.reportRepo.GetTrialBalance(ctx, from, to, query.Account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trial balance: %w", err)
+	}
+
+	report := &TrialBalanceReport{From: from, To: to, Lines: make([]*TrialBalanceLine, len(rows))}
+	for i, row := range rows {
+		net := row.OpeningDebit.Add(row.PeriodDebit).Sub(row.OpeningCredit).Sub(row.PeriodCredit)
+		line := &TrialBalanceLine{
+			AccountCode:    row.AccountCode,
+			AccountName:    row.AccountName,
+			AccountType:    row.AccountType,
+			EntryType:      row.EntryType,
+			OpeningBalance: naturalBalance(row.AccountType, row.OpeningDebit, row.OpeningCredit),
+			Debit:          row.PeriodDebit,
+			Credit:         row.PeriodCredit,
+			ClosingBalance: naturalBalance(row.AccountType, row.OpeningDebit.Add(row.PeriodDebit), row.OpeningCredit.Add(row.PeriodCredit)),
+			ClosingDebit:   decimal.Max(net, decimal.Zero),
+			ClosingCredit:  decimal.Max(net.Neg(), decimal.Zero),
+		}
+		report.Lines[i] = line
+		report.TotalDebit = report.TotalDebit.Add(line.Debit)
+		report.TotalCredit = report.TotalCredit.Add(line.Credit)
+		report.ClosingDebit = report.ClosingDebit.Add(line.ClosingDebit)
+		report.ClosingCredit = report.ClosingCredit.Add(line.ClosingCredit)
+	}
+	report.Balanced = report.TotalDebit.Equal(report.TotalCredit) && report.ClosingDebit.Equal(report.ClosingCredit)
+	return report, nil
+}
+
+func (s *ReportService) AccountActivity(ctx context.Context, query ReportQuery) (*AccountActivityReport, error) {
+	from, to, err := parseDateRange(query.From, query.To, ErrInvalidReport)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.reportRepo.GetAccountActivity(ctx, from, to, query.Account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account activity: %w", err)
+	}
+
+	report := &AccountActivityReport{From: from, To: to, Lines: make([]*AccountActivityLine, len(rows))}
+	for i, row := range rows {
+		report.Lines[i] = &AccountActivityLine{
+			Date:        row.Day.Format("2006-01-02"),
+			AccountCode: row.AccountCode,
+			AccountName: row.AccountName,
+			AccountType: row.AccountType,
+			EntryType:   row.EntryType,
+			Postings:    row.Postings,
+			Debit:       row.Debit,
+			Credit:      row.Credit,
+		}
+	}
+	return report, nil
+}
+
+func (s *ReportService) GeneralLedger(ctx context.Context, query ReportQuery, fn func(*GeneralLedgerLine) error) error {
+	from, to, err := parseDateRange(query.From, query.To, ErrInvalidReport)
+	if err != nil {
+		return err
+	}
+
+	err = s.reportRepo.EachGeneralLedgerRow(ctx, from, to, query.Account, func(row *models.GeneralLedgerRow) error {
+		balance := row.CreditBalance
+		if row.AccountType.DebitNormal() {
+			balance = balance.Neg()
+		}
+		return fn(&GeneralLedgerLine{
+			Sequence:    row.Sequence,
+			EntryID:     row.EntryID,
+			CreatedAt:   row.CreatedAt,
+			AccountCode: row.AccountCode,
+			EntryType:   row.EntryType,
+			EventID:     row.EventID,
+			UserID:      row.UserID,
+			Symbol:      row.Symbol,
+			Debit:       row.Debit,
+			Credit:      row.Credit,
+			Balance:     balance,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read general ledger: %w", err)
+	}
+	return nil
+}
+
+func (s *ReportService) ValidateQuery(query ReportQuery) error {
+	_, _, err := parseDateRange(query.From, query.To, ErrInvalidReport)
+	return err
+}
+
+func naturalBalance(accountType models.LedgerAccountType, debit, credit decimal.Decimal) decimal.Decimal {
+	if accountType.DebitNormal() {
+		return debit.Sub(credit)
+	}
+	return credit.Sub(debit)
+}
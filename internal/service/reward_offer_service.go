@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+type OfferPolicy struct {
+	MaxClaimWindow time.Duration
+}
+
+type RewardOfferService struct {
+	offerRepo     *repository.RewardOfferRepository
+	rewardRepo    *repository.RewardRepository
+	rewardService *RewardService
+	policy        OfferPolicy
+	db            *sqlx.DB
+}
+
+func NewRewardOfferService(
+	offerRepo *repository.RewardOfferRepository,
+	rewardRepo *repository.RewardRepository,
+	rewardService *RewardService,
+	policy OfferPolicy,
+	db *sqlx.DB,
+) *RewardOfferService {
+	return &RewardOfferService{
+		offerRepo:     offerRepo,
+		rewardRepo:    rewardRepo,
+		rewardService: rewardService,
+		policy:        policy,
+		db:            db,
+	}
+}
+
+type CreateCampaignRequest struct {
+	Name      string          `json:"name" binding:"required"`
+	BudgetINR decimal.Decimal `json:"budget_inr" binding:"required"`
+}
+
+type CampaignResponse struct {
+	ID           uuid.UUID       `json:"id"`
+	Name         string          `json:"name"`
+	BudgetINR    decimal.Decimal `json:"budget_inr"`
+	ReservedINR  decimal.Decimal `json:"reserved_inr"`
+	SpentINR     decimal.Decimal `json:"spent_inr"`
+	AvailableINR decimal.Decimal `json:"available_inr"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+func newCampaignResponse(c *models.RewardCampaign) *CampaignResponse {
+	return &CampaignResponse{
+		ID:           c.ID,
+		Name:         c.Name,
+		BudgetINR:    c.BudgetINR,
+		ReservedINR:  c.ReservedINR,
+		SpentINR:     c.SpentINR,
+		AvailableINR: c.AvailableINR(),
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+	}
+}
+
+type OfferRewardRequest struct {
+	UserID        uuid.UUID        `json:"user_id" binding:"required"`
+	StockSymbol   string           `json:"stock_symbol" binding:"required"`
+	Quantity      decimal.Decimal  `json:"quantity" binding:"required"`
+	EventID       uuid.UUID        `json:"event_id" binding:"required"`
+	ClaimDeadline time.Time        `json:"claim_deadline" binding:"required"`
+	CampaignID    *uuid.UUID       `json:"campaign_id,omitempty"`
+	Vesting       *VestingSchedule `json:"vesting,omitempty"`
+	RequestedBy   string           `json:"requested_by,omitempty"`
+	PartnerID     string           `json:"partner_id,omitempty"`
+}
+
+type OfferResponse struct {
+	EventID       uuid.UUID                `json:"event_id"`
+	CampaignID    *uuid.UUID               `json:"campaign_id,omitempty"`
+	UserID        uuid.UUID                `json:"user_id"`
+	StockSymbol   string                   `json:"stock_symbol"`
+	Quantity      decimal.Decimal          `json:"quantity"`
+	ReservedINR   decimal.Decimal          `json:"reserved_inr"`
+	ClaimDeadline time.Time                `json:"claim_deadline"`
+	Status        models.RewardOfferStatus `json:"status"`
+	ClaimedAt     *time.Time               `json:"claimed_at,omitempty"`
+	ExpiredAt     *time.Time               `json:"expired_at,omitempty"`
+	CreatedAt     time.Time                `json:"created_at"`
+}
+
+func newOfferResponse(o *models.RewardOffer) *OfferResponse {
+	return &OfferResponse{
+		EventID:       o.EventID,
+		CampaignID:    o.CampaignID,
+		UserID:        o.UserID,
+		StockSymbol:   o.StockSymbol,
+		Quantity:      o.Quantity,
+		ReservedINR:   o.ReservedINR,
+		ClaimDeadline: o.ClaimDeadline,
+		Status:        o.Status,
+		ClaimedAt:     o.ClaimedAt,
+		ExpiredAt:     o.ExpiredAt,
+		CreatedAt:     o.CreatedAt,
+	}
+}
+
+func (s *RewardOfferService) CreateCampaign(ctx context.Context, req CreateCampaignRequest) (*CampaignResponse, error) {
+	if !req.BudgetINR.IsPositive() {
+		return nil, fmt.Errorf("%w: budget_inr must be positive", ErrInvalidCampaign)
+	}
+
+	campaign := &models.RewardCampaign{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		BudgetINR: req.BudgetINR,
+		CreatedAt: time.Now(),
+	}
+	campaign.UpdatedAt = campaign.CreatedAt
+	if err := s.offerRepo.CreateCampaign(ctx, campaign); err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"campaign_id": campaign.ID,
+		"budget_inr":  campaign.BudgetINR,
+	}).Info("Campaign created")
+	return newCampaignResponse(campaign), nil
+}
+
+func (s *RewardOfferService) ListCampaigns(ctx context.Context) ([]*CampaignResponse, error) {
+	campaigns, err := s.offerRepo.ListCampaigns(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+
+	result := make([]*CampaignResponse, len(campaigns))
+	for i := range campaigns {
+		result[i] = newCampaignResponse(&campaigns[i])
+	}
+	return result, nil
+}
+
+func (s *RewardOfferService) GetCampaign(ctx context.Context, id uuid.UUID) (*CampaignResponse, error) {
+	campaign, err := s.offerRepo.GetCampaign(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrCampaignNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+	return newCampaignResponse(campaign), nil
+}
+
+func (s *RewardOfferService) Offer(ctx context.Context, req OfferRewardRequest) (*OfferResponse, error) {
+	now := time.Now()
+	if !req.ClaimDeadline.After(now) {
+		return nil, fmt.Errorf("%w: claim_deadline must be in the future", ErrInvalidOffer)
+	}
+	if s.policy.MaxClaimWindow > 0 && req.ClaimDeadline.After(now.Add(s.policy.MaxClaimWindow)) {
+		return nil, fmt.Errorf("%w: claim_deadline is more than %s ahead", ErrInvalidOffer, s.policy.MaxClaimWindow)
+	}
+	if !req.Quantity.IsPositive() {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidOffer)
+	}
+	if req.Vesting != nil {
+		if _, err := buildVestingTranches(req.Vesting, req.EventID, req.Quantity, req.ClaimDeadline); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.rewardRepo.GetByEventID(ctx, req.EventID); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRewardAlreadyExists, req.EventID)
+	} else if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to check existing reward: %w", err)
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode reward offer: %w", err)
+	}
+
+	offer := &models.RewardOffer{
+		ID:            uuid.New(),
+		EventID:       req.EventID,
+		CampaignID:    req.CampaignID,
+		UserID:        req.UserID,
+		StockSymbol:   req.StockSymbol,
+		Quantity:      req.Quantity,
+		Payload:       payload,
+		ReservedINR:   decimal.Zero,
+		ClaimDeadline: req.ClaimDeadline,
+		Status:        models.RewardOfferStatusOffered,
+		CreatedAt:     now,
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var campaign *models.RewardCampaign
+	if req.CampaignID != nil {
+		campaign, err = s.offerRepo.GetCampaignForUpdate(ctx, tx, *req.CampaignID)
+		if err == sql.ErrNoRows {
+			return nil, ErrCampaignNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get campaign: %w", err)
+		}
+
+		stockPrice, err := s.rewardService.priceAt(ctx, req.StockSymbol, now)
+		if err != nil {
+			return nil, err
+		}
+		offer.ReservedINR = stockPrice.Price.Mul(req.Quantity).Round(4)
+		if offer.ReservedINR.GreaterThan(campaign.AvailableINR()) {
+			return nil, fmt.Errorf("%w: needs %s, %s available", ErrCampaignBudgetExceeded, offer.ReservedINR, campaign.AvailableINR())
+		}
+	}
+
+	inserted, err := s.offerRepo.Create(ctx, tx, offer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reward offer: %w", err)
+	}
+	if inserted && campaign != nil {
+		campaign.ReservedINR = campaign.ReservedINR.Add(offer.ReservedINR)
+		if err := s.offerRepo.UpdateCampaignTotals(ctx, tx, campaign); err != nil {
+			return nil, fmt.Errorf("failed to reserve campaign budget: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if inserted {
+		logrus.WithFields(logrus.Fields{
+			"event_id":       req.EventID,
+			"user_id":        req.UserID,
+			"stock_symbol":   req.StockSymbol,
+			"claim_deadline": req.ClaimDeadline,
+			"reserved_inr":   offer.ReservedINR,
+		}).Info("Reward offered")
+	}
+
+	return s.get(ctx, req.EventID)
+}
+
+func (s *RewardOfferService) Claim(ctx context.Context, eventID uuid.UUID) (*models.RewardEvent, error) {
+	offer, err := s.offerRepo.GetByEventID(ctx, eventID)
+	if err == sql.ErrNoRows {
+		return nil, ErrOfferNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reward offer: %w", err)
+	}
+
+	switch {
+	case offer.Status == models.RewardOfferStatusClaimed:
+		reward, err := s.rewardRepo.GetByEventID(ctx, eventID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reward: %w", err)
+		}
+		return reward, nil
+	case offer.Status == models.RewardOfferStatusExpired, !offer.ClaimDeadline.After(time.Now()):
+		return nil, ErrOfferExpired
+	}
+
+	var req OfferRewardRequest
+	if err := json.Unmarshal(offer.Payload, &req); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	claimedAt := time.Now()
+	rewardReq := RewardRequest{
+		UserID:      offer.UserID,
+		StockSymbol: offer.StockSymbol,
+		Quantity:    offer.Quantity,
+		Timestamp:   claimedAt,
+		EventID:     offer.EventID,
+		Vesting:     req.Vesting,
+		RequestedBy: req.RequestedBy,
+		PartnerID:   req.PartnerID,
+		ReceivedAt:  claimedAt,
+	}
+
+	reward, err := s.rewardService.processReward(ctx, rewardReq, func(tx *sqlx.Tx, reward *models.RewardEvent) error {
+		return s.markClaimed(ctx, tx, reward, claimedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event_id":      eventID,
+		"user_id":       offer.UserID,
+		"booking_price": reward.BookingPrice,
+	}).Info("Reward offer claimed")
+	return reward, nil
+}
+
+func (s *RewardOfferService) markClaimed(ctx context.Context, tx *sqlx.Tx, reward *models.RewardEvent, claimedAt time.Time) error {
+	offer, err := s.offerRepo.GetByEventIDForUpdate(ctx, tx, reward.EventID)
+	if err != nil {
+		return fmt.Errorf("failed to get reward offer: %w", err)
+	}
+	if offer.Status != models.RewardOfferStatusOffered {
+		return fmt.Errorf("%w: status is %s", ErrOfferNotClaimable, offer.Status)
+	}
+	if !offer.ClaimDeadline.After(claimedAt) {
+		return ErrOfferExpired
+	}
+
+	offer.Status = models.RewardOfferStatusClaimed
+	offer.ClaimedAt = &claimedAt
+	if err := s.offerRepo.UpdateStatus(ctx, tx, offer); err != nil {
+		return fmt.Errorf("failed to update reward offer: %w", err)
+	}
+
+	if offer.CampaignID == nil {
+		return nil
+	}
+	campaign, err := s.offerRepo.GetCampaignForUpdate(ctx, tx, *offer.CampaignID)
+	if err != nil {
+		return fmt.Errorf("failed to get campaign: %w", err)
+	}
+	campaign.ReservedINR = decimal.Max(campaign.ReservedINR.Sub(offer.ReservedINR), decimal.Zero)
+	campaign.SpentINR = campaign.SpentINR.Add(reward.BookingPrice.Mul(reward.Quantity).Round(4))
+	if err := s.offerRepo.UpdateCampaignTotals(ctx, tx, campaign); err != nil {
+		return fmt.Errorf("failed to update campaign budget: %w", err)
+	}
+	return nil
+}
+
+func (s *RewardOfferService) ExpireOffers(ctx context.Context) error {
+	eventIDs, err := s.offerRepo.ListExpiredIDs(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to list expired reward offers: %w", err)
+	}
+
+	for _, eventID := range eventIDs {
+		if err := s.expireOne(ctx, eventID); err != nil {
+			logrus.WithError(err).WithField("event_id", eventID).Error("Failed to expire reward offer")
+		}
+	}
+	return nil
+}
+
+func (s *RewardOfferService) expireOne(ctx context.Context, eventID uuid.UUID) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	offer, err := s.offerRepo.GetByEventIDForUpdate(ctx, tx, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to get reward offer: %w", err)
+	}
+	now := time.Now()
+	if offer.Status != models.RewardOfferStatusOffered || offer.ClaimDeadline.After(now) {
+		return nil
+	}
+
+	offer.Status = models.RewardOfferStatusExpired
+	offer.ExpiredAt = &now
+	if err := s.offerRepo.UpdateStatus(ctx, tx, offer); err != nil {
+		return fmt.Errorf("failed to update reward offer: %w", err)
+	}
+
+	if offer.CampaignID != nil {
+		campaign, err := s.offerRepo.GetCampaignForUpdate(ctx, tx, *offer.CampaignID)
+		if err != nil {
+			return fmt.Errorf("failed to get campaign: %w", err)
+		}
+		campaign.ReservedINR = decimal.Max(campaign.ReservedINR.Sub(offer.ReservedINR), decimal.Zero)
+		if err := s.offerRepo.UpdateCampaignTotals(ctx, tx, campaign); err != nil {
+			return fmt.Errorf("failed to release campaign budget: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event_id":     eventID,
+		"released_inr": offer.ReservedINR,
+	}).Info("Reward offer expired")
+	return nil
+}
+
+func (s *RewardOfferService) get(ctx context.Context, eventID uuid.UUID) (*OfferResponse, error) {
+	offer, err := s.offerRepo.GetByEventID(ctx, eventID)
+	if err == sql.ErrNoRows {
+		return nil, ErrOfferNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reward offer: %w", err)
+	}
+	return newOfferResponse(offer), nil
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+type QueuePolicy struct {
+	MaxAttempts       int
+	BaseBackoff       time.Duration
+	MaxBackoff        time.Duration
+	VisibilityTimeout time.Duration
+}
+
+type RewardQueueService struct {
+	jobRepo       *repository.RewardJobRepository
+	rewardRepo    *repository.RewardRepository
+	scheduleRepo  *repository.ScheduledRewardRepository
+	rewardService *RewardService
+	deadLetters   *DeadLetterService
+	policy        QueuePolicy
+}
+
+func NewRewardQueueService(
+	jobRepo *repository.RewardJobRepository,
+	rewardRepo *repository.RewardRepository,
+	scheduleRepo *repository.ScheduledRewardRepository,
+	rewardService *RewardService,
+	deadLetters *DeadLetterService,
+	policy QueuePolicy,
+) *RewardQueueService {
+	return &RewardQueueService{
+		jobRepo:       jobRepo,
+		rewardRepo:    rewardRepo,
+		scheduleRepo:  scheduleRepo,
+		rewardService: rewardService,
+		deadLetters:   deadLetters,
+		policy:        policy,
+	}
+}
+
+type RewardStatusResponse struct {
+	EventID       uuid.UUID               `json:"event_id"`
+	Status        string                  `json:"status"`
+	JobStatus     *models.RewardJobStatus `json:"job_status,omitempty"`
+	Attempts      int                     `json:"attempts,omitempty"`
+	MaxAttempts   int                     `json:"max_attempts,omitempty"`
+	LastError     *string                 `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time              `json:"next_attempt_at,omitempty"`
+	ScheduledFor  *time.Time              `json:"scheduled_for,omitempty"`
+	RewardStatus  *models.RewardStatus    `json:"reward_status,omitempty"`
+	EventTime     *time.Time              `json:"event_time,omitempty"`
+	BookedAt      *time.Time              `json:"booked_at,omitempty"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+}
+
+func (s *RewardQueueService) Enqueue(ctx context.Context, req RewardRequest) (*RewardStatusResponse, error) {
+	if req.ReceivedAt.IsZero() {
+		req.ReceivedAt = time.Now()
+	}
+	if err := s.rewardService.timestampPolicy.Validate(req.Timestamp, req.ReceivedAt); err != nil {
+		return nil, err
+	}
+	if err := s.rewardService.checkLockedPeriod(ctx, req.Timestamp); err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode reward request: %w", err)
+	}
+
+	now := time.Now()
+	job := &models.RewardJob{
+		ID:            uuid.New(),
+		EventID:       req.EventID,
+		Payload:       payload,
+		Status:        models.RewardJobStatusQueued,
+		MaxAttempts:   s.policy.MaxAttempts,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+
+	inserted, err := s.jobRepo.Enqueue(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue reward: %w", err)
+	}
+	if inserted {
+		logrus.WithFields(logrus.Fields{
+			"event_id": req.EventID,
+			"job_id":   job.ID,
+		}).Info("Reward enqueued")
+	}
+
+	return s.GetStatus(ctx, req.EventID)
+}
+
+func (s *RewardQueueService) GetStatus(ctx context.Context, eventID uuid.UUID) (*RewardStatusResponse, error) {
+	resp := &RewardStatusResponse{EventID: eventID}
+
+	job, err := s.jobRepo.GetByEventID(ctx, eventID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get reward job: %w", err)
+	}
+	if err == nil {
+		resp.Status = string(job.Status)
+		resp.JobStatus = &job.Status
+		resp.Attempts = job.Attempts
+		resp.MaxAttempts = job.MaxAttempts
+		resp.LastError = job.LastError
+		resp.UpdatedAt = job.UpdatedAt
+		if job.Status == models.RewardJobStatusQueued {
+			resp.NextAttemptAt = &job.NextAttemptAt
+		}
+	}
+
+	scheduled, err := s.scheduleRepo.GetByEventID(ctx, eventID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get scheduled reward: %w", err)
+	}
+	if err == nil {
+		if resp.JobStatus == nil {
+			resp.Status = string(scheduled.Status)
+		}
+		resp.ScheduledFor = &scheduled.ScheduledFor
+		resp.LastError = scheduled.LastError
+		if scheduled.UpdatedAt.After(resp.UpdatedAt) {
+			resp.UpdatedAt = scheduled.UpdatedAt
+		}
+	}
+
+	reward, err := s.rewardRepo.GetByEventID(ctx, eventID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get reward: %w", err)
+	}
+	if err == nil {
+		resp.Status = string(reward.Status)
+		resp.RewardStatus = &reward.Status
+		resp.EventTime = &reward.Timestamp
+		resp.BookedAt = reward.BookedAt
+		if reward.CreatedAt.After(resp.UpdatedAt) {
+			resp.UpdatedAt = reward.CreatedAt
+		}
+	}
+
+	if resp.Status == "" {
+		return nil, ErrRewardNotFound
+	}
+	return resp, nil
+}
+
+func (s *RewardQueueService) ProcessNext(ctx context.Context) (bool, error) {
+	now := time.Now()
+	job, err := s.jobRepo.ClaimNext(ctx, now, now.Add(-s.policy.VisibilityTimeout))
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim reward job: %w", err)
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"job_id":   job.ID,
+		"event_id": job.EventID,
+		"attempt":  job.Attempts,
+	})
+
+	var req RewardRequest
+	procErr := json.Unmarshal(job.Payload, &req)
+	if procErr != nil {
+		procErr = fmt.Errorf("%w: %v", ErrInvalidPayload, procErr)
+	} else {
+		_, procErr = s.rewardService.ProcessReward(ctx, req)
+	}
+
+	job.LockedAt = nil
+	if procErr == nil {
+		completedAt := time.Now()
+		job.Status = models.RewardJobStatusSucceeded
+		job.LastError = nil
+		job.CompletedAt = &completedAt
+		log.Info("Reward job succeeded")
+		return true, s.jobRepo.Update(ctx, job)
+	}
+
+	errMsg := procErr.Error()
+	job.LastError = &errMsg
+	if isPermanentRewardError(procErr) || job.Attempts >= job.MaxAttempts {
+		completedAt := time.Now()
+		job.Status = models.RewardJobStatusFailed
+		job.CompletedAt = &completedAt
+		log.WithError(procErr).Error("Reward job failed permanently")
+		if err := s.deadLetters.capture(ctx, models.DeadLetterSourceQueue, job.EventID, job.Payload, job.Attempts, procErr); err != nil {
+			log.WithError(err).Error("Failed to dead-letter reward job")
+		}
+	} else {
+		job.Status = models.RewardJobStatusQueued
+		job.NextAttemptAt = time.Now().Add(retryBackoff(s.policy.BaseBackoff, s.policy.MaxBackoff, job.Attempts))
+		log.WithError(procErr).WithField("next_attempt_at", job.NextAttemptAt).Warn("Reward job failed, will retry")
+	}
+
+	return true, s.jobRepo.Update(ctx, job)
+}
+
+func retryBackoff(base, maxDelay time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+func isPermanentRewardError(err error) bool {
+	return errors.Is(err, ErrInvalidVestingSchedule) || errors.Is(err, ErrInvalidPayload) ||
+		errors.Is(err, ErrOperatorRequired) || errors.Is(err, ErrPartnerMismatch) ||
+		errors.Is(err, ErrInvalidTimestamp) || errors.Is(err, ErrPeriodClosed)
+}
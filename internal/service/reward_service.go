@@ -16,11 +16,18 @@ import (
 )
 
 type RewardService struct {
-	rewardRepo *repository.RewardRepository
-	ledgerRepo *repository.LedgerRepository
-	userRepo   *repository.UserRepository
-	priceRepo  *repository.StockPriceRepository
-	db         *sqlx.DB
+	rewardRepo       *repository.RewardRepository
+	ledgerRepo       *repository.LedgerRepository
+	userRepo         *repository.UserRepository
+	priceRepo        *repository.StockPriceRepository
+	vestingRepo      *repository.VestingRepository
+	approvalRepo     *repository.ApprovalRepository
+	periodRepo       *repository.AccountingPeriodRepository
+	approvalPolicy   ApprovalPolicy
+	timestampPolicy  TimestampPolicy
+	inventoryService *InventoryService
+	outboxRepo       *repository.OutboxRepository
+	db               *sqlx.DB
 }
 
 func NewRewardService(
@@ -28,118 +35,168 @@ func NewRewardService(
 	ledgerRepo *repository.LedgerRepository,
 	userRepo *repository.UserRepository,
 	priceRepo *repository.StockPriceRepository,
+	vestingRepo *repository.VestingRepository,
+	approvalRepo *repository.ApprovalRepository,
+	periodRepo *repository.AccountingPeriodRepository,
+	approvalPolicy ApprovalPolicy,
+	timestampPolicy TimestampPolicy,
+	inventoryService *InventoryService,
+	outboxRepo *repository.OutboxRepository,
 	db *sqlx.DB,
 ) *RewardService {
 	return &RewardService{
-		rewardRepo: rewardRepo,
-		ledgerRepo: ledgerRepo,
-		userRepo:   userRepo,
-		priceRepo:  priceRepo,
-		db:         db,
+		rewardRepo:       rewardRepo,
+		ledgerRepo:       ledgerRepo,
+		userRepo:         userRepo,
+		priceRepo:        priceRepo,
+		vestingRepo:      vestingRepo,
+		approvalRepo:     approvalRepo,
+		periodRepo:       periodRepo,
+		approvalPolicy:   approvalPolicy,
+		timestampPolicy:  timestampPolicy,
+		inventoryService: inventoryService,
+		outboxRepo:       outboxRepo,
+		db:               db,
 	}
 }
 
 type RewardRequest struct {
-	UserID      uuid.UUID       `json:"user_id" binding:"required"`
-	StockSymbol string          `json:"stock_symbol" binding:"required"`
-	Quantity    decimal.Decimal `json:"quantity" binding:"required"`
-	Timestamp   time.Time       `json:"timestamp" binding:"required"`
-	EventID     uuid.UUID       `json:"event_id" binding:"required"`
+	UserID      uuid.UUID        `json:"user_id" binding:"required"`
+	StockSymbol string           `json:"stock_symbol" binding:"required"`
+	Quantity    decimal.Decimal  `json:"quantity" binding:"required"`
+	Timestamp   time.Time        `json:"timestamp" binding:"required"`
+	EventID     uuid.UUID        `json:"event_id" binding:"required"`
+	Vesting     *VestingSchedule `json:"vesting,omitempty"`
+	RequestedBy string           `json:"requested_by,omitempty"`
+	PartnerID   string           `json:"partner_id,omitempty"`
+	ReceivedAt  time.Time        `json:"received_at,omitempty"`
 }
 
-func (s *RewardService) ProcessReward(ctx context.Context, req RewardRequest) error {
+type ReverseRewardRequest struct {
+	Quantity decimal.Decimal `json:"quantity" binding:"required"`
+	Reason   string          `json:"reason"`
+}
+
+func (s *RewardService) ProcessReward(ctx context.Context, req RewardRequest) (*models.RewardEvent, error) {
+	return s.processReward(ctx, req, nil)
+}
+
+func (s *RewardService) processReward(ctx context.Context, req RewardRequest, onCreate func(tx *sqlx.Tx, reward *models.RewardEvent) error) (*models.RewardEvent, error) {
 	existing, err := s.rewardRepo.GetByEventID(ctx, req.EventID)
 	if err == nil && existing != nil {
 		logrus.WithField("event_id", req.EventID).Info("Reward event already processed (idempotent)")
-		return nil
+		return existing, nil
 	}
 	if err != nil && err != sql.ErrNoRows {
-		return fmt.Errorf("failed to check idempotency: %w", err)
+		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+
+	if req.ReceivedAt.IsZero() {
+		req.ReceivedAt = time.Now()
+	}
+	if err := s.timestampPolicy.Validate(req.Timestamp, req.ReceivedAt); err != nil {
+		return nil, err
+	}
+	if err := s.checkLockedPeriod(ctx, req.Timestamp); err != nil {
+		return nil, err
 	}
 
-	_, err = s.userRepo.GetOrCreate(ctx, req.UserID)
+	var tranches []*models.VestingTranche
+	if req.Vesting != nil {
+		tranches, err = buildVestingTranches(req.Vesting, req.EventID, req.Quantity, req.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var partnerID *string
+	if req.PartnerID != "" {
+		partnerID = &req.PartnerID
+	}
+	user, err := s.userRepo.GetOrCreate(ctx, req.UserID, partnerID)
 	if err != nil {
-		return fmt.Errorf("failed to get/create user: %w", err)
+		return nil, fmt.Errorf("failed to get/create user: %w", err)
+	}
+	if partnerID != nil && *user.PartnerID != *partnerID {
+		return nil, fmt.Errorf("%w: %s", ErrPartnerMismatch, *user.PartnerID)
 	}
 
-	stockPrice, err := s.priceRepo.GetLatest(ctx, req.StockSymbol)
+	stockPrice, err := s.priceAt(ctx, req.StockSymbol, req.Timestamp)
 	if err != nil {
-		return fmt.Errorf("failed to get stock price for %s: %w", req.StockSymbol, err)
+		return nil, err
 	}
 
-	totalFees := fees.CalculateFees(stockPrice.Price, req.Quantity)
-	transactionValue := stockPrice.Price.Mul(req.Quantity)
-	totalCost := transactionValue.Add(totalFees)
+	value := stockPrice.Price.Mul(req.Quantity)
+	requiresApproval := s.requiresApproval(value)
+	if requiresApproval && req.RequestedBy == "" {
+		return nil, fmt.Errorf("%w: rewards worth more than %s INR need approval", ErrOperatorRequired, s.approvalPolicy.ThresholdINR)
+	}
 
 	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
 	reward := &models.RewardEvent{
-		ID:          uuid.New(),
-		EventID:     req.EventID,
-		UserID:      req.UserID,
-		StockSymbol: req.StockSymbol,
-		Quantity:    req.Quantity,
-		Timestamp:   req.Timestamp,
-		CreatedAt:   time.Now(),
+		ID:           uuid.New(),
+		EventID:      req.EventID,
+		UserID:       req.UserID,
+		StockSymbol:  req.StockSymbol,
+		Quantity:     req.Quantity,
+		BookingPrice: &stockPrice.Price,
+		PricedAt:     &stockPrice.FetchedAt,
+		Status:       models.RewardStatusBooked,
+		Timestamp:    req.Timestamp,
+		ReceivedAt:   &req.ReceivedAt,
+		CreatedAt:    time.Now(),
+	}
+
+	if requiresApproval {
+		reward.Status = models.RewardStatusPendingApproval
+	} else {
+		reward.BookedAt = &reward.CreatedAt
 	}
 
 	if err := s.rewardRepo.Create(ctx, tx, reward); err != nil {
-		return fmt.Errorf("failed to create reward: %w", err)
-	}
-
-	entries := []*models.LedgerEntry{
-		{
-			ID:        uuid.New(),
-			EventID:   req.EventID,
-			EntryType: models.LedgerEntryTypeStock,
-			Symbol:    &req.StockSymbol,
-			Debit:     decimal.Zero,
-			Credit:    transactionValue,
-			CreatedAt: time.Now(),
-		},
-		{
-			ID:        uuid.New(),
-			EventID:   req.EventID,
-			EntryType: models.LedgerEntryTypeCash,
-			Symbol:    nil,
-			Debit:     totalCost,
-			Credit:    decimal.Zero,
-			CreatedAt: time.Now(),
-		},
-		{
-			ID:        uuid.New(),
-			EventID:   req.EventID,
-			EntryType: models.LedgerEntryTypeFee,
-			Symbol:    nil,
-			Debit:     totalFees,
-			Credit:    decimal.Zero,
-			CreatedAt: time.Now(),
-		},
-	}
-
-	for _, entry := range entries {
-		if err := s.ledgerRepo.Create(ctx, tx, entry); err != nil {
-			return fmt.Errorf("failed to create ledger entry: %w", err)
+		return nil, fmt.Errorf("failed to create reward: %w", err)
+	}
+
+	for _, tranche := range tranches {
+		if err := s.vestingRepo.CreateTranche(ctx, tx, tranche); err != nil {
+			return nil, fmt.Errorf("failed to create vesting tranche: %w", err)
 		}
 	}
 
-	totalDebit := decimal.Zero
-	totalCredit := decimal.Zero
-	for _, entry := range entries {
-		totalDebit = totalDebit.Add(entry.Debit)
-		totalCredit = totalCredit.Add(entry.Credit)
+	if err := recordRewardEvent(ctx, tx, s.outboxRepo, models.OutboxEventRewardCreated, reward, ""); err != nil {
+		return nil, err
 	}
 
-	if !totalDebit.Equal(totalCredit) {
-		return fmt.Errorf("ledger imbalance: debit=%s, credit=%s", totalDebit, totalCredit)
+	if requiresApproval {
+		approval := &models.RewardApproval{
+			ID:          uuid.New(),
+			EventID:     req.EventID,
+			Status:      models.ApprovalStatusPending,
+			ValueINR:    value,
+			RequestedBy: req.RequestedBy,
+			ExpiresAt:   time.Now().Add(s.approvalPolicy.Expiry),
+			CreatedAt:   time.Now(),
+		}
+		if err := s.approvalRepo.Create(ctx, tx, approval); err != nil {
+			return nil, fmt.Errorf("failed to create approval request: %w", err)
+		}
+	} else if err := s.bookReward(ctx, tx, reward); err != nil {
+		return nil, err
+	}
+
+	if onCreate != nil {
+		if err := onCreate(tx, reward); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	logrus.WithFields(logrus.Fields{
@@ -147,10 +204,210 @@ func (s *RewardService) ProcessReward(ctx context.Context, req RewardRequest) er
 		"user_id":      req.UserID,
 		"stock_symbol": req.StockSymbol,
 		"quantity":     req.Quantity,
-		"total_cost":   totalCost,
-		"fees":         totalFees,
+		"value":        value,
+		"status":       reward.Status,
+		"tranches":     len(tranches),
 	}).Info("Reward processed successfully")
 
+	if !requiresApproval {
+		s.replenishInventory(ctx, reward.StockSymbol)
+	}
+
+	return reward, nil
+}
+
+func (s *RewardService) requiresApproval(value decimal.Decimal) bool {
+	return s.approvalPolicy.ThresholdINR.IsPositive() && value.GreaterThan(s.approvalPolicy.ThresholdINR)
+}
+
+func (s *RewardService) checkLockedPeriod(ctx context.Context, eventTime time.Time) error {
+	period, err := accountingPeriodOf(eventTime)
+	if err != nil {
+		return err
+	}
+	p, err := s.periodRepo.GetByPeriod(ctx, period)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get accounting period: %w", err)
+	}
+	if p.Status == models.PeriodStatusLocked {
+		return fmt.Errorf("%w: timestamp %s falls in locked period %s", ErrPeriodClosed, eventTime.Format(time.RFC3339), period)
+	}
 	return nil
 }
 
+func (s *RewardService) priceAt(ctx context.Context, symbol string, eventTime time.Time) (*models.StockPrice, error) {
+	price, err := s.priceRepo.GetLatest(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s: %v", ErrPriceUnavailable, symbol, err)
+	}
+	if !eventTime.Before(price.FetchedAt) {
+		return price, nil
+	}
+
+	historical, err := s.priceRepo.GetHistoricalPrices(ctx, symbol, eventTime)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s at %s: %v", ErrPriceUnavailable, symbol, eventTime.Format(time.RFC3339), err)
+	}
+	return historical, nil
+}
+
+func (s *RewardService) bookReward(ctx context.Context, tx *sqlx.Tx, reward *models.RewardEvent) error {
+	allocated, err := s.inventoryService.allocate(ctx, tx, reward.StockSymbol, reward.Quantity, reward.EventID)
+	if err != nil {
+		return err
+	}
+	if allocated {
+		if err := s.bookFromInventory(ctx, tx, reward); err != nil {
+			return err
+		}
+		return recordRewardEvent(ctx, tx, s.outboxRepo, models.OutboxEventRewardBooked, reward, "")
+	}
+
+	price := *reward.BookingPrice
+	feeBreakdown := fees.CalculateFees(price, reward.Quantity)
+	transactionValue := price.Mul(reward.Quantity)
+
+	entries := withUser(ledgerTransfer(reward.EventID, models.LedgerEntryTypeRewardExpense, models.LedgerEntryTypeStock, &reward.StockSymbol, transactionValue), reward.UserID)
+	entries = withHolding(entries, reward.Quantity, reward.Timestamp)
+	entries = append(entries, feeEntries(reward.EventID, feeBreakdown, true)...)
+	entries = append(entries, &models.LedgerEntry{
+		ID:        uuid.New(),
+		EventID:   reward.EventID,
+		EntryType: models.LedgerEntryTypeSettlement,
+		Debit:     decimal.Zero,
+		Credit:    feeBreakdown.Total(),
+		CreatedAt: time.Now(),
+	})
+
+	if err := postLedgerEntries(ctx, tx, s.ledgerRepo, entries); err != nil {
+		return err
+	}
+	return recordRewardEvent(ctx, tx, s.outboxRepo, models.OutboxEventRewardBooked, reward, "")
+}
+
+func (s *RewardService) bookFromInventory(ctx context.Context, tx *sqlx.Tx, reward *models.RewardEvent) error {
+	now := time.Now()
+	reward.Status = models.RewardStatusSettled
+	reward.SettledAt = &now
+	if err := s.rewardRepo.UpdateStatus(ctx, tx, reward); err != nil {
+		return fmt.Errorf("failed to update reward status: %w", err)
+	}
+
+	value := reward.BookingPrice.Mul(reward.Quantity)
+	entries := withUser(ledgerTransfer(reward.EventID, models.LedgerEntryTypeRewardExpense, models.LedgerEntryTypeStock, &reward.StockSymbol, value), reward.UserID)
+	return postLedgerEntries(ctx, tx, s.ledgerRepo, withHolding(entries, reward.Quantity, reward.Timestamp))
+}
+
+func (s *RewardService) replenishInventory(ctx context.Context, symbol string) {
+	if err := s.inventoryService.ReplenishIfNeeded(ctx, symbol); err != nil {
+		logrus.WithError(err).WithField("symbol", symbol).Error("Failed to replenish inventory")
+	}
+}
+
+func (s *RewardService) ReverseReward(ctx context.Context, eventID uuid.UUID, req ReverseRewardRequest) (*models.RewardReversal, error) {
+	if req.Quantity.LessThanOrEqual(decimal.Zero) {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInsufficientUnvested)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	reward, err := s.rewardRepo.GetByEventIDForUpdate(ctx, tx, eventID)
+	if err == sql.ErrNoRows {
+		return nil, ErrRewardNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reward: %w", err)
+	}
+	if !reward.Status.Held() {
+		return nil, fmt.Errorf("%w: status is %s", ErrRewardNotBooked, reward.Status)
+	}
+	if reward.BookingPrice == nil {
+		return nil, fmt.Errorf("reward %s has no booking price", eventID)
+	}
+
+	tranches, err := s.vestingRepo.GetUnvestedByEventIDForUpdate(ctx, tx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unvested tranches: %w", err)
+	}
+
+	unvested := decimal.Zero
+	for _, t := range tranches {
+		unvested = unvested.Add(t.Outstanding())
+	}
+	if req.Quantity.GreaterThan(unvested) {
+		return nil, fmt.Errorf("%w: requested=%s, unvested=%s", ErrInsufficientUnvested, req.Quantity, unvested)
+	}
+
+	remaining := req.Quantity
+	for _, t := range tranches {
+		if remaining.IsZero() {
+			break
+		}
+		take := decimal.Min(remaining, t.Outstanding())
+		if take.IsZero() {
+			continue
+		}
+		if err := s.vestingRepo.AddReversedQuantity(ctx, tx, t.ID, take); err != nil {
+			return nil, fmt.Errorf("failed to update vesting tranche: %w", err)
+		}
+		remaining = remaining.Sub(take)
+	}
+
+	reversal := &models.RewardReversal{
+		ID:        uuid.New(),
+		EventID:   eventID,
+		Quantity:  req.Quantity,
+		Reason:    req.Reason,
+		CreatedAt: time.Now(),
+	}
+	if err := s.rewardRepo.CreateReversal(ctx, tx, reversal); err != nil {
+		return nil, fmt.Errorf("failed to create reversal: %w", err)
+	}
+
+	reversalValue := reward.BookingPrice.Mul(req.Quantity)
+	if reward.Status == models.RewardStatusSettled {
+		if err := s.inventoryService.addToPool(ctx, tx, reward.StockSymbol, req.Quantity, reversalValue, models.LedgerEntryTypeCustody, models.InventoryMovementReversal, reversal.ID); err != nil {
+			return nil, err
+		}
+	}
+	entries := withUser(ledgerTransfer(eventID, models.LedgerEntryTypeStock, models.LedgerEntryTypeRewardExpense, &reward.StockSymbol, reversalValue), reward.UserID)
+	entries = withHolding(entries, req.Quantity, reversal.CreatedAt)
+
+	if err := postLedgerEntries(ctx, tx, s.ledgerRepo, entries); err != nil {
+		return nil, err
+	}
+
+	payload := RewardReversedPayload{
+		EventID:     eventID,
+		UserID:      reward.UserID,
+		StockSymbol: reward.StockSymbol,
+		ReversalID:  reversal.ID,
+		Quantity:    req.Quantity,
+		Value:       reversalValue,
+		Reason:      req.Reason,
+	}
+	if err := recordOutboxEvent(ctx, tx, s.outboxRepo, models.OutboxEventRewardReversed, "reward", eventID.String(), reward.UserID.String(), payload); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event_id":     eventID,
+		"user_id":      reward.UserID,
+		"stock_symbol": reward.StockSymbol,
+		"quantity":     req.Quantity,
+		"value":        reversalValue,
+	}).Info("Reward reversed")
+
+	return reversal, nil
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+type SchedulePolicy struct {
+	MaxHorizon        time.Duration
+	BatchSize         int
+	VisibilityTimeout time.Duration
+}
+
+type ScheduledRewardService struct {
+	scheduleRepo  *repository.ScheduledRewardRepository
+	rewardRepo    *repository.RewardRepository
+	rewardService *RewardService
+	rewardQueue   *RewardQueueService
+	deadLetters   *DeadLetterService
+	policy        SchedulePolicy
+}
+
+func NewScheduledRewardService(
+	scheduleRepo *repository.ScheduledRewardRepository,
+	rewardRepo *repository.RewardRepository,
+	rewardService *RewardService,
+	rewardQueue *RewardQueueService,
+	deadLetters *DeadLetterService,
+	policy SchedulePolicy,
+) *ScheduledRewardService {
+	return &ScheduledRewardService{
+		scheduleRepo:  scheduleRepo,
+		rewardRepo:    rewardRepo,
+		rewardService: rewardService,
+		rewardQueue:   rewardQueue,
+		deadLetters:   deadLetters,
+		policy:        policy,
+	}
+}
+
+type ScheduleRewardRequest struct {
+	UserID       uuid.UUID        `json:"user_id" binding:"required"`
+	StockSymbol  string           `json:"stock_symbol" binding:"required"`
+	Quantity     decimal.Decimal  `json:"quantity" binding:"required"`
+	EventID      uuid.UUID        `json:"event_id" binding:"required"`
+	ScheduledFor time.Time        `json:"scheduled_for" binding:"required"`
+	Vesting      *VestingSchedule `json:"vesting,omitempty"`
+	RequestedBy  string           `json:"requested_by,omitempty"`
+	PartnerID    string           `json:"partner_id,omitempty"`
+}
+
+type ScheduledRewardResponse struct {
+	EventID      uuid.UUID                    `json:"event_id"`
+	Status       models.ScheduledRewardStatus `json:"status"`
+	ScheduledFor time.Time                    `json:"scheduled_for"`
+	ExecutedAt   *time.Time                   `json:"executed_at,omitempty"`
+	CancelledAt  *time.Time                   `json:"cancelled_at,omitempty"`
+	CancelledBy  *string                      `json:"cancelled_by,omitempty"`
+	LastError    *string                      `json:"last_error,omitempty"`
+}
+
+func newScheduledRewardResponse(s *models.ScheduledReward) *ScheduledRewardResponse {
+	return &ScheduledRewardResponse{
+		EventID:      s.EventID,
+		Status:       s.Status,
+		ScheduledFor: s.ScheduledFor,
+		ExecutedAt:   s.ExecutedAt,
+		CancelledAt:  s.CancelledAt,
+		CancelledBy:  s.CancelledBy,
+		LastError:    s.LastError,
+	}
+}
+
+func (s *ScheduledRewardService) Schedule(ctx context.Context, req ScheduleRewardRequest) (*ScheduledRewardResponse, error) {
+	now := time.Now()
+	if !req.ScheduledFor.After(now) {
+		return nil, fmt.Errorf("%w: scheduled_for must be in the future", ErrInvalidSchedule)
+	}
+	if s.policy.MaxHorizon > 0 && req.ScheduledFor.After(now.Add(s.policy.MaxHorizon)) {
+		return nil, fmt.Errorf("%w: scheduled_for is more than %s ahead", ErrInvalidSchedule, s.policy.MaxHorizon)
+	}
+	if !req.Quantity.IsPositive() {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidSchedule)
+	}
+	if req.Vesting != nil {
+		if _, err := buildVestingTranches(req.Vesting, req.EventID, req.Quantity, req.ScheduledFor); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.rewardRepo.GetByEventID(ctx, req.EventID); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRewardAlreadyExists, req.EventID)
+	} else if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to check existing reward: %w", err)
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode scheduled reward: %w", err)
+	}
+
+	scheduled := &models.ScheduledReward{
+		ID:           uuid.New(),
+		EventID:      req.EventID,
+		UserID:       req.UserID,
+		StockSymbol:  req.StockSymbol,
+		Quantity:     req.Quantity,
+		Payload:      payload,
+		ScheduledFor: req.ScheduledFor,
+		Status:       models.ScheduledRewardStatusScheduled,
+		CreatedAt:    now,
+	}
+	inserted, err := s.scheduleRepo.Create(ctx, scheduled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule reward: %w", err)
+	}
+	if inserted {
+		logrus.WithFields(logrus.Fields{
+			"event_id":      req.EventID,
+			"user_id":       req.UserID,
+			"stock_symbol":  req.StockSymbol,
+			"scheduled_for": req.ScheduledFor,
+		}).Info("Reward scheduled")
+	}
+
+	return s.get(ctx, req.EventID)
+}
+
+func (s *ScheduledRewardService) Cancel(ctx context.Context, eventID uuid.UUID, operator string) (*ScheduledRewardResponse, error) {
+	var cancelledBy *string
+	if operator != "" {
+		cancelledBy = &operator
+	}
+
+	cancelled, err := s.scheduleRepo.Cancel(ctx, eventID, cancelledBy, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel scheduled reward: %w", err)
+	}
+
+	resp, err := s.get(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, fmt.Errorf("%w: status is %s", ErrRewardNotScheduled, resp.Status)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event_id":     eventID,
+		"cancelled_by": operator,
+	}).Info("Scheduled reward cancelled")
+	return resp, nil
+}
+
+func (s *ScheduledRewardService) ExecuteDue(ctx context.Context) error {
+	now := time.Now()
+	due, err := s.scheduleRepo.ClaimDue(ctx, now, now.Add(-s.policy.VisibilityTimeout), s.policy.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to claim scheduled rewards: %w", err)
+	}
+
+	for i := range due {
+		if err := s.execute(ctx, &due[i]); err != nil {
+			logrus.WithError(err).WithField("event_id", due[i].EventID).Error("Failed to record scheduled reward result")
+		}
+	}
+	return nil
+}
+
+func (s *ScheduledRewardService) execute(ctx context.Context, scheduled *models.ScheduledReward) error {
+	var req ScheduleRewardRequest
+	if err := json.Unmarshal(scheduled.Payload, &req); err != nil {
+		return s.fail(ctx, scheduled, RewardRequest{EventID: scheduled.EventID}, fmt.Errorf("%w: %v", ErrInvalidPayload, err))
+	}
+
+	executedAt := time.Now()
+	rewardReq := RewardRequest{
+		UserID:      req.UserID,
+		StockSymbol: req.StockSymbol,
+		Quantity:    req.Quantity,
+		Timestamp:   executedAt,
+		EventID:     req.EventID,
+		Vesting:     req.Vesting,
+		RequestedBy: req.RequestedBy,
+		PartnerID:   req.PartnerID,
+		ReceivedAt:  executedAt,
+	}
+
+	if _, err := s.rewardQueue.Enqueue(ctx, rewardReq); err != nil {
+		if isPermanentRewardError(err) {
+			return s.fail(ctx, scheduled, rewardReq, err)
+		}
+		return s.retry(ctx, scheduled, err)
+	}
+
+	scheduled.Status = models.ScheduledRewardStatusExecuted
+	scheduled.ExecutedAt = &executedAt
+	scheduled.LastError = nil
+	logrus.WithFields(logrus.Fields{
+		"event_id":      scheduled.EventID,
+		"scheduled_for": scheduled.ScheduledFor,
+	}).Info("Scheduled reward queued")
+	return s.scheduleRepo.Complete(ctx, scheduled)
+}
+
+func (s *ScheduledRewardService) retry(ctx context.Context, scheduled *models.ScheduledReward, cause error) error {
+	errMsg := cause.Error()
+	scheduled.Status = models.ScheduledRewardStatusScheduled
+	scheduled.ExecutedAt = nil
+	scheduled.LastError = &errMsg
+	logrus.WithError(cause).WithField("event_id", scheduled.EventID).Warn("Scheduled reward could not be queued, will retry")
+	return s.scheduleRepo.Complete(ctx, scheduled)
+}
+
+func (s *ScheduledRewardService) fail(ctx context.Context, scheduled *models.ScheduledReward, req RewardRequest, cause error) error {
+	now := time.Now()
+	errMsg := cause.Error()
+	scheduled.Status = models.ScheduledRewardStatusFailed
+	scheduled.ExecutedAt = &now
+	scheduled.LastError = &errMsg
+	logrus.WithError(cause).WithField("event_id", scheduled.EventID).Error("Scheduled reward failed")
+
+	if err := s.deadLetters.CaptureRequest(ctx, models.DeadLetterSourceSchedule, req, 1, cause); err != nil {
+		logrus.WithError(err).WithField("event_id", scheduled.EventID).Error("Failed to dead-letter scheduled reward")
+	}
+	return s.scheduleRepo.Complete(ctx, scheduled)
+}
+
+func (s *ScheduledRewardService) get(ctx context.Context, eventID uuid.UUID) (*ScheduledRewardResponse, error) {
+	scheduled, err := s.scheduleRepo.GetByEventID(ctx, eventID)
+	if err == sql.ErrNoRows {
+		return nil, ErrRewardNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled reward: %w", err)
+	}
+	return newScheduledRewardResponse(scheduled), nil
+}
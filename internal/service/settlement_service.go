@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+	"stocky/pkg/fees"
+)
+
+type SettlementService struct {
+	rewardRepo       *repository.RewardRepository
+	ledgerRepo       *repository.LedgerRepository
+	inventoryService *InventoryService
+	outboxRepo       *repository.OutboxRepository
+	db               *sqlx.DB
+}
+
+func NewSettlementService(
+	rewardRepo *repository.RewardRepository,
+	ledgerRepo *repository.LedgerRepository,
+	inventoryService *InventoryService,
+	outboxRepo *repository.OutboxRepository,
+	db *sqlx.DB,
+) *SettlementService {
+	return &SettlementService{
+		rewardRepo:       rewardRepo,
+		ledgerRepo:       ledgerRepo,
+		inventoryService: inventoryService,
+		outboxRepo:       outboxRepo,
+		db:               db,
+	}
+}
+
+type FailSettlementRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type SettlementResponse struct {
+	EventID         uuid.UUID           `json:"event_id"`
+	Status          models.RewardStatus `json:"status"`
+	BookedAt        *time.Time          `json:"booked_at,omitempty"`
+	OrderedAt       *time.Time          `json:"ordered_at,omitempty"`
+	SettlementDueAt *time.Time          `json:"settlement_due_at,omitempty"`
+	SettledAt       *time.Time          `json:"settled_at,omitempty"`
+	FailedAt        *time.Time          `json:"failed_at,omitempty"`
+	FailureReason   *string             `json:"failure_reason,omitempty"`
+}
+
+func newSettlementResponse(r *models.RewardEvent) *SettlementResponse {
+	return &SettlementResponse{
+		EventID:         r.EventID,
+		Status:          r.Status,
+		BookedAt:        r.BookedAt,
+		OrderedAt:       r.OrderedAt,
+		SettlementDueAt: r.SettlementDueAt,
+		SettledAt:       r.SettledAt,
+		FailedAt:        r.FailedAt,
+		FailureReason:   r.FailureReason,
+	}
+}
+
+func (s *SettlementService) MarkOrdered(ctx context.Context, eventID uuid.UUID) (*SettlementResponse, error) {
+	return s.transition(ctx, eventID, s.markOrdered)
+}
+
+func (s *SettlementService) MarkSettled(ctx context.Context, eventID uuid.UUID) (*SettlementResponse, error) {
+	return s.transition(ctx, eventID, func(ctx context.Context, tx *sqlx.Tx, reward *models.RewardEvent, value decimal.Decimal) ([]*models.LedgerEntry, error) {
+		if reward.Status != models.RewardStatusOrdered {
+			return nil, fmt.Errorf("%w: cannot settle reward in status %s", ErrInvalidTransition, reward.Status)
+		}
+
+		reversed, err := s.reversedSinceOrdered(ctx, tx, reward)
+		if err != nil {
+			return nil, err
+		}
+		if reversed.IsPositive() {
+			if err := s.inventoryService.addToPool(ctx, tx, reward.StockSymbol, reversed, reward.BookingPrice.Mul(reversed), models.LedgerEntryTypeSettlement, models.InventoryMovementReversal, reward.EventID); err != nil {
+				return nil, err
+			}
+		}
+
+		now := time.Now()
+		reward.Status = models.RewardStatusSettled
+		reward.SettledAt = &now
+		return ledgerTransfer(reward.EventID, models.LedgerEntryTypeCustody, models.LedgerEntryTypeSettlement, &reward.StockSymbol, value), nil
+	})
+}
+
+func (s *SettlementService) MarkFailed(ctx context.Context, eventID uuid.UUID, req FailSettlementRequest) (*SettlementResponse, error) {
+	return s.transition(ctx, eventID, s.markFailed(req.Reason))
+}
+
+func (s *SettlementService) markFailed(reason string) transitionFunc {
+	return func(ctx context.Context, tx *sqlx.Tx, reward *models.RewardEvent, value decimal.Decimal) ([]*models.LedgerEntry, error) {
+		if reward.Status != models.RewardStatusBooked && reward.Status != models.RewardStatusOrdered {
+			return nil, fmt.Errorf("%w: cannot fail reward in status %s", ErrInvalidTransition, reward.Status)
+		}
+		if err := s.rewardRepo.LockHolding(ctx, tx, reward.UserID, reward.StockSymbol); err != nil {
+			return nil, fmt.Errorf("failed to lock holding: %w", err)
+		}
+
+		var entries []*models.LedgerEntry
+		if reward.Status == models.RewardStatusOrdered {
+			reversed, err := s.reversedSinceOrdered(ctx, tx, reward)
+			if err != nil {
+				return nil, err
+			}
+			ordered := value.Add(reward.BookingPrice.Mul(reversed))
+			entries = append(entries, ledgerTransfer(reward.EventID, models.LedgerEntryTypeCash, models.LedgerEntryTypeSettlement, &reward.StockSymbol, ordered.Add(bookingFees(reward).Total()))...)
+		}
+		entries = append(entries, withUser(ledgerTransfer(reward.EventID, models.LedgerEntryTypeStock, models.LedgerEntryTypeRewardExpense, &reward.StockSymbol, value), reward.UserID)...)
+		entries = append(entries, releasedFeeEntries(reward)...)
+
+		now := time.Now()
+		reward.Status = models.RewardStatusFailed
+		reward.FailedAt = &now
+		reward.FailureReason = &reason
+		return entries, nil
+	}
+}
+
+func (s *SettlementService) SettleDue(ctx context.Context) error {
+	eventIDs, err := s.rewardRepo.ListDueForSettlement(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to list rewards due for settlement: %w", err)
+	}
+
+	for _, eventID := range eventIDs {
+		if _, err := s.MarkSettled(ctx, eventID); err != nil {
+			logrus.WithError(err).WithField("event_id", eventID).Error("Failed to settle reward")
+		}
+	}
+	return nil
+}
+
+func (s *SettlementService) markOrdered(ctx context.Context, tx *sqlx.Tx, reward *models.RewardEvent, value decimal.Decimal) ([]*models.LedgerEntry, error) {
+	if reward.Status != models.RewardStatusBooked {
+		return nil, fmt.Errorf("%w: cannot order reward in status %s", ErrInvalidTransition, reward.Status)
+	}
+
+	now := time.Now()
+	due := settlementDate(now)
+	reward.Status = models.RewardStatusOrdered
+	reward.OrderedAt = &now
+	reward.SettlementDueAt = &due
+	return ledgerTransfer(reward.EventID, models.LedgerEntryTypeSettlement, models.LedgerEntryTypeCash, &reward.StockSymbol, value.Add(bookingFees(reward).Total())), nil
+}
+
+func (s *SettlementService) markReversed(ctx context.Context, tx *sqlx.Tx, reward *models.RewardEvent, value decimal.Decimal) ([]*models.LedgerEntry, error) {
+	if reward.Status != models.RewardStatusBooked || !value.IsZero() {
+		return nil, fmt.Errorf("%w: reward %s still has shares to order", ErrInvalidTransition, reward.EventID)
+	}
+
+	now := time.Now()
+	reward.Status = models.RewardStatusSettled
+	reward.SettledAt = &now
+	return releasedFeeEntries(reward), nil
+}
+
+func (s *SettlementService) reversedSinceOrdered(ctx context.Context, tx *sqlx.Tx, reward *models.RewardEvent) (decimal.Decimal, error) {
+	if reward.OrderedAt == nil {
+		return decimal.Zero, nil
+	}
+	reversed, err := s.rewardRepo.GetReversedQuantitySince(ctx, tx, reward.EventID, *reward.OrderedAt)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get reversed quantity: %w", err)
+	}
+	return reversed, nil
+}
+
+type transitionFunc func(ctx context.Context, tx *sqlx.Tx, reward *models.RewardEvent, value decimal.Decimal) ([]*models.LedgerEntry, error)
+
+func (s *SettlementService) transition(ctx context.Context, eventID uuid.UUID, apply transitionFunc) (*SettlementResponse, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	reward, err := s.rewardRepo.GetByEventIDForUpdate(ctx, tx, eventID)
+	if err == sql.ErrNoRows {
+		return nil, ErrRewardNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reward: %w", err)
+	}
+
+	if err := s.applyTransition(ctx, tx, reward, apply); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return newSettlementResponse(reward), nil
+}
+
+func (s *SettlementService) applyTransition(ctx context.Context, tx *sqlx.Tx, reward *models.RewardEvent, apply transitionFunc) error {
+	if reward.BookingPrice == nil {
+		return fmt.Errorf("reward %s has no booking price", reward.EventID)
+	}
+
+	reversed, err := s.rewardRepo.GetReversedQuantity(ctx, tx, reward.EventID)
+	if err != nil {
+		return fmt.Errorf("failed to get reversed quantity: %w", err)
+	}
+
+	from := reward.Status
+	outstanding := reward.Quantity.Sub(reversed)
+	value := reward.BookingPrice.Mul(outstanding)
+	entries, err := apply(ctx, tx, reward, value)
+	if err != nil {
+		return err
+	}
+	entries = withHolding(entries, outstanding, reward.Timestamp)
+
+	if err := s.rewardRepo.UpdateStatus(ctx, tx, reward); err != nil {
+		return fmt.Errorf("failed to update reward status: %w", err)
+	}
+	entries = nonZeroEntries(entries)
+	if len(entries) > 0 {
+		if err := postLedgerEntries(ctx, tx, s.ledgerRepo, entries); err != nil {
+			return err
+		}
+	}
+	if err := recordRewardEvent(ctx, tx, s.outboxRepo, models.OutboxEventRewardStatusChanged, reward, from); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"event_id": reward.EventID,
+		"from":     from,
+		"to":       reward.Status,
+		"value":    value,
+	}).Info("Reward settlement status changed")
+
+	return nil
+}
+
+func bookingFees(reward *models.RewardEvent) fees.Breakdown {
+	return fees.CalculateFees(*reward.BookingPrice, reward.Quantity)
+}
+
+func releasedFeeEntries(reward *models.RewardEvent) []*models.LedgerEntry {
+	breakdown := bookingFees(reward)
+	entries := []*models.LedgerEntry{{
+		ID:        uuid.New(),
+		EventID:   reward.EventID,
+		EntryType: models.LedgerEntryTypeSettlement,
+		Debit:     breakdown.Total(),
+		Credit:    decimal.Zero,
+		CreatedAt: time.Now(),
+	}}
+	return append(entries, feeEntries(reward.EventID, breakdown, false)...)
+}
+
+func settlementDate(orderedAt time.Time) time.Time {
+	due := orderedAt.AddDate(0, 0, 1)
+	for due.Weekday() == time.Saturday || due.Weekday() == time.Sunday {
+		due = due.AddDate(0, 0, 1)
+	}
+	return due
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+	"stocky/pkg/fees"
+)
+
+type ShareSaleService struct {
+	saleRepo         *repository.ShareSaleRepository
+	rewardRepo       *repository.RewardRepository
+	ledgerRepo       *repository.LedgerRepository
+	rewardService    *RewardService
+	inventoryService *InventoryService
+	outboxRepo       *repository.OutboxRepository
+	db               *sqlx.DB
+}
+
+func NewShareSaleService(
+	saleRepo *repository.ShareSaleRepository,
+	rewardRepo *repository.RewardRepository,
+	ledgerRepo *repository.LedgerRepository,
+	rewardService *RewardService,
+	inventoryService *InventoryService,
+	outboxRepo *repository.OutboxRepository,
+	db *sqlx.DB,
+) *ShareSaleService {
+	return &ShareSaleService{
+		saleRepo:         saleRepo,
+		rewardRepo:       rewardRepo,
+		ledgerRepo:       ledgerRepo,
+		rewardService:    rewardService,
+		inventoryService: inventoryService,
+		outboxRepo:       outboxRepo,
+		db:               db,
+	}
+}
+
+type SellRequest struct {
+	SaleID      uuid.UUID       `json:"sale_id" binding:"required"`
+	UserID      uuid.UUID       `json:"user_id" binding:"required"`
+	StockSymbol string          `json:"stock_symbol" binding:"required"`
+	Quantity    decimal.Decimal `json:"quantity"`
+	All         bool            `json:"all,omitempty"`
+}
+
+type SaleResponse struct {
+	SaleID      uuid.UUID       `json:"sale_id"`
+	UserID      uuid.UUID       `json:"user_id"`
+	StockSymbol string          `json:"stock_symbol"`
+	Quantity    decimal.Decimal `json:"quantity"`
+	Price       decimal.Decimal `json:"price"`
+	GrossINR    decimal.Decimal `json:"gross_inr"`
+	FeesINR     decimal.Decimal `json:"fees_inr"`
+	NetINR      decimal.Decimal `json:"net_inr"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+func newSaleResponse(s *models.ShareSale) *SaleResponse {
+	return &SaleResponse{
+		SaleID:      s.ID,
+		UserID:      s.UserID,
+		StockSymbol: s.StockSymbol,
+		Quantity:    s.Quantity,
+		Price:       s.Price,
+		GrossINR:    s.GrossINR,
+		FeesINR:     s.FeesINR,
+		NetINR:      s.NetINR,
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+type WalletResponse struct {
+	UserID     uuid.UUID       `json:"user_id"`
+	BalanceINR decimal.Decimal `json:"balance_inr"`
+}
+
+func (s *ShareSaleService) Sell(ctx context.Context, req SellRequest) (*SaleResponse, error) {
+	if req.All == req.Quantity.IsPositive() {
+		return nil, fmt.Errorf("%w: specify either a positive quantity or all", ErrInvalidSale)
+	}
+
+	existing, err := s.saleRepo.GetByID(ctx, req.SaleID)
+	if err == nil {
+		return s.replay(existing, req)
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+
+	now := time.Now()
+	stockPrice, err := s.rewardService.priceAt(ctx, req.StockSymbol, now)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.rewardRepo.LockHolding(ctx, tx, req.UserID, req.StockSymbol); err != nil {
+		return nil, fmt.Errorf("failed to lock holding: %w", err)
+	}
+
+	available, err := availableShares(ctx, s.ledgerRepo, s.rewardRepo, req.UserID, req.StockSymbol)
+	if err != nil {
+		return nil, err
+	}
+	quantity := req.Quantity
+	if req.All {
+		quantity = available
+	}
+	if !quantity.IsPositive() || quantity.GreaterThan(available) {
+		return nil, fmt.Errorf("%w: requested=%s, available=%s", ErrInsufficientHoldings, quantity, available)
+	}
+
+	gross := stockPrice.Price.Mul(quantity).Round(4)
+	charges := fees.CalculateBuybackFees(stockPrice.Price, quantity)
+	saleFees := charges.Total()
+	net := gross.Sub(saleFees)
+	if !net.IsPositive() {
+		return nil, fmt.Errorf("%w: proceeds %s do not cover fees %s", ErrInvalidSale, gross, saleFees)
+	}
+
+	sale := &models.ShareSale{
+		ID:          req.SaleID,
+		UserID:      req.UserID,
+		StockSymbol: req.StockSymbol,
+		Quantity:    quantity,
+		Price:       stockPrice.Price,
+		GrossINR:    gross,
+		FeesINR:     saleFees,
+		NetINR:      net,
+		CreatedAt:   now,
+	}
+	inserted, err := s.saleRepo.Create(ctx, tx, sale)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create share sale: %w", err)
+	}
+	if !inserted {
+		tx.Rollback()
+		existing, err := s.saleRepo.GetByID(ctx, req.SaleID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get share sale: %w", err)
+		}
+		return s.replay(existing, req)
+	}
+
+	cost, err := carryingCost(ctx, s.ledgerRepo, sale.UserID, sale.StockSymbol, sale.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.inventoryService.addToPool(ctx, tx, sale.StockSymbol, sale.Quantity, cost, models.LedgerEntryTypeCustody, models.InventoryMovementBuyback, sale.ID); err != nil {
+		return nil, err
+	}
+
+	gainLoss := &models.LedgerEntry{
+		ID:        uuid.New(),
+		EventID:   sale.ID,
+		UserID:    &sale.UserID,
+		EntryType: models.LedgerEntryTypeBuybackGainLoss,
+		Symbol:    &sale.StockSymbol,
+		Debit:     decimal.Zero,
+		Credit:    decimal.Zero,
+		CreatedAt: now,
+	}
+	if difference := gross.Sub(cost); difference.IsPositive() {
+		gainLoss.Debit = difference
+	} else {
+		gainLoss.Credit = difference.Neg()
+	}
+
+	entries := []*models.LedgerEntry{
+		{
+			ID:        uuid.New(),
+			EventID:   sale.ID,
+			UserID:    &sale.UserID,
+			EntryType: models.LedgerEntryTypeStock,
+			Symbol:    &sale.StockSymbol,
+			Debit:     cost,
+			Credit:    decimal.Zero,
+			CreatedAt: now,
+			Quantity:  sale.Quantity,
+		},
+		gainLoss,
+		{
+			ID:        uuid.New(),
+			EventID:   sale.ID,
+			UserID:    &sale.UserID,
+			EntryType: models.LedgerEntryTypeWallet,
+			Symbol:    nil,
+			Debit:     decimal.Zero,
+			Credit:    net,
+			CreatedAt: now,
+		},
+	}
+	for _, charge := range []struct {
+		entryType models.LedgerEntryType
+		amount    decimal.Decimal
+	}{
+		{models.LedgerEntryTypeSaleCharges, charges.Brokerage},
+		{models.LedgerEntryTypeGSTPayable, charges.GST},
+		{models.LedgerEntryTypeChargesPayable, charges.STT.Add(charges.ExchangeCharges).Add(charges.SEBICharges)},
+	} {
+		entries = append(entries, &models.LedgerEntry{
+			ID:        uuid.New(),
+			EventID:   sale.ID,
+			UserID:    &sale.UserID,
+			EntryType: charge.entryType,
+			Debit:     decimal.Zero,
+			Credit:    charge.amount,
+			CreatedAt: now,
+		})
+	}
+	if err := postLedgerEntries(ctx, tx, s.ledgerRepo, entries); err != nil {
+		return nil, err
+	}
+
+	payload := SharesSoldPayload{
+		SaleID:      sale.ID,
+		UserID:      sale.UserID,
+		StockSymbol: sale.StockSymbol,
+		Quantity:    sale.Quantity,
+		Price:       sale.Price,
+		Fees:        sale.FeesINR,
+		NetAmount:   sale.NetINR,
+	}
+	if err := recordOutboxEvent(ctx, tx, s.outboxRepo, models.OutboxEventSharesSold, "sale", sale.ID.String(), sale.UserID.String(), payload); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"sale_id":      sale.ID,
+		"user_id":      sale.UserID,
+		"stock_symbol": sale.StockSymbol,
+		"quantity":     sale.Quantity,
+		"net_inr":      sale.NetINR,
+	}).Info("Shares sold")
+
+	return newSaleResponse(sale), nil
+}
+
+func (s *ShareSaleService) ListSales(ctx context.Context, userID uuid.UUID) ([]*SaleResponse, error) {
+	sales, err := s.saleRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share sales: %w", err)
+	}
+
+	result := make([]*SaleResponse, len(sales))
+	for i := range sales {
+		result[i] = newSaleResponse(&sales[i])
+	}
+	return result, nil
+}
+
+func (s *ShareSaleService) GetWallet(ctx context.Context, userID uuid.UUID) (*WalletResponse, error) {
+	balance, err := s.ledgerRepo.GetWalletBalance(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+	return &WalletResponse{UserID: userID, BalanceINR: balance}, nil
+}
+
+func (s *ShareSaleService) replay(existing *models.ShareSale, req SellRequest) (*SaleResponse, error) {
+	if existing.UserID != req.UserID || existing.StockSymbol != req.StockSymbol ||
+		(!req.All && !existing.Quantity.Equal(req.Quantity)) {
+		return nil, fmt.Errorf("%w: %s", ErrSaleConflict, req.SaleID)
+	}
+	logrus.WithField("sale_id", req.SaleID).Info("Share sale already processed (idempotent)")
+	return newSaleResponse(existing), nil
+}
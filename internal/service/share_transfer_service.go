@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+type ShareTransferService struct {
+	transferRepo  *repository.ShareTransferRepository
+	rewardRepo    *repository.RewardRepository
+	userRepo      *repository.UserRepository
+	ledgerRepo    *repository.LedgerRepository
+	rewardService *RewardService
+	outboxRepo    *repository.OutboxRepository
+	db            *sqlx.DB
+}
+
+func NewShareTransferService(
+	transferRepo *repository.ShareTransferRepository,
+	rewardRepo *repository.RewardRepository,
+	userRepo *repository.UserRepository,
+	ledgerRepo *repository.LedgerRepository,
+	rewardService *RewardService,
+	outboxRepo *repository.OutboxRepository,
+	db *sqlx.DB,
+) *ShareTransferService {
+	return &ShareTransferService{
+		transferRepo:  transferRepo,
+		rewardRepo:    rewardRepo,
+		userRepo:      userRepo,
+		ledgerRepo:    ledgerRepo,
+		rewardService: rewardService,
+		outboxRepo:    outboxRepo,
+		db:            db,
+	}
+}
+
+type TransferRequest struct {
+	TransferID  uuid.UUID       `json:"transfer_id" binding:"required"`
+	FromUserID  uuid.UUID       `json:"from_user_id" binding:"required"`
+	ToUserID    uuid.UUID       `json:"to_user_id" binding:"required"`
+	StockSymbol string          `json:"stock_symbol" binding:"required"`
+	Quantity    decimal.Decimal `json:"quantity" binding:"required"`
+	Note        string          `json:"note,omitempty"`
+}
+
+type TransferResponse struct {
+	TransferID  uuid.UUID       `json:"transfer_id"`
+	FromUserID  uuid.UUID       `json:"from_user_id"`
+	ToUserID    uuid.UUID       `json:"to_user_id"`
+	StockSymbol string          `json:"stock_symbol"`
+	Quantity    decimal.Decimal `json:"quantity"`
+	Price       decimal.Decimal `json:"price"`
+	ValueINR    decimal.Decimal `json:"value_inr"`
+	Note        *string         `json:"note,omitempty"`
+	Direction   string          `json:"direction,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+func newTransferResponse(t *models.ShareTransfer) *TransferResponse {
+	return &TransferResponse{
+		TransferID:  t.ID,
+		FromUserID:  t.FromUserID,
+		ToUserID:    t.ToUserID,
+		StockSymbol: t.StockSymbol,
+		Quantity:    t.Quantity,
+		Price:       t.Price,
+		ValueINR:    t.ValueINR,
+		Note:        t.Note,
+		CreatedAt:   t.CreatedAt,
+	}
+}
+
+func (s *ShareTransferService) Transfer(ctx context.Context, req TransferRequest) (*TransferResponse, error) {
+	if !req.Quantity.IsPositive() {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidTransfer)
+	}
+	if req.FromUserID == req.ToUserID {
+		return nil, fmt.Errorf("%w: sender and recipient must differ", ErrInvalidTransfer)
+	}
+
+	existing, err := s.transferRepo.GetByID(ctx, req.TransferID)
+	if err == nil {
+		return s.replay(existing, req)
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+
+	if _, err := s.userRepo.GetOrCreate(ctx, req.ToUserID, nil); err != nil {
+		return nil, fmt.Errorf("failed to get/create user: %w", err)
+	}
+
+	now := time.Now()
+	stockPrice, err := s.rewardService.priceAt(ctx, req.StockSymbol, now)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.rewardRepo.LockHolding(ctx, tx, req.FromUserID, req.StockSymbol); err != nil {
+		return nil, fmt.Errorf("failed to lock holding: %w", err)
+	}
+
+	available, err := availableShares(ctx, s.ledgerRepo, s.rewardRepo, req.FromUserID, req.StockSymbol)
+	if err != nil {
+		return nil, err
+	}
+	if req.Quantity.GreaterThan(available) {
+		return nil, fmt.Errorf("%w: requested=%s, available=%s", ErrInsufficientHoldings, req.Quantity, available)
+	}
+
+	transfer := &models.ShareTransfer{
+		ID:          req.TransferID,
+		FromUserID:  req.FromUserID,
+		ToUserID:    req.ToUserID,
+		StockSymbol: req.StockSymbol,
+		Quantity:    req.Quantity,
+		Price:       stockPrice.Price,
+		ValueINR:    stockPrice.Price.Mul(req.Quantity).Round(4),
+		CreatedAt:   now,
+	}
+	if req.Note != "" {
+		transfer.Note = &req.Note
+	}
+
+	inserted, err := s.transferRepo.Create(ctx, tx, transfer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create share transfer: %w", err)
+	}
+	if !inserted {
+		tx.Rollback()
+		existing, err := s.transferRepo.GetByID(ctx, req.TransferID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get share transfer: %w", err)
+		}
+		return s.replay(existing, req)
+	}
+
+	cost, err := carryingCost(ctx, s.ledgerRepo, transfer.FromUserID, transfer.StockSymbol, transfer.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := ledgerTransfer(transfer.ID, models.LedgerEntryTypeStock, models.LedgerEntryTypeStock, &transfer.StockSymbol, cost)
+	entries[0].UserID = &transfer.FromUserID
+	entries[1].UserID = &transfer.ToUserID
+	if err := postLedgerEntries(ctx, tx, s.ledgerRepo, withHolding(entries, transfer.Quantity, transfer.CreatedAt)); err != nil {
+		return nil, err
+	}
+
+	payload := SharesTransferredPayload{
+		TransferID:  transfer.ID,
+		FromUserID:  transfer.FromUserID,
+		ToUserID:    transfer.ToUserID,
+		StockSymbol: transfer.StockSymbol,
+		Quantity:    transfer.Quantity,
+		Value:       transfer.ValueINR,
+	}
+	if err := recordOutboxEvent(ctx, tx, s.outboxRepo, models.OutboxEventSharesTransferred, "transfer", transfer.ID.String(), transfer.FromUserID.String(), payload); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"transfer_id":  transfer.ID,
+		"from_user_id": transfer.FromUserID,
+		"to_user_id":   transfer.ToUserID,
+		"stock_symbol": transfer.StockSymbol,
+		"quantity":     transfer.Quantity,
+	}).Info("Shares transferred")
+
+	return newTransferResponse(transfer), nil
+}
+
+func (s *ShareTransferService) ListTransfers(ctx context.Context, userID uuid.UUID) ([]*TransferResponse, error) {
+	transfers, err := s.transferRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share transfers: %w", err)
+	}
+
+	result := make([]*TransferResponse, len(transfers))
+	for i := range transfers {
+		result[i] = newTransferResponse(&transfers[i])
+		if transfers[i].FromUserID == userID {
+			result[i].Direction = "OUT"
+		} else {
+			result[i].Direction = "IN"
+		}
+	}
+	return result, nil
+}
+
+func (s *ShareTransferService) replay(existing *models.ShareTransfer, req TransferRequest) (*TransferResponse, error) {
+	if existing.FromUserID != req.FromUserID || existing.ToUserID != req.ToUserID ||
+		existing.StockSymbol != req.StockSymbol || !existing.Quantity.Equal(req.Quantity) {
+		return nil, fmt.Errorf("%w: %s", ErrTransferConflict, req.TransferID)
+	}
+	logrus.WithField("transfer_id", req.TransferID).Info("Share transfer already processed (idempotent)")
+	return newTransferResponse(existing), nil
+}
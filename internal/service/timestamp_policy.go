@@ -0,0 +1,31 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+type TimestampPolicy struct {
+	MaxFutureSkew time.Duration
+	MaxBackdate   time.Duration
+	ClosedBefore  *time.Time
+}
+
+func (p TimestampPolicy) Validate(eventTime, receivedAt time.Time) error {
+	if eventTime.IsZero() {
+		return fmt.Errorf("%w: timestamp is required", ErrInvalidTimestamp)
+	}
+	if p.ClosedBefore != nil && eventTime.Before(*p.ClosedBefore) {
+		return fmt.Errorf("%w: timestamp %s falls before %s",
+			ErrPeriodClosed, eventTime.Format(time.RFC3339), p.ClosedBefore.Format("2006-01-02"))
+	}
+	if eventTime.After(receivedAt.Add(p.MaxFutureSkew)) {
+		return fmt.Errorf("%w: timestamp %s is more than %s in the future",
+			ErrInvalidTimestamp, eventTime.Format(time.RFC3339), p.MaxFutureSkew)
+	}
+	if p.MaxBackdate > 0 && eventTime.Before(receivedAt.Add(-p.MaxBackdate)) {
+		return fmt.Errorf("%w: timestamp %s is more than %s in the past",
+			ErrInvalidTimestamp, eventTime.Format(time.RFC3339), p.MaxBackdate)
+	}
+	return nil
+}
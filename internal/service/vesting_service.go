@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/repository"
+)
+
+type VestingService struct {
+	vestingRepo *repository.VestingRepository
+}
+
+func NewVestingService(vestingRepo *repository.VestingRepository) *VestingService {
+	return &VestingService{vestingRepo: vestingRepo}
+}
+
+type VestingSchedule struct {
+	Type         models.VestingType `json:"type" binding:"required"`
+	CliffDate    *time.Time         `json:"cliff_date,omitempty"`
+	StartDate    *time.Time         `json:"start_date,omitempty"`
+	Tranches     int                `json:"tranches,omitempty"`
+	IntervalDays int                `json:"interval_days,omitempty"`
+}
+
+func (s *VestingService) VestDueTranches(ctx context.Context) error {
+	vested, err := s.vestingRepo.VestDue(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to vest due tranches: %w", err)
+	}
+
+	if vested > 0 {
+		logrus.WithField("tranches", vested).Info("Vested due reward tranches")
+	}
+	return nil
+}
+
+func buildVestingTranches(schedule *VestingSchedule, eventID uuid.UUID, quantity decimal.Decimal, rewardTime time.Time) ([]*models.VestingTranche, error) {
+	now := time.Now()
+	newTranche := func(vestDate time.Time, qty decimal.Decimal) *models.VestingTranche {
+		return &models.VestingTranche{
+			ID:               uuid.New(),
+			EventID:          eventID,
+			VestDate:         vestDate,
+			Quantity:         qty,
+			ReversedQuantity: decimal.Zero,
+			CreatedAt:        now,
+		}
+	}
+
+	switch schedule.Type {
+	case models.VestingTypeCliff:
+		if schedule.CliffDate == nil {
+			return nil, fmt.Errorf("%w: cliff_date is required for CLIFF vesting", ErrInvalidVestingSchedule)
+		}
+		return []*models.VestingTranche{newTranche(*schedule.CliffDate, quantity)}, nil
+
+	case models.VestingTypeLinear:
+		if schedule.Tranches < 1 || schedule.IntervalDays < 1 {
+			return nil, fmt.Errorf("%w: tranches and interval_days must be positive for LINEAR vesting", ErrInvalidVestingSchedule)
+		}
+
+		start := rewardTime
+		if schedule.StartDate != nil {
+			start = *schedule.StartDate
+		}
+
+		perTranche := quantity.Div(decimal.NewFromInt(int64(schedule.Tranches))).RoundDown(6)
+		if perTranche.LessThanOrEqual(decimal.Zero) {
+			return nil, fmt.Errorf("%w: quantity too small for %d tranches", ErrInvalidVestingSchedule, schedule.Tranches)
+		}
+
+		tranches := make([]*models.VestingTranche, 0, schedule.Tranches)
+		allocated := decimal.Zero
+		for i := 1; i <= schedule.Tranches; i++ {
+			qty := perTranche
+			if i == schedule.Tranches {
+				qty = quantity.Sub(allocated)
+			}
+			allocated = allocated.Add(qty)
+			tranches = append(tranches, newTranche(start.AddDate(0, 0, i*schedule.IntervalDays), qty))
+		}
+		return tranches, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidVestingSchedule, schedule.Type)
+	}
+}
@@ -0,0 +1,515 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"stocky/internal/models"
+	"stocky/internal/outbox"
+	"stocky/internal/repository"
+	"stocky/internal/webhook"
+)
+
+type WebhookPolicy struct {
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	DisableAfter int
+	BatchSize    int
+	LeaseTimeout time.Duration
+}
+
+type WebhookService struct {
+	webhookRepo *repository.WebhookRepository
+	client      *webhook.Client
+	policy      WebhookPolicy
+	db          *sqlx.DB
+}
+
+func NewWebhookService(
+	webhookRepo *repository.WebhookRepository,
+	client *webhook.Client,
+	policy WebhookPolicy,
+	db *sqlx.DB,
+) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		client:      client,
+		policy:      policy,
+		db:          db,
+	}
+}
+
+type CreateWebhookRequest struct {
+	PartnerID  string   `json:"partner_id" binding:"required"`
+	Name       string   `json:"name" binding:"required"`
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+	Secret     string   `json:"secret"`
+}
+
+type UpdateWebhookRequest struct {
+	Name       *string               `json:"name"`
+	URL        *string               `json:"url"`
+	EventTypes []string              `json:"event_types"`
+	Status     *models.WebhookStatus `json:"status"`
+}
+
+type WebhookResponse struct {
+	ID                  uuid.UUID            `json:"id"`
+	PartnerID           *string              `json:"partner_id,omitempty"`
+	Name                string               `json:"name"`
+	URL                 string               `json:"url"`
+	EventTypes          []string             `json:"event_types"`
+	Secret              string               `json:"secret,omitempty"`
+	Status              models.WebhookStatus `json:"status"`
+	ConsecutiveFailures int                  `json:"consecutive_failures"`
+	DisabledReason      *string              `json:"disabled_reason,omitempty"`
+	DisabledAt          *time.Time           `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time            `json:"created_at"`
+	UpdatedAt           time.Time            `json:"updated_at"`
+}
+
+type WebhookAttemptResponse struct {
+	Attempt      int       `json:"attempt"`
+	StatusCode   *int      `json:"status_code,omitempty"`
+	Error        *string   `json:"error,omitempty"`
+	ResponseBody *string   `json:"response_body,omitempty"`
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID                    `json:"id"`
+	SubscriptionID uuid.UUID                    `json:"subscription_id"`
+	MessageID      uuid.UUID                    `json:"message_id"`
+	EventType      string                       `json:"event_type"`
+	Payload        json.RawMessage              `json:"payload,omitempty"`
+	Status         models.WebhookDeliveryStatus `json:"status"`
+	Attempts       int                          `json:"attempts"`
+	NextAttemptAt  *time.Time                   `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int                         `json:"last_status_code,omitempty"`
+	LastError      *string                      `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time                   `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time                    `json:"created_at"`
+	AttemptLog     []WebhookAttemptResponse     `json:"attempt_log,omitempty"`
+}
+
+func newWebhookResponse(s *models.WebhookSubscription) *WebhookResponse {
+	return &WebhookResponse{
+		ID:                  s.ID,
+		PartnerID:           s.PartnerID,
+		Name:                s.Name,
+		URL:                 s.URL,
+		EventTypes:          s.EventTypes,
+		Status:              s.Status,
+		ConsecutiveFailures: s.ConsecutiveFailures,
+		DisabledReason:      s.DisabledReason,
+		DisabledAt:          s.DisabledAt,
+		CreatedAt:           s.CreatedAt,
+		UpdatedAt:           s.UpdatedAt,
+	}
+}
+
+func newWebhookDeliveryResponse(d *models.WebhookDelivery) *WebhookDeliveryResponse {
+	resp := &WebhookDeliveryResponse{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		MessageID:      d.MessageID,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.Status == models.WebhookDeliveryPending {
+		resp.NextAttemptAt = &d.NextAttemptAt
+	}
+	return resp
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, req CreateWebhookRequest) (*WebhookResponse, error) {
+	if err := validateWebhook(req.URL, req.EventTypes); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	sub := &models.WebhookSubscription{
+		ID:         uuid.New(),
+		PartnerID:  &req.PartnerID,
+		Name:       req.Name,
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     secret,
+		Status:     models.WebhookStatusActive,
+		CreatedAt:  time.Now(),
+	}
+	sub.UpdatedAt = sub.CreatedAt
+	if err := s.webhookRepo.CreateSubscription(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"subscription_id": sub.ID,
+		"partner_id":      req.PartnerID,
+		"url":             sub.URL,
+		"event_types":     sub.EventTypes,
+	}).Info("Webhook subscription created")
+
+	resp := newWebhookResponse(sub)
+	resp.Secret = secret
+	return resp, nil
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]*WebhookResponse, error) {
+	subs, err := s.webhookRepo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	result := make([]*WebhookResponse, len(subs))
+	for i := range subs {
+		result[i] = newWebhookResponse(&subs[i])
+	}
+	return result, nil
+}
+
+func (s *WebhookService) GetSubscription(ctx context.Context, id uuid.UUID) (*WebhookResponse, error) {
+	sub, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return newWebhookResponse(sub), nil
+}
+
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id uuid.UUID, req UpdateWebhookRequest) (*WebhookResponse, error) {
+	sub, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		sub.Name = *req.Name
+	}
+	if req.URL != nil {
+		sub.URL = *req.URL
+	}
+	if req.EventTypes != nil {
+		sub.EventTypes = req.EventTypes
+	}
+	if err := validateWebhook(sub.URL, sub.EventTypes); err != nil {
+		return nil, err
+	}
+
+	if req.Status != nil {
+		switch *req.Status {
+		case models.WebhookStatusActive:
+			sub.Status = models.WebhookStatusActive
+			sub.ConsecutiveFailures = 0
+			sub.DisabledReason = nil
+			sub.DisabledAt = nil
+		case models.WebhookStatusDisabled:
+			if sub.Status != models.WebhookStatusDisabled {
+				now := time.Now()
+				reason := "disabled by operator"
+				sub.Status = models.WebhookStatusDisabled
+				sub.DisabledReason = &reason
+				sub.DisabledAt = &now
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown status %s", ErrInvalidWebhook, *req.Status)
+		}
+	}
+
+	if err := s.webhookRepo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+	return s.GetSubscription(ctx, id)
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	deleted, err := s.webhookRepo.DeleteSubscription(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, status models.WebhookDeliveryStatus) ([]*WebhookDeliveryResponse, error) {
+	if _, err := s.getSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.webhookRepo.ListDeliveries(ctx, subscriptionID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	result := make([]*WebhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		result[i] = newWebhookDeliveryResponse(&deliveries[i])
+	}
+	return result, nil
+}
+
+func (s *WebhookService) GetDelivery(ctx context.Context, id uuid.UUID) (*WebhookDeliveryResponse, error) {
+	delivery, err := s.webhookRepo.GetDelivery(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	attempts, err := s.webhookRepo.GetAttempts(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery attempts: %w", err)
+	}
+
+	resp := newWebhookDeliveryResponse(delivery)
+	resp.Payload = json.RawMessage(delivery.Payload)
+	for _, a := range attempts {
+		resp.AttemptLog = append(resp.AttemptLog, WebhookAttemptResponse{
+			Attempt:      a.Attempt,
+			StatusCode:   a.StatusCode,
+			Error:        a.Error,
+			ResponseBody: a.ResponseBody,
+			DurationMs:   a.DurationMs,
+			CreatedAt:    a.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+func (s *WebhookService) Redeliver(ctx context.Context, id uuid.UUID) (*WebhookDeliveryResponse, error) {
+	if _, err := s.webhookRepo.GetDelivery(ctx, id); err == sql.ErrNoRows {
+		return nil, ErrWebhookDeliveryNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	if err := s.webhookRepo.ResetDelivery(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to reset webhook delivery: %w", err)
+	}
+
+	logrus.WithField("delivery_id", id).Info("Webhook delivery queued for redelivery")
+	return s.GetDelivery(ctx, id)
+}
+
+func (s *WebhookService) Name() string {
+	return "webhooks"
+}
+
+func (s *WebhookService) Publish(ctx context.Context, msg outbox.Message) error {
+	var userID *uuid.UUID
+	if id, err := uuid.Parse(msg.PartitionKey); err == nil {
+		userID = &id
+	}
+	subs, err := s.webhookRepo.ListActiveForEvent(ctx, msg.Type, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	now := time.Now()
+	for _, sub := range subs {
+		delivery := &models.WebhookDelivery{
+			ID:             uuid.New(),
+			SubscriptionID: sub.ID,
+			MessageID:      msg.ID,
+			EventType:      msg.Type,
+			Payload:        body,
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		}
+		if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to create webhook delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *WebhookService) DeliverDue(ctx context.Context) error {
+	now := time.Now()
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, now, now.Add(s.policy.LeaseTimeout), s.policy.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to claim due webhook deliveries: %w", err)
+	}
+
+	subs := make(map[uuid.UUID]*models.WebhookSubscription)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		sub, ok := subs[delivery.SubscriptionID]
+		if !ok {
+			sub, err = s.webhookRepo.GetSubscription(ctx, delivery.SubscriptionID)
+			if err != nil {
+				return fmt.Errorf("failed to get webhook subscription: %w", err)
+			}
+			subs[sub.ID] = sub
+		}
+		if sub.Status != models.WebhookStatusActive {
+			continue
+		}
+
+		if err := s.deliver(ctx, sub, delivery); err != nil {
+			logrus.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to record webhook delivery result")
+		}
+	}
+	return nil
+}
+
+func (s *WebhookService) deliver(ctx context.Context, sub *models.WebhookSubscription, delivery *models.WebhookDelivery) error {
+	result, sendErr := s.client.Send(ctx, webhook.Request{
+		URL:        sub.URL,
+		Secret:     sub.Secret,
+		DeliveryID: delivery.ID.String(),
+		EventType:  delivery.EventType,
+		Body:       delivery.Payload,
+	})
+	if sendErr == nil && !result.Succeeded() {
+		sendErr = fmt.Errorf("endpoint responded with status %d", result.StatusCode)
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	attempt := &models.WebhookDeliveryAttempt{
+		ID:         uuid.New(),
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+		DurationMs: result.Duration.Milliseconds(),
+		CreatedAt:  now,
+	}
+	if result.StatusCode != 0 {
+		attempt.StatusCode = &result.StatusCode
+		attempt.ResponseBody = &result.ResponseBody
+		delivery.LastStatusCode = &result.StatusCode
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"delivery_id":     delivery.ID,
+		"subscription_id": sub.ID,
+		"event_type":      delivery.EventType,
+		"attempt":         delivery.Attempts,
+	})
+
+	if sendErr == nil {
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.LastError = nil
+		delivery.DeliveredAt = &now
+		log.Info("Webhook delivered")
+	} else {
+		errMsg := sendErr.Error()
+		attempt.Error = &errMsg
+		delivery.LastError = &errMsg
+		if delivery.Attempts >= s.policy.MaxAttempts {
+			delivery.Status = models.WebhookDeliveryFailed
+		} else {
+			delivery.NextAttemptAt = now.Add(retryBackoff(s.policy.BaseBackoff, s.policy.MaxBackoff, delivery.Attempts))
+		}
+		log.WithError(sendErr).Warn("Webhook delivery failed")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	locked, err := s.webhookRepo.GetSubscriptionForUpdate(ctx, tx, sub.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	if sendErr == nil {
+		locked.ConsecutiveFailures = 0
+	} else {
+		locked.ConsecutiveFailures++
+		if locked.Status == models.WebhookStatusActive && s.policy.DisableAfter > 0 && locked.ConsecutiveFailures >= s.policy.DisableAfter {
+			reason := fmt.Sprintf("disabled after %d consecutive failures", locked.ConsecutiveFailures)
+			locked.Status = models.WebhookStatusDisabled
+			locked.DisabledReason = &reason
+			locked.DisabledAt = &now
+			log.Warn("Webhook subscription disabled")
+		}
+	}
+
+	if err := s.webhookRepo.AddAttempt(ctx, tx, attempt); err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	if err := s.webhookRepo.UpdateDelivery(ctx, tx, delivery); err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	if err := s.webhookRepo.UpdateSubscriptionHealth(ctx, tx, locked); err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	*sub = *locked
+	return nil
+}
+
+func (s *WebhookService) getSubscription(ctx context.Context, id uuid.UUID) (*models.WebhookSubscription, error) {
+	sub, err := s.webhookRepo.GetSubscription(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return sub, nil
+}
+
+func validateWebhook(rawURL string, eventTypes []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	if len(eventTypes) == 0 {
+		return fmt.Errorf("%w: at least one event type is required", ErrInvalidWebhook)
+	}
+	for _, t := range eventTypes {
+		if !models.OutboxEventType(t).Valid() {
+			return fmt.Errorf("%w: unknown event type %s", ErrInvalidWebhook, t)
+		}
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
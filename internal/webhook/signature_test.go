@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	signedAt := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"cc0e8400-e29b-41d4-a716-446655440000","type":"RewardBooked"}`)
+	header := Sign("whsec_test", signedAt, body)
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  string
+		body    []byte
+		now     time.Time
+		wantErr string
+	}{
+		{name: "valid", secret: "whsec_test", header: header, body: body, now: signedAt},
+		{name: "within tolerance", secret: "whsec_test", header: header, body: body, now: signedAt.Add(4 * time.Minute)},
+		{name: "clock skew within tolerance", secret: "whsec_test", header: header, body: body, now: signedAt.Add(-4 * time.Minute)},
+		{name: "wrong secret", secret: "whsec_other", header: header, body: body, now: signedAt, wantErr: "signature mismatch"},
+		{name: "tampered body", secret: "whsec_test", header: header, body: []byte(`{"id":"x"}`), now: signedAt, wantErr: "signature mismatch"},
+		{name: "expired", secret: "whsec_test", header: header, body: body, now: signedAt.Add(6 * time.Minute), wantErr: "outside tolerance"},
+		{name: "from the future", secret: "whsec_test", header: header, body: body, now: signedAt.Add(-6 * time.Minute), wantErr: "outside tolerance"},
+		{name: "replayed timestamp", secret: "whsec_test", header: strings.Replace(header, "t=1700000000", "t=1700000060", 1), body: body, now: signedAt, wantErr: "signature mismatch"},
+		{name: "missing signature", secret: "whsec_test", header: "t=1700000000", body: body, now: signedAt, wantErr: "malformed"},
+		{name: "missing timestamp", secret: "whsec_test", header: strings.TrimPrefix(header, "t=1700000000,"), body: body, now: signedAt, wantErr: "malformed"},
+		{name: "empty header", secret: "whsec_test", header: "", body: body, now: signedAt, wantErr: "malformed"},
+		{name: "bad timestamp", secret: "whsec_test", header: "t=abc,v1=00", body: body, now: signedAt, wantErr: "invalid signature timestamp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.header, tt.body, 5*time.Minute, tt.now)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Verify() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		signedAt time.Time
+		body     []byte
+		want     string
+	}{
+		{
+			name:     "empty body",
+			secret:   "secret",
+			signedAt: time.Unix(0, 0),
+			body:     nil,
+			want:     "t=0,v1=3445798a051818ef95def46c2eb62b43d377ce6e3c29b4d0aec3da0e59577f79",
+		},
+		{
+			name:     "timestamp in seconds",
+			secret:   "secret",
+			signedAt: time.Unix(1700000000, 999999999),
+			body:     []byte("{}"),
+			want:     "t=1700000000,v1=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.signedAt, tt.body); got != tt.want {
+				t.Fatalf("Sign() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
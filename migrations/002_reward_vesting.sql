@@ -0,0 +1,61 @@
+-- Booking price captured at the time a reward is processed
+ALTER TABLE reward_events ADD COLUMN IF NOT EXISTS booking_price NUMERIC(18,4);
+
+-- Backfill booking price from the STOCK ledger entry of existing rewards
+UPDATE reward_events re
+SET booking_price = ROUND(le.credit / re.quantity, 4)
+FROM ledger_entries le
+WHERE le.event_id = re.event_id
+  AND le.entry_type = 'STOCK'
+  AND re.booking_price IS NULL;
+
+-- Vesting tranches (rewards without tranches are vested immediately)
+CREATE TABLE IF NOT EXISTS reward_vesting_tranches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES reward_events(event_id) ON DELETE CASCADE,
+    vest_date TIMESTAMP NOT NULL,
+    quantity NUMERIC(18,6) NOT NULL CHECK (quantity > 0),
+    reversed_quantity NUMERIC(18,6) NOT NULL DEFAULT 0,
+    vested BOOLEAN NOT NULL DEFAULT FALSE,
+    vested_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (reversed_quantity >= 0 AND reversed_quantity <= quantity)
+);
+
+-- Reward reversals (only unvested quantities can be reversed)
+CREATE TABLE IF NOT EXISTS reward_reversals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES reward_events(event_id) ON DELETE CASCADE,
+    quantity NUMERIC(18,6) NOT NULL CHECK (quantity > 0),
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_vesting_tranches_event_id ON reward_vesting_tranches(event_id);
+CREATE INDEX IF NOT EXISTS idx_vesting_tranches_due ON reward_vesting_tranches(vest_date) WHERE vested = FALSE;
+CREATE INDEX IF NOT EXISTS idx_reward_reversals_event_id ON reward_reversals(event_id);
+
+-- Reward value is an expense; company cash only pays the fees
+ALTER TABLE ledger_entries ALTER COLUMN entry_type TYPE VARCHAR(30);
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_entry_type_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_entry_type_check
+    CHECK (entry_type IN ('STOCK', 'CASH', 'FEE', 'REWARD_EXPENSE'));
+
+-- Rebalance existing bookings that debited cash for value plus fees
+INSERT INTO ledger_entries (event_id, entry_type, debit, credit, created_at)
+SELECT s.event_id, 'REWARD_EXPENSE', s.credit, 0, s.created_at
+FROM ledger_entries s
+JOIN ledger_entries c ON c.event_id = s.event_id AND c.entry_type = 'CASH'
+JOIN ledger_entries f ON f.event_id = s.event_id AND f.entry_type = 'FEE'
+WHERE s.entry_type = 'STOCK'
+  AND c.credit = 0
+  AND c.debit = s.credit + f.debit;
+
+UPDATE ledger_entries c
+SET debit = 0, credit = f.debit
+FROM ledger_entries f
+WHERE f.event_id = c.event_id
+  AND f.entry_type = 'FEE'
+  AND c.entry_type = 'CASH'
+  AND c.credit = 0
+  AND EXISTS (SELECT 1 FROM ledger_entries x WHERE x.event_id = c.event_id AND x.entry_type = 'REWARD_EXPENSE');
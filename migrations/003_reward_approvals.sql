@@ -0,0 +1,22 @@
+-- Reward lifecycle status (only BOOKED rewards count towards holdings)
+ALTER TABLE reward_events ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'BOOKED';
+ALTER TABLE reward_events DROP CONSTRAINT IF EXISTS reward_events_status_check;
+ALTER TABLE reward_events ADD CONSTRAINT reward_events_status_check
+    CHECK (status IN ('PENDING_APPROVAL', 'BOOKED', 'REJECTED', 'EXPIRED'));
+
+-- Maker-checker approvals for high-value rewards
+CREATE TABLE IF NOT EXISTS reward_approvals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID UNIQUE NOT NULL REFERENCES reward_events(event_id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'EXPIRED')),
+    value_inr NUMERIC(18,4) NOT NULL,
+    requested_by VARCHAR(100) NOT NULL DEFAULT '',
+    decided_by VARCHAR(100),
+    reason TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_events_status ON reward_events(status);
+CREATE INDEX IF NOT EXISTS idx_reward_approvals_pending ON reward_approvals(expires_at) WHERE status = 'PENDING';
@@ -0,0 +1,29 @@
+-- Settlement lifecycle: BOOKED -> ORDERED -> SETTLED / FAILED
+ALTER TABLE reward_events DROP CONSTRAINT IF EXISTS reward_events_status_check;
+ALTER TABLE reward_events ADD CONSTRAINT reward_events_status_check
+    CHECK (status IN ('PENDING_APPROVAL', 'BOOKED', 'REJECTED', 'EXPIRED', 'ORDERED', 'SETTLED', 'FAILED'));
+
+ALTER TABLE reward_events ADD COLUMN IF NOT EXISTS booked_at TIMESTAMP;
+ALTER TABLE reward_events ADD COLUMN IF NOT EXISTS ordered_at TIMESTAMP;
+ALTER TABLE reward_events ADD COLUMN IF NOT EXISTS settlement_due_at TIMESTAMP;
+ALTER TABLE reward_events ADD COLUMN IF NOT EXISTS settled_at TIMESTAMP;
+ALTER TABLE reward_events ADD COLUMN IF NOT EXISTS failed_at TIMESTAMP;
+ALTER TABLE reward_events ADD COLUMN IF NOT EXISTS failure_reason TEXT;
+
+UPDATE reward_events SET booked_at = created_at WHERE status = 'BOOKED' AND booked_at IS NULL;
+
+-- Settlement (shares receivable from broker) and inventory (shares held in custody) entries
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_entry_type_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_entry_type_check
+    CHECK (entry_type IN ('STOCK', 'CASH', 'FEE', 'REWARD_EXPENSE', 'SETTLEMENT', 'INVENTORY'));
+
+-- Fees of booked rewards are now paid in cash when the order is placed
+UPDATE ledger_entries le
+SET entry_type = 'SETTLEMENT'
+FROM reward_events re
+WHERE re.event_id = le.event_id
+  AND re.status = 'BOOKED'
+  AND le.entry_type = 'CASH'
+  AND le.credit > 0;
+
+CREATE INDEX IF NOT EXISTS idx_reward_events_settlement_due ON reward_events(settlement_due_at) WHERE status = 'ORDERED';
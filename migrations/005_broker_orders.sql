@@ -0,0 +1,44 @@
+-- Ledger entries may now belong to non-reward events (e.g. broker orders)
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_event_id_fkey;
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_entry_type_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_entry_type_check
+    CHECK (entry_type IN ('STOCK', 'CASH', 'FEE', 'REWARD_EXPENSE', 'SETTLEMENT', 'INVENTORY', 'EXECUTION_VARIANCE'));
+
+-- Aggregated whole-share buy orders placed with the broker
+CREATE TABLE IF NOT EXISTS broker_orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    symbol VARCHAR(20) NOT NULL,
+    side VARCHAR(4) NOT NULL DEFAULT 'BUY' CHECK (side IN ('BUY', 'SELL')),
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    reward_quantity NUMERIC(18,6) NOT NULL,
+    booked_value NUMERIC(18,4) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('PENDING', 'SUBMITTED', 'FILLED', 'FAILED')),
+    broker_order_id VARCHAR(100),
+    failure_reason TEXT,
+    submitted_at TIMESTAMP,
+    filled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Rewards netted into each broker order
+CREATE TABLE IF NOT EXISTS broker_order_rewards (
+    order_id UUID NOT NULL REFERENCES broker_orders(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL REFERENCES reward_events(event_id) ON DELETE CASCADE,
+    PRIMARY KEY (order_id, event_id)
+);
+
+-- Executions reported by the broker
+CREATE TABLE IF NOT EXISTS broker_fills (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES broker_orders(id) ON DELETE CASCADE,
+    broker_fill_id VARCHAR(100) UNIQUE NOT NULL,
+    quantity NUMERIC(18,6) NOT NULL CHECK (quantity > 0),
+    price NUMERIC(18,4) NOT NULL CHECK (price > 0),
+    fees NUMERIC(18,4) NOT NULL DEFAULT 0,
+    executed_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_broker_orders_status ON broker_orders(status);
+CREATE INDEX IF NOT EXISTS idx_broker_order_rewards_event_id ON broker_order_rewards(event_id);
+CREATE INDEX IF NOT EXISTS idx_broker_fills_order_id ON broker_fills(order_id);
@@ -0,0 +1,51 @@
+-- Company-owned pool of shares per symbol used to allocate fractional rewards
+CREATE TABLE IF NOT EXISTS inventory_pools (
+    symbol VARCHAR(20) PRIMARY KEY,
+    quantity NUMERIC(18,6) NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    cost NUMERIC(18,4) NOT NULL DEFAULT 0,
+    replenish_threshold NUMERIC(18,6) NOT NULL DEFAULT 0,
+    replenish_quantity BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Every change to a pool
+CREATE TABLE IF NOT EXISTS inventory_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    symbol VARCHAR(20) NOT NULL,
+    quantity NUMERIC(18,6) NOT NULL,
+    cost NUMERIC(18,4) NOT NULL DEFAULT 0,
+    movement_type VARCHAR(20) NOT NULL CHECK (movement_type IN ('PURCHASE', 'ALLOCATION', 'REVERSAL')),
+    reference_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Broker orders are either netted rewards or pool replenishment
+ALTER TABLE broker_orders ADD COLUMN IF NOT EXISTS purpose VARCHAR(20) NOT NULL DEFAULT 'REWARDS'
+    CHECK (purpose IN ('REWARDS', 'REPLENISHMENT'));
+
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_symbol ON inventory_movements(symbol, created_at);
+
+-- INVENTORY holds only the pool; shares held for users move to CUSTODY
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_entry_type_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_entry_type_check
+    CHECK (entry_type IN ('STOCK', 'CASH', 'FEE', 'REWARD_EXPENSE', 'SETTLEMENT', 'INVENTORY', 'CUSTODY', 'EXECUTION_VARIANCE'));
+
+UPDATE ledger_entries le
+SET entry_type = 'CUSTODY'
+FROM reward_events re
+WHERE re.event_id = le.event_id
+  AND le.entry_type = 'INVENTORY';
+
+-- Seed the pools with the fractional shares already bought by filled orders
+INSERT INTO inventory_movements (symbol, quantity, cost, movement_type, reference_id, created_at)
+SELECT bo.symbol, bo.quantity - bo.reward_quantity, SUM(le.debit - le.credit), 'PURCHASE', bo.id, COALESCE(bo.filled_at, bo.created_at)
+FROM broker_orders bo
+JOIN ledger_entries le ON le.event_id = bo.id AND le.entry_type = 'INVENTORY'
+WHERE bo.status = 'FILLED' AND bo.quantity > bo.reward_quantity
+GROUP BY bo.id, bo.symbol, bo.quantity, bo.reward_quantity, bo.filled_at, bo.created_at;
+
+INSERT INTO inventory_pools (symbol, quantity, cost)
+SELECT symbol, SUM(quantity), SUM(cost)
+FROM inventory_movements
+GROUP BY symbol
+ON CONFLICT (symbol) DO NOTHING;
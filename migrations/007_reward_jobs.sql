@@ -0,0 +1,18 @@
+-- Durable queue for asynchronous reward processing
+CREATE TABLE IF NOT EXISTS reward_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID UNIQUE NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'QUEUED' CHECK (status IN ('QUEUED', 'PROCESSING', 'SUCCEEDED', 'FAILED')),
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_jobs_ready ON reward_jobs(next_attempt_at) WHERE status = 'QUEUED';
+CREATE INDEX IF NOT EXISTS idx_reward_jobs_processing ON reward_jobs(locked_at) WHERE status = 'PROCESSING';
@@ -0,0 +1,18 @@
+-- Failed reward requests kept for inspection and replay
+CREATE TABLE IF NOT EXISTS reward_dead_letters (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID UNIQUE NOT NULL,
+    source VARCHAR(10) NOT NULL CHECK (source IN ('API', 'QUEUE')),
+    payload JSONB NOT NULL,
+    error TEXT NOT NULL,
+    error_class VARCHAR(30) NOT NULL,
+    attempts INT NOT NULL DEFAULT 1,
+    status VARCHAR(10) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'REPLAYED')),
+    replay_count INT NOT NULL DEFAULT 0,
+    replayed_by VARCHAR(100),
+    replayed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_dead_letters_open ON reward_dead_letters(error_class, created_at) WHERE status = 'OPEN';
@@ -0,0 +1,32 @@
+-- Transactional outbox for domain events and per-sink delivery tracking
+CREATE TABLE IF NOT EXISTS outbox_partitions (
+    partition_key VARCHAR(100) PRIMARY KEY,
+    last_sequence BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    message_id UUID UNIQUE NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    aggregate_type VARCHAR(30) NOT NULL,
+    aggregate_id VARCHAR(100) NOT NULL,
+    partition_key VARCHAR(100) NOT NULL,
+    sequence BIGINT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS outbox_deliveries (
+    sink VARCHAR(30) NOT NULL,
+    event_id BIGINT NOT NULL REFERENCES outbox_events(id),
+    delivered_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (sink, event_id)
+);
+
+CREATE TABLE IF NOT EXISTS outbox_sink_leases (
+    sink VARCHAR(30) PRIMARY KEY,
+    lease_id UUID NOT NULL,
+    leased_until TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_events_partition_sequence ON outbox_events(partition_key, sequence);
@@ -0,0 +1,17 @@
+-- Price history for pricing backdated rewards, and event vs. booking time on rewards
+CREATE TABLE IF NOT EXISTS stock_price_history (
+    symbol VARCHAR(20) NOT NULL,
+    price NUMERIC(18,4) NOT NULL CHECK (price > 0),
+    fetched_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (symbol, fetched_at)
+);
+
+INSERT INTO stock_price_history (symbol, price, fetched_at)
+SELECT symbol, price, fetched_at FROM stock_prices
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE reward_events ADD COLUMN IF NOT EXISTS priced_at TIMESTAMP;
+ALTER TABLE reward_events ADD COLUMN IF NOT EXISTS received_at TIMESTAMP;
+
+UPDATE reward_events SET received_at = created_at WHERE received_at IS NULL;
+UPDATE reward_events SET priced_at = booked_at WHERE priced_at IS NULL AND booking_price IS NOT NULL;
@@ -0,0 +1,24 @@
+-- Rewards submitted now and granted at a future date
+CREATE TABLE IF NOT EXISTS scheduled_rewards (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID UNIQUE NOT NULL,
+    user_id UUID NOT NULL,
+    stock_symbol VARCHAR(20) NOT NULL,
+    quantity NUMERIC(18,6) NOT NULL CHECK (quantity > 0),
+    payload JSONB NOT NULL,
+    scheduled_for TIMESTAMP NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'SCHEDULED' CHECK (status IN ('SCHEDULED', 'EXECUTING', 'EXECUTED', 'CANCELLED', 'FAILED')),
+    locked_at TIMESTAMP,
+    executed_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    cancelled_by VARCHAR(100),
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_rewards_due ON scheduled_rewards(scheduled_for) WHERE status = 'SCHEDULED';
+
+ALTER TABLE reward_dead_letters DROP CONSTRAINT IF EXISTS reward_dead_letters_source_check;
+ALTER TABLE reward_dead_letters ADD CONSTRAINT reward_dead_letters_source_check
+    CHECK (source IN ('API', 'QUEUE', 'SCHEDULE'));
@@ -0,0 +1,30 @@
+-- Campaign budgets and reward offers that users claim before a deadline
+CREATE TABLE IF NOT EXISTS reward_campaigns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    budget_inr NUMERIC(18,4) NOT NULL CHECK (budget_inr > 0),
+    reserved_inr NUMERIC(18,4) NOT NULL DEFAULT 0 CHECK (reserved_inr >= 0),
+    spent_inr NUMERIC(18,4) NOT NULL DEFAULT 0 CHECK (spent_inr >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS reward_offers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID UNIQUE NOT NULL,
+    campaign_id UUID REFERENCES reward_campaigns(id),
+    user_id UUID NOT NULL,
+    stock_symbol VARCHAR(20) NOT NULL,
+    quantity NUMERIC(18,6) NOT NULL CHECK (quantity > 0),
+    payload JSONB NOT NULL,
+    reserved_inr NUMERIC(18,4) NOT NULL DEFAULT 0,
+    claim_deadline TIMESTAMP NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'OFFERED' CHECK (status IN ('OFFERED', 'CLAIMED', 'EXPIRED')),
+    claimed_at TIMESTAMP,
+    expired_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_offers_user_open ON reward_offers(user_id, claim_deadline) WHERE status = 'OFFERED';
+CREATE INDEX IF NOT EXISTS idx_reward_offers_deadline ON reward_offers(claim_deadline) WHERE status = 'OFFERED';
@@ -0,0 +1,20 @@
+-- Peer-to-peer gifts of rewarded shares between users
+CREATE TABLE IF NOT EXISTS share_transfers (
+    id UUID PRIMARY KEY,
+    from_user_id UUID NOT NULL REFERENCES users(id),
+    to_user_id UUID NOT NULL REFERENCES users(id),
+    stock_symbol VARCHAR(20) NOT NULL,
+    quantity NUMERIC(18,6) NOT NULL CHECK (quantity > 0),
+    price NUMERIC(18,4) NOT NULL,
+    value_inr NUMERIC(18,4) NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (from_user_id <> to_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_transfers_from_user ON share_transfers(from_user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_share_transfers_to_user ON share_transfers(to_user_id, created_at);
+
+-- Ledger entries can now belong to a user account
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS user_id UUID;
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_user_id ON ledger_entries(user_id) WHERE user_id IS NOT NULL;
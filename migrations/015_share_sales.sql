@@ -0,0 +1,23 @@
+-- User sales of rewarded shares, paid out to an INR wallet
+CREATE TABLE IF NOT EXISTS share_sales (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id),
+    stock_symbol VARCHAR(20) NOT NULL,
+    quantity NUMERIC(18,6) NOT NULL CHECK (quantity > 0),
+    price NUMERIC(18,4) NOT NULL,
+    gross_inr NUMERIC(18,4) NOT NULL,
+    fees_inr NUMERIC(18,4) NOT NULL,
+    net_inr NUMERIC(18,4) NOT NULL CHECK (net_inr > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_sales_user ON share_sales(user_id, created_at);
+
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_entry_type_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_entry_type_check
+    CHECK (entry_type IN ('STOCK', 'CASH', 'FEE', 'REWARD_EXPENSE', 'SETTLEMENT', 'INVENTORY', 'CUSTODY', 'EXECUTION_VARIANCE',
+        'WALLET', 'SALE_CHARGES', 'GST_PAYABLE', 'CHARGES_PAYABLE'));
+
+ALTER TABLE inventory_movements DROP CONSTRAINT IF EXISTS inventory_movements_movement_type_check;
+ALTER TABLE inventory_movements ADD CONSTRAINT inventory_movements_movement_type_check
+    CHECK (movement_type IN ('PURCHASE', 'ALLOCATION', 'REVERSAL', 'BUYBACK'));
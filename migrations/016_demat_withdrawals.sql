@@ -0,0 +1,27 @@
+-- Transfers of rewarded shares out to a user's own demat account
+CREATE TABLE IF NOT EXISTS demat_withdrawals (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id),
+    stock_symbol VARCHAR(20) NOT NULL,
+    quantity NUMERIC(18,6) NOT NULL CHECK (quantity > 0 AND quantity = TRUNC(quantity)),
+    price NUMERIC(18,4) NOT NULL,
+    value_inr NUMERIC(18,4) NOT NULL,
+    dp_id VARCHAR(16) NOT NULL,
+    client_id VARCHAR(16) NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'REQUESTED' CHECK (status IN ('REQUESTED', 'SUBMITTED', 'COMPLETED', 'FAILED')),
+    depository_ref VARCHAR(100),
+    failure_reason TEXT,
+    submitted_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    failed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_demat_withdrawals_user ON demat_withdrawals(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_demat_withdrawals_open ON demat_withdrawals(status, created_at) WHERE status IN ('REQUESTED', 'SUBMITTED');
+
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_entry_type_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_entry_type_check
+    CHECK (entry_type IN ('STOCK', 'CASH', 'FEE', 'REWARD_EXPENSE', 'SETTLEMENT', 'INVENTORY', 'CUSTODY', 'EXECUTION_VARIANCE',
+        'WALLET', 'SALE_CHARGES', 'GST_PAYABLE', 'CHARGES_PAYABLE', 'WITHDRAWAL_HOLD', 'DEMAT_OUT'));
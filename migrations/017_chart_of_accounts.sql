@@ -0,0 +1,88 @@
+-- Chart of accounts: company-level accounts plus per-user stock, wallet and withdrawal accounts
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(100) NOT NULL UNIQUE,
+    name VARCHAR(200) NOT NULL,
+    account_type VARCHAR(10) NOT NULL CHECK (account_type IN ('ASSET', 'LIABILITY', 'EQUITY', 'INCOME', 'EXPENSE')),
+    user_id UUID REFERENCES users(id),
+    symbol VARCHAR(20),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_accounts_user ON ledger_accounts(user_id) WHERE user_id IS NOT NULL;
+
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_entry_type_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_entry_type_check
+    CHECK (entry_type IN ('STOCK', 'CASH', 'FEE', 'REWARD_EXPENSE', 'SETTLEMENT', 'INVENTORY', 'CUSTODY', 'EXECUTION_VARIANCE',
+        'WALLET', 'SALE_CHARGES', 'GST_PAYABLE', 'CHARGES_PAYABLE', 'BUYBACK_GAIN_LOSS', 'WITHDRAWAL_HOLD', 'DEMAT_OUT'));
+
+INSERT INTO ledger_accounts (code, name, account_type) VALUES
+    ('COMPANY_CASH', 'Company cash', 'ASSET'),
+    ('FEE_EXPENSE', 'Brokerage and statutory fees', 'EXPENSE'),
+    ('REWARD_EXPENSE', 'Reward expense', 'EXPENSE'),
+    ('CLIENT_CUSTODY', 'Shares held in custody for users', 'ASSET'),
+    ('SALE_CHARGES_INCOME', 'Charges on share buybacks', 'INCOME'),
+    ('GST_PAYABLE', 'GST collected on sale charges', 'LIABILITY'),
+    ('STATUTORY_CHARGES_PAYABLE', 'STT, exchange and SEBI charges collected on buybacks', 'LIABILITY'),
+    ('BUYBACK_GAIN_LOSS', 'Gain or loss on share buybacks', 'INCOME'),
+    ('COMPANY_INVENTORY', 'Company share inventory', 'ASSET'),
+    ('SETTLEMENT_CLEARING', 'Broker settlement clearing', 'LIABILITY'),
+    ('EXECUTION_VARIANCE', 'Broker execution variance', 'EXPENSE'),
+    ('DEMAT_TRANSFERS_OUT', 'Shares delivered to depository', 'LIABILITY'),
+    ('STOCK_UNALLOCATED', 'Stock postings without a user', 'LIABILITY')
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS account_id UUID REFERENCES ledger_accounts(id);
+
+-- Reward postings predate ledger_entries.user_id; attribute them through the reward
+UPDATE ledger_entries le
+SET user_id = re.user_id
+FROM reward_events re
+WHERE le.user_id IS NULL AND le.entry_type = 'STOCK' AND le.event_id = re.event_id;
+
+INSERT INTO ledger_accounts (code, name, account_type, user_id, symbol)
+SELECT DISTINCT 'USER_STOCK:' || user_id || ':' || symbol, 'Stock held for user (' || symbol || ')', 'LIABILITY', user_id, symbol
+FROM ledger_entries
+WHERE entry_type = 'STOCK' AND user_id IS NOT NULL AND symbol IS NOT NULL
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO ledger_accounts (code, name, account_type, user_id)
+SELECT DISTINCT 'USER_WALLET:' || user_id, 'INR wallet', 'LIABILITY', user_id
+FROM ledger_entries
+WHERE entry_type = 'WALLET' AND user_id IS NOT NULL
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO ledger_accounts (code, name, account_type, user_id, symbol)
+SELECT DISTINCT 'USER_WITHDRAWAL_HOLD:' || user_id || ':' || symbol, 'Stock held for demat withdrawal (' || symbol || ')', 'LIABILITY', user_id, symbol
+FROM ledger_entries
+WHERE entry_type = 'WITHDRAWAL_HOLD' AND user_id IS NOT NULL AND symbol IS NOT NULL
+ON CONFLICT (code) DO NOTHING;
+
+UPDATE ledger_entries le SET account_id = a.id FROM ledger_accounts a
+WHERE le.account_id IS NULL AND le.entry_type = 'STOCK' AND a.code = 'USER_STOCK:' || le.user_id || ':' || le.symbol;
+
+UPDATE ledger_entries le SET account_id = a.id FROM ledger_accounts a
+WHERE le.account_id IS NULL AND le.entry_type = 'WALLET' AND a.code = 'USER_WALLET:' || le.user_id;
+
+UPDATE ledger_entries le SET account_id = a.id FROM ledger_accounts a
+WHERE le.account_id IS NULL AND le.entry_type = 'WITHDRAWAL_HOLD' AND a.code = 'USER_WITHDRAWAL_HOLD:' || le.user_id || ':' || le.symbol;
+
+UPDATE ledger_entries le SET account_id = a.id FROM ledger_accounts a
+WHERE le.account_id IS NULL AND a.code = CASE le.entry_type
+    WHEN 'CASH' THEN 'COMPANY_CASH'
+    WHEN 'FEE' THEN 'FEE_EXPENSE'
+    WHEN 'REWARD_EXPENSE' THEN 'REWARD_EXPENSE'
+    WHEN 'CUSTODY' THEN 'CLIENT_CUSTODY'
+    WHEN 'SALE_CHARGES' THEN 'SALE_CHARGES_INCOME'
+    WHEN 'GST_PAYABLE' THEN 'GST_PAYABLE'
+    WHEN 'CHARGES_PAYABLE' THEN 'STATUTORY_CHARGES_PAYABLE'
+    WHEN 'BUYBACK_GAIN_LOSS' THEN 'BUYBACK_GAIN_LOSS'
+    WHEN 'INVENTORY' THEN 'COMPANY_INVENTORY'
+    WHEN 'SETTLEMENT' THEN 'SETTLEMENT_CLEARING'
+    WHEN 'EXECUTION_VARIANCE' THEN 'EXECUTION_VARIANCE'
+    WHEN 'DEMAT_OUT' THEN 'DEMAT_TRANSFERS_OUT'
+    ELSE 'STOCK_UNALLOCATED'
+END;
+
+ALTER TABLE ledger_entries ALTER COLUMN account_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account ON ledger_entries(account_id, created_at);
@@ -0,0 +1,48 @@
+-- Tamper-evident ledger: every entry carries a sequence number, the previous entry's hash and its own hash
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS sequence BIGINT;
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS prev_hash CHAR(64);
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS hash CHAR(64);
+
+-- Chain existing entries in posting order. The hashed fields and their format match the service.
+DO $$
+DECLARE
+    r RECORD;
+    n BIGINT := 0;
+    prev CHAR(64) := repeat('0', 64);
+BEGIN
+    FOR r IN SELECT * FROM ledger_entries WHERE sequence IS NULL ORDER BY created_at, id LOOP
+        n := n + 1;
+        UPDATE ledger_entries
+        SET sequence = n,
+            prev_hash = prev,
+            hash = encode(sha256(convert_to(concat_ws('|',
+                n, r.id, r.event_id, COALESCE(r.user_id::text, ''), r.account_id, r.entry_type,
+                COALESCE(r.symbol, ''), r.debit::text, r.credit::text,
+                to_char(r.created_at, 'YYYY-MM-DD HH24:MI:SS.US'), prev), 'UTF8')), 'hex')
+        WHERE id = r.id
+        RETURNING hash INTO prev;
+    END LOOP;
+END $$;
+
+ALTER TABLE ledger_entries ALTER COLUMN sequence SET NOT NULL;
+ALTER TABLE ledger_entries ALTER COLUMN prev_hash SET NOT NULL;
+ALTER TABLE ledger_entries ALTER COLUMN hash SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_entries_sequence ON ledger_entries(sequence);
+
+-- Ledger rows are append-only
+CREATE OR REPLACE FUNCTION reject_ledger_mutation()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger_entries is append-only: % is not allowed', TG_OP;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS ledger_entries_append_only ON ledger_entries;
+CREATE TRIGGER ledger_entries_append_only
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION reject_ledger_mutation();
+
+DROP TRIGGER IF EXISTS ledger_entries_no_truncate ON ledger_entries;
+CREATE TRIGGER ledger_entries_no_truncate
+    BEFORE TRUNCATE ON ledger_entries
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_ledger_mutation();
@@ -0,0 +1,23 @@
+-- Results of scheduled ledger reconciliation runs
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    id UUID PRIMARY KEY,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('PASSED', 'FAILED')),
+    discrepancy_count INT NOT NULL DEFAULT 0,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS reconciliation_discrepancies (
+    id UUID PRIMARY KEY,
+    run_id UUID NOT NULL REFERENCES reconciliation_runs(id) ON DELETE CASCADE,
+    check_name VARCHAR(30) NOT NULL,
+    reference_id UUID,
+    symbol VARCHAR(20),
+    expected NUMERIC(24,6),
+    actual NUMERIC(24,6),
+    message TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reconciliation_runs_started ON reconciliation_runs(started_at);
+CREATE INDEX IF NOT EXISTS idx_reconciliation_discrepancies_run ON reconciliation_discrepancies(run_id);
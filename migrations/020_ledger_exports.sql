@@ -0,0 +1,18 @@
+-- Daily ledger exports for the accounting system, one per date and format
+CREATE TABLE IF NOT EXISTS ledger_exports (
+    id UUID PRIMARY KEY,
+    export_date DATE NOT NULL,
+    format VARCHAR(20) NOT NULL CHECK (format IN ('TALLY_XML', 'JOURNAL_CSV')),
+    voucher_count INT NOT NULL,
+    entry_count INT NOT NULL,
+    total_debit NUMERIC(18,4) NOT NULL,
+    total_credit NUMERIC(18,4) NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    content BYTEA NOT NULL,
+    imported_at TIMESTAMP,
+    imported_by VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (export_date, format)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_exports_date ON ledger_exports(export_date DESC);
@@ -0,0 +1,28 @@
+-- Fees are posted per component so brokerage, taxes and charges each have their own account
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_entry_type_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_entry_type_check
+    CHECK (entry_type IN ('STOCK', 'CASH', 'FEE', 'REWARD_EXPENSE', 'SETTLEMENT', 'INVENTORY', 'CUSTODY', 'EXECUTION_VARIANCE',
+        'WALLET', 'SALE_CHARGES', 'GST_PAYABLE', 'CHARGES_PAYABLE', 'BUYBACK_GAIN_LOSS', 'WITHDRAWAL_HOLD', 'DEMAT_OUT',
+        'BROKERAGE', 'STT', 'GST', 'EXCHANGE_CHARGES', 'SEBI_CHARGES', 'STAMP_DUTY'));
+
+INSERT INTO ledger_accounts (code, name, account_type) VALUES
+    ('BROKERAGE_EXPENSE', 'Brokerage', 'EXPENSE'),
+    ('STT_EXPENSE', 'Securities transaction tax', 'EXPENSE'),
+    ('GST_EXPENSE', 'GST on brokerage', 'EXPENSE'),
+    ('EXCHANGE_CHARGES_EXPENSE', 'Exchange transaction charges', 'EXPENSE'),
+    ('SEBI_CHARGES_EXPENSE', 'SEBI turnover fees', 'EXPENSE'),
+    ('STAMP_DUTY_EXPENSE', 'Stamp duty', 'EXPENSE')
+ON CONFLICT (code) DO NOTHING;
+
+-- Itemised fees charged on each broker fill
+ALTER TABLE broker_fills ADD COLUMN IF NOT EXISTS brokerage NUMERIC(18,4) NOT NULL DEFAULT 0;
+ALTER TABLE broker_fills ADD COLUMN IF NOT EXISTS stt NUMERIC(18,4) NOT NULL DEFAULT 0;
+ALTER TABLE broker_fills ADD COLUMN IF NOT EXISTS gst NUMERIC(18,4) NOT NULL DEFAULT 0;
+ALTER TABLE broker_fills ADD COLUMN IF NOT EXISTS exchange_charges NUMERIC(18,4) NOT NULL DEFAULT 0;
+ALTER TABLE broker_fills ADD COLUMN IF NOT EXISTS sebi_charges NUMERIC(18,4) NOT NULL DEFAULT 0;
+ALTER TABLE broker_fills ADD COLUMN IF NOT EXISTS stamp_duty NUMERIC(18,4) NOT NULL DEFAULT 0;
+
+-- Fills recorded before itemisation carry their whole fee as brokerage
+UPDATE broker_fills SET brokerage = fees
+WHERE fees <> 0 AND brokerage = 0 AND stt = 0 AND gst = 0
+    AND exchange_charges = 0 AND sebi_charges = 0 AND stamp_duty = 0;
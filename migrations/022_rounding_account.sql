@@ -0,0 +1,10 @@
+-- Postings are rounded to ledger precision; residuals go to a rounding account so every event balances exactly
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_entry_type_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_entry_type_check
+    CHECK (entry_type IN ('STOCK', 'CASH', 'FEE', 'REWARD_EXPENSE', 'SETTLEMENT', 'INVENTORY', 'CUSTODY', 'EXECUTION_VARIANCE',
+        'WALLET', 'SALE_CHARGES', 'GST_PAYABLE', 'CHARGES_PAYABLE', 'BUYBACK_GAIN_LOSS', 'WITHDRAWAL_HOLD', 'DEMAT_OUT',
+        'BROKERAGE', 'STT', 'GST', 'EXCHANGE_CHARGES', 'SEBI_CHARGES', 'STAMP_DUTY', 'ROUNDING'));
+
+INSERT INTO ledger_accounts (code, name, account_type) VALUES
+    ('ROUNDING_DIFFERENCE', 'Rounding differences', 'EXPENSE')
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,27 @@
+-- Monthly accounting periods (IST) and the account balances snapshotted when a period is closed
+CREATE TABLE IF NOT EXISTS accounting_periods (
+    id UUID PRIMARY KEY,
+    period VARCHAR(7) NOT NULL UNIQUE,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'CLOSED', 'LOCKED')),
+    closed_at TIMESTAMP,
+    closed_by VARCHAR(100),
+    locked_at TIMESTAMP,
+    locked_by VARCHAR(100),
+    reopened_at TIMESTAMP,
+    reopened_by VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE TABLE IF NOT EXISTS accounting_period_balances (
+    period_id UUID NOT NULL REFERENCES accounting_periods(id),
+    account_id UUID NOT NULL REFERENCES ledger_accounts(id),
+    opening_debit NUMERIC(18,4) NOT NULL,
+    opening_credit NUMERIC(18,4) NOT NULL,
+    period_debit NUMERIC(18,4) NOT NULL,
+    period_credit NUMERIC(18,4) NOT NULL,
+    closing_balance NUMERIC(18,4) NOT NULL,
+    PRIMARY KEY (period_id, account_id)
+);
@@ -0,0 +1,95 @@
+-- Share holdings per user and symbol, maintained with every stock posting
+CREATE TABLE IF NOT EXISTS holding_balances (
+    user_id UUID NOT NULL REFERENCES users(id),
+    symbol VARCHAR(20) NOT NULL,
+    quantity NUMERIC(18,6) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, symbol)
+);
+
+-- Every change to a holding. effective_at is when the change counts towards holdings, e.g. a reward's event time.
+CREATE TABLE IF NOT EXISTS holding_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    symbol VARCHAR(20) NOT NULL,
+    quantity NUMERIC(18,6) NOT NULL,
+    reference_id UUID NOT NULL,
+    entry_id UUID REFERENCES ledger_entries(id),
+    effective_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_holding_movements_user ON holding_movements(user_id, symbol, effective_at);
+CREATE INDEX IF NOT EXISTS idx_holding_movements_effective ON holding_movements(effective_at);
+
+-- End-of-day (IST) holdings. A day is complete once it is in holding_snapshot_days.
+CREATE TABLE IF NOT EXISTS holding_snapshot_days (
+    snapshot_date DATE PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS holding_snapshots (
+    user_id UUID NOT NULL REFERENCES users(id),
+    snapshot_date DATE NOT NULL REFERENCES holding_snapshot_days(snapshot_date),
+    symbol VARCHAR(20) NOT NULL,
+    quantity NUMERIC(18,6) NOT NULL,
+    PRIMARY KEY (user_id, snapshot_date, symbol)
+);
+
+-- Backfill movements from existing rewards, reversals, gifts, sales and withdrawals
+INSERT INTO holding_movements (user_id, symbol, quantity, reference_id, effective_at, created_at)
+SELECT user_id, symbol, quantity, reference_id, effective_at, NOW()
+FROM (
+    SELECT user_id, stock_symbol AS symbol, quantity, event_id AS reference_id, timestamp AS effective_at
+    FROM reward_events
+    WHERE status IN ('BOOKED', 'ORDERED', 'SETTLED')
+    UNION ALL
+    SELECT re.user_id, re.stock_symbol, -rr.quantity, re.event_id, rr.created_at
+    FROM reward_reversals rr
+    JOIN reward_events re ON re.event_id = rr.event_id
+    WHERE re.status IN ('BOOKED', 'ORDERED', 'SETTLED')
+    UNION ALL
+    SELECT to_user_id, stock_symbol, quantity, id, created_at
+    FROM share_transfers
+    UNION ALL
+    SELECT from_user_id, stock_symbol, -quantity, id, created_at
+    FROM share_transfers
+    UNION ALL
+    SELECT user_id, stock_symbol, -quantity, id, created_at
+    FROM share_sales
+    UNION ALL
+    SELECT user_id, stock_symbol, -quantity, id, created_at
+    FROM demat_withdrawals
+    WHERE status <> 'FAILED'
+) history
+WHERE NOT EXISTS (SELECT 1 FROM holding_movements);
+
+INSERT INTO holding_balances (user_id, symbol, quantity, updated_at)
+SELECT user_id, symbol, SUM(quantity), NOW()
+FROM holding_movements
+GROUP BY user_id, symbol
+ON CONFLICT (user_id, symbol) DO NOTHING;
+
+-- Link backfilled holding movements to their stock postings so statements can show quantities.
+-- Only unambiguous pairs are linked; the rest keep no entry.
+UPDATE holding_movements hm
+SET entry_id = le.id, created_at = le.created_at
+FROM ledger_entries le
+WHERE hm.entry_id IS NULL
+    AND le.event_id = hm.reference_id
+    AND le.user_id = hm.user_id
+    AND le.symbol = hm.symbol
+    AND le.entry_type = 'STOCK'
+    AND ((hm.quantity > 0 AND le.credit > 0) OR (hm.quantity < 0 AND le.debit > 0))
+    AND (
+        SELECT COUNT(*) FROM ledger_entries x
+        WHERE x.event_id = le.event_id AND x.user_id = le.user_id AND x.symbol = le.symbol
+            AND x.entry_type = 'STOCK' AND (x.credit > 0) = (le.credit > 0)
+    ) = 1
+    AND (
+        SELECT COUNT(*) FROM holding_movements y
+        WHERE y.entry_id IS NULL AND y.reference_id = hm.reference_id AND y.user_id = hm.user_id
+            AND y.symbol = hm.symbol AND SIGN(y.quantity) = SIGN(hm.quantity)
+    ) = 1;
+
+CREATE INDEX IF NOT EXISTS idx_holding_movements_entry ON holding_movements(entry_id) WHERE entry_id IS NOT NULL;
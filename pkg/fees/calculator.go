@@ -4,26 +4,81 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-func CalculateFees(stockPrice, quantity decimal.Decimal) decimal.Decimal {
-	transactionValue := stockPrice.Mul(quantity)
+type Component string
 
-	brokerage := transactionValue.Mul(decimal.NewFromFloat(0.0003))
-	if brokerage.LessThan(decimal.NewFromInt(20)) {
-		brokerage = decimal.NewFromInt(20)
-	}
+const (
+	ComponentBrokerage       Component = "BROKERAGE"
+	ComponentSTT             Component = "STT"
+	ComponentGST             Component = "GST"
+	ComponentExchangeCharges Component = "EXCHANGE_CHARGES"
+	ComponentSEBICharges     Component = "SEBI_CHARGES"
+	ComponentStampDuty       Component = "STAMP_DUTY"
+)
 
-	stt := transactionValue.Mul(decimal.NewFromFloat(0.00025))
+type Breakdown struct {
+	Brokerage       decimal.Decimal `json:"brokerage"`
+	STT             decimal.Decimal `json:"stt"`
+	GST             decimal.Decimal `json:"gst"`
+	ExchangeCharges decimal.Decimal `json:"exchange_charges"`
+	SEBICharges     decimal.Decimal `json:"sebi_charges"`
+	StampDuty       decimal.Decimal `json:"stamp_duty"`
+}
 
-	gst := brokerage.Mul(decimal.NewFromFloat(0.18))
+type Item struct {
+	Component Component
+	Amount    decimal.Decimal
+}
 
-	exchangeCharges := transactionValue.Mul(decimal.NewFromFloat(0.0000325))
+func (b Breakdown) Items() []Item {
+	return []Item{
+		{ComponentBrokerage, b.Brokerage},
+		{ComponentSTT, b.STT},
+		{ComponentGST, b.GST},
+		{ComponentExchangeCharges, b.ExchangeCharges},
+		{ComponentSEBICharges, b.SEBICharges},
+		{ComponentStampDuty, b.StampDuty},
+	}
+}
 
-	sebiCharges := transactionValue.Mul(decimal.NewFromFloat(0.000001))
+func (b Breakdown) Total() decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range b.Items() {
+		total = total.Add(item.Amount)
+	}
+	return total
+}
 
-	stampDuty := transactionValue.Mul(decimal.NewFromFloat(0.00003))
+func (b Breakdown) Add(other Breakdown) Breakdown {
+	return Breakdown{
+		Brokerage:       b.Brokerage.Add(other.Brokerage),
+		STT:             b.STT.Add(other.STT),
+		GST:             b.GST.Add(other.GST),
+		ExchangeCharges: b.ExchangeCharges.Add(other.ExchangeCharges),
+		SEBICharges:     b.SEBICharges.Add(other.SEBICharges),
+		StampDuty:       b.StampDuty.Add(other.StampDuty),
+	}
+}
 
-	totalFees := brokerage.Add(stt).Add(gst).Add(exchangeCharges).Add(sebiCharges).Add(stampDuty)
+func CalculateFees(stockPrice, quantity decimal.Decimal) Breakdown {
+	return calculate(stockPrice.Mul(quantity), decimal.NewFromFloat(0.00025), decimal.NewFromFloat(0.00003))
+}
 
-	return totalFees.Round(2)
+func CalculateBuybackFees(stockPrice, quantity decimal.Decimal) Breakdown {
+	return calculate(stockPrice.Mul(quantity), decimal.NewFromFloat(0.001), decimal.Zero)
 }
 
+func calculate(transactionValue, sttRate, stampDutyRate decimal.Decimal) Breakdown {
+	brokerage := transactionValue.Mul(decimal.NewFromFloat(0.0003))
+	if brokerage.LessThan(decimal.NewFromInt(20)) {
+		brokerage = decimal.NewFromInt(20)
+	}
+
+	return Breakdown{
+		Brokerage:       brokerage.Round(2),
+		STT:             transactionValue.Mul(sttRate).Round(2),
+		GST:             brokerage.Mul(decimal.NewFromFloat(0.18)).Round(2),
+		ExchangeCharges: transactionValue.Mul(decimal.NewFromFloat(0.0000325)).Round(2),
+		SEBICharges:     transactionValue.Mul(decimal.NewFromFloat(0.000001)).Round(2),
+		StampDuty:       transactionValue.Mul(stampDutyRate).Round(2),
+	}
+}
@@ -0,0 +1,103 @@
+package fees
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCalculate(t *testing.T) {
+	d := decimal.RequireFromString
+
+	tests := []struct {
+		name      string
+		calculate func(price, quantity decimal.Decimal) Breakdown
+		price     string
+		quantity  string
+		want      Breakdown
+		total     string
+	}{
+		{
+			name:      "minimum brokerage",
+			calculate: CalculateFees,
+			price:     "2500",
+			quantity:  "1",
+			want:      Breakdown{Brokerage: d("20"), STT: d("0.63"), GST: d("3.6"), ExchangeCharges: d("0.08"), SEBICharges: d("0"), StampDuty: d("0.08")},
+			total:     "24.39",
+		},
+		{
+			name:      "fractional quantity",
+			calculate: CalculateFees,
+			price:     "1234.5",
+			quantity:  "0.5",
+			want:      Breakdown{Brokerage: d("20"), STT: d("0.15"), GST: d("3.6"), ExchangeCharges: d("0.02"), SEBICharges: d("0"), StampDuty: d("0.02")},
+			total:     "23.79",
+		},
+		{
+			name:      "just above minimum brokerage",
+			calculate: CalculateFees,
+			price:     "667",
+			quantity:  "100",
+			want:      Breakdown{Brokerage: d("20.01"), STT: d("16.68"), GST: d("3.6"), ExchangeCharges: d("2.17"), SEBICharges: d("0.07"), StampDuty: d("2")},
+			total:     "44.53",
+		},
+		{
+			name:      "percentage brokerage",
+			calculate: CalculateFees,
+			price:     "2000",
+			quantity:  "100",
+			want:      Breakdown{Brokerage: d("60"), STT: d("50"), GST: d("10.8"), ExchangeCharges: d("6.5"), SEBICharges: d("0.2"), StampDuty: d("6")},
+			total:     "133.5",
+		},
+		{
+			name:      "buyback with minimum brokerage",
+			calculate: CalculateBuybackFees,
+			price:     "2500",
+			quantity:  "1",
+			want:      Breakdown{Brokerage: d("20"), STT: d("2.5"), GST: d("3.6"), ExchangeCharges: d("0.08"), SEBICharges: d("0")},
+			total:     "26.18",
+		},
+		{
+			name:      "buyback with percentage brokerage",
+			calculate: CalculateBuybackFees,
+			price:     "2000",
+			quantity:  "100",
+			want:      Breakdown{Brokerage: d("60"), STT: d("200"), GST: d("10.8"), ExchangeCharges: d("6.5"), SEBICharges: d("0.2")},
+			total:     "277.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.calculate(d(tt.price), d(tt.quantity))
+
+			wantItems := tt.want.Items()
+			sum := decimal.Zero
+			for i, item := range got.Items() {
+				if !item.Amount.Equal(wantItems[i].Amount) {
+					t.Errorf("%s = %s, want %s", item.Component, item.Amount, wantItems[i].Amount)
+				}
+				sum = sum.Add(item.Amount)
+			}
+			if !got.Total().Equal(d(tt.total)) {
+				t.Errorf("Total() = %s, want %s", got.Total(), tt.total)
+			}
+			if !got.Total().Equal(sum) {
+				t.Errorf("Total() = %s, components sum to %s", got.Total(), sum)
+			}
+		})
+	}
+}
+
+func TestBreakdownAdd(t *testing.T) {
+	a := CalculateFees(decimal.NewFromInt(2500), decimal.NewFromInt(1))
+	b := CalculateBuybackFees(decimal.NewFromInt(2000), decimal.NewFromInt(100))
+
+	got := a.Add(b)
+	if !got.Total().Equal(a.Total().Add(b.Total())) {
+		t.Fatalf("Add().Total() = %s, want %s", got.Total(), a.Total().Add(b.Total()))
+	}
+	if !got.Brokerage.Equal(decimal.NewFromInt(80)) {
+		t.Fatalf("Add().Brokerage = %s, want 80", got.Brokerage)
+	}
+}